@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+)
+
+// ControlServer serves the endpoint attaching, detaching, pausing and resuming the
+// probes of the handler at runtime.
+func ControlServer(addr string, handler *ebpf.Handler) *http.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /probes", func(w http.ResponseWriter, r *http.Request) {
+		writeProbes(w, handler)
+	})
+
+	mux.HandleFunc("POST /probes/{name}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		actions := map[string]func(string) error{
+			"attach": handler.Attach,
+			"detach": handler.Detach,
+			"pause":  handler.Pause,
+			"resume": handler.Resume,
+		}
+
+		action, ok := actions[r.PathValue("action")]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown action %q, expected attach, detach, pause or resume", r.PathValue("action")), http.StatusNotFound)
+			return
+		}
+
+		if err := action(r.PathValue("name")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("probe %s: %s", r.PathValue("name"), r.PathValue("action"))
+		writeProbes(w, handler)
+	})
+
+	return &http.Server{Addr: addr, Handler: mux}
+}
+
+// writeProbes writes the state of the probes of the handler as JSON.
+func writeProbes(w http.ResponseWriter, handler *ebpf.Handler) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(handler.Probes()); err != nil {
+		log.Print(err)
+	}
+}
@@ -11,4 +11,140 @@
 // The package uses the Kubernetes client-go library to interact with the Kubernetes API server. It retrieves
 // the Kubernetes context for a given process by finding the pod associated with the container ID of the process.
 // The Kubernetes context includes information about the pod, the container, and the namespace.
+//
+// Flags:
+//
+//	-syscall-backend string
+//		hooks used to capture syscalls, kprobe (default), raw_tracepoint or fexit. kprobe and raw_tracepoint
+//		produce identical events, fexit writes one event with the arguments and the return value of a syscall
+//		and falls back to kprobe on kernels without BPF trampolines. With raw_tracepoint, the sys_enter and
+//		sys_exit tracepoints are always attached and call the handlers of the syscalls selected, which
+//		-control-addr attaches and detaches by syscall like the kprobes.
+//	-control-addr string
+//		address of the endpoint controlling the probes at runtime, e.g. localhost:8090, disabled if empty.
+//		GET /probes lists the probes with their number of programs, attached programs and whether they are
+//		paused. POST /probes/{name}/attach and /detach attach and detach the programs of a probe, including
+//		those not selected by -probes; POST /probes/{name}/pause and /resume drop and restore its events
+//		in the kernel, with its programs left attached.
+//	-correlate duration
+//		time the entry and exit events of a syscall wait for each other to be merged into one record, 0 (default)
+//		to not merge them. The record, e.g. sys_openat for sys_openat_entry and sys_openat_exit, is named like
+//		the events of the fexit backend, with the arguments of both events, and adds the return value, the
+//		errno name, empty when the syscall succeeded, and the duration in nanoseconds. The events of a thread
+//		are matched by its host thread ID; those left without their other half are printed as they are, and
+//		counted in the summary printed on exit.
+//	-fd-table-size int
+//		number of processes whose file descriptors are tracked, 0 (default) to not resolve the file
+//		descriptors, e.g. 32768. The fds returned by the open, openat, openat2, socket, accept and accept4 events, and
+//		duplicated by dup, dup2, dup3 and fcntl F_DUPFD, are tracked until their close, or the exec of the
+//		process for the close-on-exec ones, with the path of the file, or the family, type, protocol and the
+//		addresses given by bind, connect and accept of the socket; the events with an fd argument, e.g. read,
+//		write or connect, get a descriptor field with what it refers to. A forked child inherits the fds of its
+//		parent, and the fds open before the detector started are read by -proc-dir. The fds of pipe and
+//		socketpair are not known. It requires -reorder-window.
+//	-flows
+//		record the connections of the processes into flow events, requires -fd-table-size. A flow event, marked
+//		synthetic, is returned when a socket connected, accepted or listened on is closed, or its process exits,
+//		with the metadata and the Kubernetes context of the process, the direction (outbound, inbound or
+//		listen), the family, type and protocol of the socket, its local and remote addresses, the start and end
+//		kernel timestamps and the bytes read and written, telling which pod talked to what. The flows not read
+//		in time are dropped and counted, in the summary printed on exit.
+//	-health-addr string
+//		address of the liveness and readiness endpoints, e.g. :8080, disabled if empty. GET /healthz fails,
+//		with the 503 status code, once events are queued and none was read for longer than -stall-timeout,
+//		so that Kubernetes restarts a detector whose reading loop is stuck. GET /readyz fails until the
+//		programs selected by -probes are attached, the map readers are open, the events are read and the
+//		cache of the pods is synced; the probes detached at runtime do not make the detector unready.
+//	-latency-interval duration
+//		interval at which the syscall_latency summary event is printed, 0 (default) to not print it. The
+//		durations of the syscalls merged by -correlate are counted in histograms by syscall and container,
+//		exported by -metrics-addr as syscall_latency_seconds; the event gives, for the interval, the number,
+//		mean, median, 99th percentile and longest durations of every syscall in every container, e.g. to spot
+//		the openat calls slow on a network file system or a hung connect. It requires -correlate, without which
+//		the syscalls are not merged and syscall_latency_seconds stays empty.
+//	-list-probes
+//		list the probes, with their group and whether the -probes selection attaches them, and exit. The lsm,
+//		tls and readline probes are only loaded when enabled by their own flags.
+//	-lsm-policies string
+//		JSON file of the LSM policies reported, and denied if enforced, none if empty. The file is an array of
+//		policies with an id, the hook, bprm_check_security, file_open or socket_connect, the path of the file
+//		executed or opened, or the addr and port connected to, an addr left out matching any address, and
+//		enforce, e.g.
+//
+//			[{"id": 1, "hook": "bprm_check_security", "path": "/usr/bin/nc", "enforce": true},
+//			 {"id": 2, "hook": "socket_connect", "addr": "169.254.169.254", "port": 80}]
+//
+//		The paths are of the files of the host, resolved through the root of its init process in /host/proc.
+//		Every match is an event carrying the id of the policy. The enforced policies deny the operation with
+//		EPERM when the bpf LSM is active, and are only reported otherwise.
+//	-metrics-addr string
+//		address of the Prometheus metrics endpoint, e.g. :9090, disabled if empty. GET /metrics exposes the
+//		events read by event, the parse errors, the enrichment failures, the queue depth, the events dropped,
+//		late and unmatched in userspace, the syscall latency histograms, the samples lost by the perf buffers,
+//		the tarian_stats kernel counters and the programs of every probe attached, under the tarian_detector
+//		namespace.
+//	-ordering string
+//		order of the events parsed by the -workers (default thread): unordered, cpu or thread. The events of a
+//		processor, or of a thread, are parsed by the same worker and keep the order in which they were read;
+//		unordered balances the events across the workers regardless of their origin.
+//	-overflow string
+//		policy applied when the event queue is full (default block): block, drop-newest, drop-oldest or
+//		priority. With block the readers wait and the kernel drops the events it can not buffer meanwhile,
+//		silently; the other policies drop events in userspace and count them by event, in the summary
+//		printed on exit. priority drops the read and write events once the queue is 3/4 full, and blocks
+//		for the others, so the process, file and network events are kept.
+//	-probes string
+//		comma separated probes or probe groups to attach, all if empty. Probes are named after their syscall,
+//		e.g. read, or their event, e.g. ssl_write; the groups are process, file, network and io. The programs
+//		of the other probes are loaded but not attached, e.g. -probes process,network drops read and write.
+//	-proc-dir string
+//		procfs of the host read at startup to snapshot the processes running, e.g. /host/proc, empty (default)
+//		to not snapshot them. Every process gets a process_snapshot event, marked synthetic, with the metadata
+//		the eBPF programs give the events, its executable, its arguments and its open file descriptors; the
+//		process tree is seeded with them, so the ancestry of the processes started before the detector is
+//		known.
+//	-process-table-size int
+//		number of processes the process tree holds, 0 (default) to not attach the ancestry of the processes,
+//		e.g. 32768. The processes are tracked by the execId and parentExecId of the events, with the binary
+//		and the arguments of their last successful execve, or of their parent for a forked child; every
+//		event gets an ancestry field with the binary, arguments, host pid and uid of the ancestors of its
+//		process, from its parent. The sched_process_fork, sched_process_exec and sched_process_exit events
+//		add the children, update the binary and remove the exited processes, kept until their last child is
+//		removed so that orphaned children keep their ancestry; the least recently seen processes are evicted
+//		first. It requires -reorder-window.
+//	-queue-size int
+//		number of events read and not yet parsed the detector holds (default 131072).
+//	-reorder-window duration
+//		time the events are held to be sorted by their kernel timestamp, 0 (default) to not sort them, e.g.
+//		100ms. The events of the processors are read from separate buffers, so an event may be read before
+//		one captured earlier on another processor, e.g. a connect before the socket call creating its fd. The
+//		events arriving after the window are counted as late, in the summary printed on exit. The process and
+//		fd tables follow the events once sorted, and merged by -correlate, so they require the window.
+//	-ring-buffer-size int
+//		size in bytes of the ring buffers of all the cpus together (default 67108864), split between them, on
+//		kernels supporting BPF ring buffers. The buffer of a cpu is rounded down to a power of two and holds at
+//		least two of the largest events, 128kB; the events the kernel can not buffer are dropped and counted
+//		as n_trgs_dropped_max_map_capacity, see -stats-interval.
+//	-shell-capture
+//		capture the lines read by the interactive shells using GNU readline, bash or those linking libreadline,
+//		including the builtins and the commands typed in a kubectl exec session. Combined with the Kubernetes
+//		context of the events, it gives the timeline of the commands run in a pod.
+//	-stall-timeout duration
+//		time the queued events may go unread before /healthz fails (default 30s), see -health-addr.
+//	-stats-interval duration
+//		interval at which the counters are logged, 0 (default) to log them on exit only. The kernel counters of
+//		tarian_stats tell the events triggered, sent and dropped by the eBPF programs, e.g. n_trgs_dropped_max_map_capacity
+//		when the perf or ring buffer is full, lost_samples the samples the kernel could not write to the perf
+//		buffers, and dropped the events dropped in userspace by the -overflow policy.
+//	-tls-capture
+//		capture the plaintext of the OpenSSL (libssl) and Go crypto/tls connections. The functions of the
+//		stripped Go executables are found in their Go function table.
+//	-uprobe-refresh duration
+//		interval at which the libraries and shells of new processes are discovered (default 10s). The files
+//		used by -shell-capture and -tls-capture are discovered in the running processes, including those of
+//		the containers, when the detector starts and then on every refresh, which detaches the probes of
+//		the files no longer used by any process.
+//	-workers int
+//		number of goroutines parsing the events and retrieving their Kubernetes context, 0 (default) to do
+//		it on the reading loop. On nodes with many processors a single loop can not keep up with the events.
 package main
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/intelops/tarian-detector/pkg/detector"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/intelops/tarian-detector/pkg/k8s"
+)
+
+// HealthServer serves the liveness and readiness endpoints of the detector. GET /healthz fails once
+// events are queued and none was read for longer than stallTimeout. GET /readyz fails until the
+// programs are attached, the map readers are open, the event detector is started and the cache of
+// the pods is synced, when the Kubernetes watcher is enabled.
+func HealthServer(addr string, d *detector.EventsDetector, h *ebpf.Handler, watcher *k8s.PodWatcher, stallTimeout time.Duration) *http.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, d.Live(stallTimeout))
+	})
+
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, h.Ready(), d.Ready(), podWatcherReady(watcher))
+	})
+
+	return &http.Server{Addr: addr, Handler: mux}
+}
+
+// podWatcherReady returns an error unless the cache of the pods is synced. Outside of Kubernetes,
+// without watcher, it is always ready.
+func podWatcherReady(watcher *k8s.PodWatcher) error {
+	if watcher != nil && !watcher.HasSynced() {
+		return fmt.Errorf("kubernetes pod cache not synced")
+	}
+
+	return nil
+}
+
+// writeHealth writes ok, or the failing checks with the 503 status code.
+func writeHealth(w http.ResponseWriter, checks ...error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	failed := false
+	for _, err := range checks {
+		if err == nil {
+			continue
+		}
+
+		if !failed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			failed = true
+		}
+
+		fmt.Fprintln(w, err)
+	}
+
+	if !failed {
+		fmt.Fprintln(w, "ok")
+	}
+}
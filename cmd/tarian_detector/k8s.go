@@ -92,3 +92,14 @@ func GetK8sContext(watcher *k8s.PodWatcher, processId uint32) (K8sContext, error
 
 	return k8sCtx, nil
 }
+
+// containerID returns the ID of the container of the process of an event, from its Kubernetes context.
+// It is empty for the processes of the host and outside of Kubernetes.
+func containerID(event map[string]any) string {
+	k8sCtx, ok := event["kubernetes"].(K8sContext)
+	if !ok {
+		return ""
+	}
+
+	return k8sCtx.ContainerID
+}
@@ -4,14 +4,24 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/intelops/tarian-detector/pkg/detector"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/intelops/tarian-detector/pkg/process"
 	"github.com/intelops/tarian-detector/pkg/utils"
 	"github.com/intelops/tarian-detector/tarian"
 )
@@ -19,9 +29,71 @@ import (
 // main is the entry point of the application. It sets up the necessary components
 // and starts the main event loop.
 func main() {
-	// Create a channel to listen for interrupt signals (Ctrl+C or SIGTERM)
-	stopper := make(chan os.Signal, 1)
-	signal.Notify(stopper, os.Interrupt, syscall.SIGTERM)
+	syscallBackend := flag.String("syscall-backend", tarian.KprobeBackend.String(),
+		fmt.Sprintf("hooks used to capture syscalls: %s, %s or %s", tarian.KprobeBackend, tarian.RawTracepointBackend, tarian.FexitBackend))
+	tlsCapture := flag.Bool("tls-capture", false, "capture the plaintext of the OpenSSL and Go crypto/tls connections")
+	shellCapture := flag.Bool("shell-capture", false, "capture the lines read by the interactive shells using readline, e.g. bash")
+	uprobeRefresh := flag.Duration("uprobe-refresh", 10*time.Second, "interval at which the libraries and shells of new processes are discovered")
+	lsmPolicies := flag.String("lsm-policies", "", "JSON file of the LSM policies reported, and denied if enforced, none if empty")
+	probes := flag.String("probes", "", "comma separated probes or probe groups to attach, all if empty")
+	listProbes := flag.Bool("list-probes", false, "list the probes, with their group and whether they are attached, and exit")
+	workers := flag.Int("workers", 0, "number of goroutines parsing and enriching the events, 0 to parse them on the reading loop")
+	ordering := flag.String("ordering", detector.PerThreadOrder.String(),
+		fmt.Sprintf("order of the events parsed by the workers: %s, %s or %s", detector.Unordered, detector.PerCpuOrder, detector.PerThreadOrder))
+	queueSize := flag.Int("queue-size", 8192*16, "number of events read and not yet parsed the detector holds")
+	overflow := flag.String("overflow", detector.Block.String(),
+		fmt.Sprintf("policy applied when the event queue is full: %s, %s, %s or %s", detector.Block, detector.DropNewest, detector.DropOldest, detector.DropLowPriority))
+	ringBufferSize := flag.Int("ring-buffer-size", tarian.DefaultRingBufferSize, "size in bytes of the ring buffers of all the cpus together, split between them")
+	reorderWindow := flag.Duration("reorder-window", 0, "time the events are held to be sorted by their kernel timestamp, 0 to not sort them, required by the process and fd tables")
+	correlate := flag.Duration("correlate", 0, "time the entry and exit events of a syscall wait for each other to be merged into one record, 0 to not merge them")
+	latencyInterval := flag.Duration("latency-interval", 0, "interval at which the syscall latency summary event is printed, 0 to not print it, requires -correlate")
+	fdTableSize := flag.Int("fd-table-size", 0, "number of processes whose file descriptors are tracked, 0 to not resolve the file descriptors")
+	flowRecords := flag.Bool("flows", false, "record the connections of the processes into flow events, requires -fd-table-size")
+	procDir := flag.String("proc-dir", "", "procfs of the host read at startup to snapshot the processes running, empty to not snapshot them")
+	processTableSize := flag.Int("process-table-size", 0, "number of processes the process tree holds, 0 to not attach the ancestry of the processes")
+	statsInterval := flag.Duration("stats-interval", 0, "interval at which the kernel and userspace counters are logged, 0 to log them on exit only")
+	metricsAddr := flag.String("metrics-addr", "", "address of the Prometheus metrics endpoint, e.g. :9090, disabled if empty")
+	healthAddr := flag.String("health-addr", "", "address of the /healthz and /readyz endpoints, e.g. :8080, disabled if empty")
+	stallTimeout := flag.Duration("stall-timeout", 30*time.Second, "time the queued events may go unread before /healthz fails")
+	controlAddr := flag.String("control-addr", "", "address of the endpoint controlling the probes at runtime, e.g. localhost:8090, disabled if empty")
+	flag.Parse()
+
+	backend, err := tarian.ParseSyscallBackend(*syscallBackend)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	order, err := detector.ParseOrdering(*ordering)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	policy, err := detector.ParseOverflowPolicy(*overflow)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	selection, err := tarian.ParseProbeSelection(*probes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *latencyInterval > 0 && *correlate == 0 {
+		log.Fatal("-latency-interval requires -correlate, the latency is measured on the merged syscall records")
+	}
+
+	if *reorderWindow == 0 && (*processTableSize > 0 || *fdTableSize > 0) {
+		log.Fatal("-process-table-size and -fd-table-size require -reorder-window, the tables follow the events in order")
+	}
+
+	if *listProbes {
+		printProbes(os.Stdout, selection)
+		os.Exit(0)
+	}
+
+	// Stop the detector on interrupt signals (Ctrl+C or SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Initialize and start the Kubernetes watcher
 	watcher, err := K8Watcher()
@@ -31,8 +103,24 @@ func main() {
 		watcher.Start()
 	}
 
+	// Read the LSM policies
+	var policies []tarian.LsmPolicy
+	if *lsmPolicies != "" {
+		policies, err = tarian.ReadLsmPolicies(*lsmPolicies)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// Initialize Tarian eBPF module
-	tarianEbpfModule, err := tarian.GetModule()
+	tarianEbpfModule, err := tarian.GetModule(tarian.Options{
+		SyscallBackend: backend,
+		LsmPolicies:    policies,
+		TlsCapture:     *tlsCapture,
+		ShellCapture:   *shellCapture,
+		Probes:         selection,
+		RingBufferSize: *ringBufferSize,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -43,59 +131,258 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Instantiate the event detectors
-	eventsDetector := detector.NewEventsDetector()
+	// Snapshot the processes running, once the programs are attached so that no process is missed
+	var snapshot *process.Snapshot
+	if *procDir != "" {
+		snapshot, err = process.ReadSnapshot(*procDir)
+		if err != nil {
+			log.Printf("processes not snapshotted: %v\n", err)
+		}
+	}
+
+	// Instantiate the event detectors, the events are parsed and enriched by the workers, then sorted, merged
+	// and tracked by the process and fd tables
+	eventsDetector := detector.NewEventsDetector().
+		QueueSize(*queueSize, policy).
+		Workers(*workers, order).
+		Reorder(*reorderWindow).
+		Correlate(*correlate)
+
+	// Record the latency of the merged syscalls
+	if *correlate > 0 {
+		eventsDetector.Latency(*latencyInterval, containerID)
+	}
+
+	// Retrieve Kubernetes context based on host process ID
+	eventsDetector.Enrich(func(e map[string]any) error {
+		k8sCtx, err := GetK8sContext(watcher, e["hostProcessId"].(uint32))
+		if err != nil {
+			// Log the error as the Kubernetes context if an error is
+			e["kubernetes"] = err.Error()
+
+			// outside of Kubernetes the context is not a failure
+			if watcher == nil {
+				return nil
+			}
 
-	// Add the eBPF module to the detectors
+			return err
+		}
+
+		// Set the Kubernetes context if no error is encountered
+		e["kubernetes"] = k8sCtx
+
+		return nil
+	})
+
+	// Attach the ancestry of the process of the events
+	if *processTableSize > 0 {
+		tree := process.NewTree(*processTableSize)
+		if snapshot != nil {
+			tree.Seed(snapshot.Processes())
+		}
+
+		eventsDetector.Track(tree.Enrich)
+	}
+
+	// Attach what the file descriptors of the events refer to, and record the connections
+	var flows *process.Flows
+	if *fdTableSize > 0 {
+		fds := process.NewFdTable(*fdTableSize)
+		if snapshot != nil {
+			fds.Seed(snapshot.Processes())
+		}
+
+		if *flowRecords {
+			flows = process.NewFlows(process.DefaultFlowQueueSize)
+			fds.RecordFlows(flows)
+		}
+
+		eventsDetector.Track(fds.Enrich)
+	}
+
+	// Add the eBPF module, the snapshot and the flows to the detectors
 	eventsDetector.Add(tarianDetector)
+	if snapshot != nil {
+		eventsDetector.Add(snapshot)
+	}
+	if flows != nil {
+		eventsDetector.Add(flows)
+	}
+
+	// Start the event detectors, they are stopped once ctx is cancelled and the probes are no longer refreshed
+	detectorCtx, stopDetectors := context.WithCancel(context.Background())
+	defer stopDetectors()
 
-	// Start the event detectors and defer their closure
-	err = eventsDetector.Start()
+	err = eventsDetector.Start(detectorCtx)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer eventsDetector.Close()
 
 	log.Printf("%d probes running...\n\n", eventsDetector.Count())
 
-	go func() {
-		<-stopper // Wait for an interrupt signal
+	// Attach the probes of the libraries and shells used by newly started processes
+	var refreshing sync.WaitGroup
+	if *tlsCapture || *shellCapture {
+		refreshing.Add(1)
+		go func() {
+			defer refreshing.Done()
 
-		eventsDetector.Close()
-		log.Printf("Total records captured : %d\n", eventsDetector.GetTotalCount())
-		count := 1
-		for ky, vl := range eventsDetector.GetProbeCount() {
-			fmt.Printf("%d. %s: %d\n", count, ky, vl)
-			count++
-		}
-		os.Exit(0)
-	}()
+			ticker := time.NewTicker(*uprobeRefresh)
+			defer ticker.Stop()
 
-	// Continuously read events
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := tarianDetector.Refresh(); err != nil {
+						log.Print(err)
+					}
+				}
+			}
+		}()
+	}
+
+	// the probes attached by a refresh in progress are detached by the detectors
 	go func() {
-		for {
-			e, err := eventsDetector.ReadAsInterface()
-			if err != nil {
+		<-ctx.Done()
+		refreshing.Wait()
+		stopDetectors()
+	}()
+
+	// Log the counters periodically
+	if *statsInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(*statsInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					logStats(eventsDetector, tarianDetector)
+				}
+			}
+		}()
+	}
+
+	// Serve the Prometheus metrics
+	if len(*metricsAddr) != 0 {
+		server := MetricsServer(*metricsAddr, eventsDetector, tarianDetector)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Print(err)
+			}
+		}()
+		defer server.Close()
+	}
+
+	// Serve the liveness and readiness endpoints
+	if len(*healthAddr) != 0 {
+		server := HealthServer(*healthAddr, eventsDetector, tarianDetector, watcher, *stallTimeout)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				log.Print(err)
-				continue
 			}
+		}()
+		defer server.Close()
+	}
 
-			// Retrieve Kubernetes context based on host process ID
-			k8sCtx, err := GetK8sContext(watcher, e["hostProcessId"].(uint32))
-			if err != nil {
-				// Log the error as the Kubernetes context if an error is
-				e["kubernetes"] = err.Error()
-			} else {
-				// Set the Kubernetes context if no error is encountered
-				e["kubernetes"] = k8sCtx
+	// Serve the endpoint attaching, detaching, pausing and resuming the probes
+	if len(*controlAddr) != 0 {
+		server := ControlServer(*controlAddr, tarianDetector)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Print(err)
 			}
+		}()
+		defer server.Close()
+	}
 
-			utils.PrintEvent(e, eventsDetector.GetTotalCount())
+	// Continuously read events, until the detector is stopped and the queued events are drained
+	for {
+		e, err := eventsDetector.ReadAsInterface()
+		if errors.Is(err, io.EOF) {
+			break
 		}
-	}()
 
-	// Only for avoiding deadlock detection
-	for {
-		time.Sleep(1 * time.Minute)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+
+		utils.PrintEvent(e, eventsDetector.GetTotalCount())
 	}
+
+	if err := eventsDetector.Stop(); err != nil {
+		log.Print(err)
+	}
+
+	log.Printf("Total records captured : %d\n", eventsDetector.GetTotalCount())
+	logStats(eventsDetector, tarianDetector)
+	if eventsDetector.GetReorderWindow() > 0 {
+		log.Printf("Records arrived after the %v reorder window : %d\n", eventsDetector.GetReorderWindow(), eventsDetector.GetLateCount())
+	}
+	if eventsDetector.GetCorrelationTimeout() > 0 {
+		log.Printf("Syscall events without their entry or exit after %v : %d\n", eventsDetector.GetCorrelationTimeout(), eventsDetector.GetUnmatchedCount())
+	}
+	if flows != nil {
+		log.Printf("Flows dropped as they were not read in time : %d\n", flows.GetDropCount())
+	}
+
+	count := 1
+	for ky, vl := range eventsDetector.GetProbeCount() {
+		fmt.Printf("%d. %s: %d\n", count, ky, vl)
+		count++
+	}
+
+	if drops := eventsDetector.GetDropCount(); len(drops) != 0 {
+		log.Printf("Records dropped by the %s overflow policy :\n", policy)
+		count = 1
+		for ky, vl := range drops {
+			fmt.Printf("%d. %s: %d\n", count, ky, vl)
+			count++
+		}
+	}
+}
+
+// logStats logs the counters of the events triggered, sent and dropped in the kernel, the samples
+// lost by the perf buffers, and the events queued and dropped in userspace.
+func logStats(d *detector.EventsDetector, h *ebpf.Handler) {
+	var b strings.Builder
+	if stats, err := h.Stats(); err != nil {
+		log.Print(err)
+	} else {
+		for _, name := range tarian.StatNames {
+			fmt.Fprintf(&b, " %s=%d", name, stats[name])
+		}
+
+		log.Printf("kernel:%s", b.String())
+	}
+
+	dropped := 0
+	for _, n := range d.GetDropCount() {
+		dropped += n
+	}
+
+	log.Printf("userspace: lost_samples=%d records=%d queued=%d dropped=%d late=%d",
+		h.LostSamples(), d.GetTotalCount(), d.GetQueueDepth(), dropped, d.GetLateCount())
+}
+
+// printProbes writes the probes of the detector, with their group and whether the selection attaches them.
+func printProbes(out io.Writer, selection tarian.ProbeSelection) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROBE\tGROUP\tSTATUS")
+
+	for _, p := range tarian.Probes() {
+		status := "disabled"
+		if selection.Selects(p.Name) {
+			status = "enabled"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.Group, status)
+	}
+
+	w.Flush()
 }
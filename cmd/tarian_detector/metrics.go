@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/intelops/tarian-detector/pkg/detector"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "tarian_detector"
+
+// metricsCollector collects the metrics of the detector from its counters on every scrape.
+type metricsCollector struct {
+	detector *detector.EventsDetector
+	handler  *ebpf.Handler
+
+	records       *prometheus.Desc
+	events        *prometheus.Desc
+	parseErrors   *prometheus.Desc
+	enrichErrors  *prometheus.Desc
+	dropped       *prometheus.Desc
+	late          *prometheus.Desc
+	unmatched     *prometheus.Desc
+	latency       *prometheus.Desc
+	queueDepth    *prometheus.Desc
+	lostSamples   *prometheus.Desc
+	kernel        *prometheus.Desc
+	probePrograms *prometheus.Desc
+	probeAttached *prometheus.Desc
+	probePaused   *prometheus.Desc
+	attachedCount *prometheus.Desc
+}
+
+// newMetricsCollector returns the collector of the metrics of the detector and its eBPF handler.
+func newMetricsCollector(d *detector.EventsDetector, h *ebpf.Handler) *metricsCollector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", name), help, labels, nil)
+	}
+
+	return &metricsCollector{
+		detector: d,
+		handler:  h,
+
+		records:       desc("records_total", "Events read."),
+		events:        desc("events_total", "Events read and parsed, by event.", "event"),
+		parseErrors:   desc("parse_errors_total", "Events which could not be read or parsed."),
+		enrichErrors:  desc("enrichment_failures_total", "Events an enrichment, e.g. the Kubernetes context, failed on."),
+		dropped:       desc("dropped_events_total", "Events dropped in userspace by the overflow policy, by event.", "event"),
+		late:          desc("late_events_total", "Events arrived after the reorder window."),
+		unmatched:     desc("unmatched_syscall_events_total", "Entry and exit events of syscalls returned without their other half."),
+		latency:       desc("syscall_latency_seconds", "Durations of the syscalls merged from their entry and exit events, by syscall and container.", "syscall", "container"),
+		queueDepth:    desc("queue_depth", "Events read and not yet parsed."),
+		lostSamples:   desc("perf_lost_samples_total", "Samples the kernel could not write to the perf buffers."),
+		kernel:        desc("kernel_triggers_total", "Counters of the eBPF programs, summed across the processors, by tarian_stats counter.", "counter"),
+		probePrograms: desc("probe_programs", "Programs of the probe.", "probe"),
+		probeAttached: desc("probe_attached_programs", "Programs of the probe attached.", "probe"),
+		probePaused:   desc("probe_paused", "Whether the events of the probe are paused.", "probe"),
+		attachedCount: desc("attached_programs", "Programs attached."),
+	}
+}
+
+// Describe sends the descriptors of the metrics, see prometheus.Collector.
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+// Collect sends the metrics read from the counters of the detector, see prometheus.Collector.
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for event, n := range c.detector.GetProbeCount() {
+		ch <- prometheus.MustNewConstMetric(c.events, prometheus.CounterValue, float64(n), event)
+	}
+
+	for event, n := range c.detector.GetDropCount() {
+		ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(n), event)
+	}
+
+	for key, h := range c.detector.GetLatency() {
+		buckets := make(map[float64]uint64, len(detector.LatencyBuckets))
+
+		var n uint64
+		for i, bound := range detector.LatencyBuckets {
+			n += h.Buckets[i]
+			buckets[bound.Seconds()] = n
+		}
+
+		ch <- prometheus.MustNewConstHistogram(c.latency, h.Count, h.Sum.Seconds(), buckets, key.Syscall, key.Container)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.records, prometheus.CounterValue, float64(c.detector.GetTotalCount()))
+	ch <- prometheus.MustNewConstMetric(c.parseErrors, prometheus.CounterValue, float64(c.detector.GetParseErrorCount()))
+	ch <- prometheus.MustNewConstMetric(c.enrichErrors, prometheus.CounterValue, float64(c.detector.GetEnrichErrorCount()))
+	ch <- prometheus.MustNewConstMetric(c.late, prometheus.CounterValue, float64(c.detector.GetLateCount()))
+	ch <- prometheus.MustNewConstMetric(c.unmatched, prometheus.CounterValue, float64(c.detector.GetUnmatchedCount()))
+	ch <- prometheus.MustNewConstMetric(c.queueDepth, prometheus.GaugeValue, float64(c.detector.GetQueueDepth()))
+	ch <- prometheus.MustNewConstMetric(c.lostSamples, prometheus.CounterValue, float64(c.handler.LostSamples()))
+	ch <- prometheus.MustNewConstMetric(c.attachedCount, prometheus.GaugeValue, float64(c.handler.Count()))
+
+	if stats, err := c.handler.Stats(); err == nil {
+		for name, n := range stats {
+			ch <- prometheus.MustNewConstMetric(c.kernel, prometheus.CounterValue, float64(n), name)
+		}
+	} else {
+		log.Print(err)
+	}
+
+	for _, p := range c.handler.Probes() {
+		paused := 0.0
+		if p.Paused {
+			paused = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.probePrograms, prometheus.GaugeValue, float64(p.Programs), p.Name)
+		ch <- prometheus.MustNewConstMetric(c.probeAttached, prometheus.GaugeValue, float64(p.Attached), p.Name)
+		ch <- prometheus.MustNewConstMetric(c.probePaused, prometheus.GaugeValue, paused, p.Name)
+	}
+}
+
+// MetricsServer serves the Prometheus metrics of the detector and its eBPF handler on /metrics,
+// along with the metrics of the Go runtime and the process.
+func MetricsServer(addr string, d *detector.EventsDetector, h *ebpf.Handler) *http.Server {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		newMetricsCollector(d, h),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	return &http.Server{Addr: addr, Handler: mux}
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	syscallPrefix = "sys_"
+	entrySuffix   = "_entry"
+	exitSuffix    = "_exit"
+)
+
+// syscallHalf is the entry or the exit event of a syscall waiting for its other half.
+type syscallHalf struct {
+	syscall string      // syscall is the name of the event without its _entry or _exit suffix.
+	exit    bool        // exit reports whether the event is the exit of the syscall.
+	ts      uint64      // ts is the kernel timestamp of the event.
+	event   parsedEvent // event is the parsed event.
+}
+
+// newSyscallHalf returns the syscall half of the event, false if it is not the entry or the exit
+// of a syscall. Only the syscall events are named sys_..., others may end with _exit too, e.g.
+// sched_process_exit.
+func newSyscallHalf(p parsedEvent) (syscallHalf, bool) {
+	if p.err != nil {
+		return syscallHalf{}, false
+	}
+
+	name, _ := p.record["eventId"].(string)
+	ts, ok := p.record["timestamp"].(uint64)
+	if !ok || !strings.HasPrefix(name, syscallPrefix) {
+		return syscallHalf{}, false
+	}
+
+	if syscall, ok := strings.CutSuffix(name, entrySuffix); ok {
+		return syscallHalf{syscall: syscall, ts: ts, event: p}, true
+	}
+
+	if syscall, ok := strings.CutSuffix(name, exitSuffix); ok {
+		return syscallHalf{syscall: syscall, exit: true, ts: ts, event: p}, true
+	}
+
+	return syscallHalf{}, false
+}
+
+// matches reports whether h and o are the entry and the exit of the same syscall.
+func (h syscallHalf) matches(o syscallHalf) bool {
+	if h.syscall != o.syscall || h.exit == o.exit {
+		return false
+	}
+
+	entry, exit := h, o
+	if h.exit {
+		entry, exit = o, h
+	}
+
+	return entry.ts <= exit.ts
+}
+
+// merge returns the record of the syscall of the entry and exit events: the record of the entry,
+// named after the syscall like the events of the fexit backend, with the arguments of both events,
+// the return value, the errno name, empty when the syscall succeeded, and the duration in nanoseconds.
+func merge(entry, exit syscallHalf) parsedEvent {
+	record := entry.event.record
+	record["eventId"] = entry.syscall
+
+	entryArgs, _ := record["context"].([]eventparser.Arg)
+	exitArgs, _ := exit.event.record["context"].([]eventparser.Arg)
+	record["context"] = append(entryArgs, exitArgs...)
+
+	record["errno"] = ""
+	for _, arg := range exitArgs {
+		if arg.Name != "return" {
+			continue
+		}
+
+		ret, err := strconv.ParseInt(arg.Value, 10, 64)
+		if err != nil {
+			break
+		}
+
+		record["return"] = ret
+		if ret < 0 && ret >= -4095 {
+			record["errno"] = unix.ErrnoName(unix.Errno(-ret))
+		}
+	}
+
+	record["duration"] = exit.ts - entry.ts
+
+	return parsedEvent{record: record, read: true}
+}
+
+// correlate merges the entry and exit events of the syscalls of every thread of in into one record,
+// sent to out. The events of a thread are read from the buffer of the processor it ran on, so the
+// exit of a syscall may arrive before its entry: each half waits for the other until timeout past
+// its kernel timestamp, and is then sent unmerged and counted as unmatched, like a half replaced by
+// another syscall of the thread. The other events are not delayed. out is closed once in is closed
+// and drained, the halves still waiting are then sent in the order of their timestamp. The latency
+// summary events are sent along the merged records.
+func (t *EventsDetector) correlate(in <-chan parsedEvent, out chan<- parsedEvent) {
+	pending := make(map[uint32]syscallHalf)
+
+	expire := func(deadline uint64) {
+		var expired []syscallHalf
+		for thread, h := range pending {
+			if h.ts <= deadline {
+				expired = append(expired, h)
+				delete(pending, thread)
+			}
+		}
+
+		sort.Slice(expired, func(i, j int) bool { return expired[i].ts < expired[j].ts })
+
+		for _, h := range expired {
+			t.unmatchedEvents.Add(1)
+			out <- h.event
+		}
+	}
+
+	summarize := func() {
+		if summary, ok := t.latencySummary(); ok {
+			out <- summary
+		}
+	}
+
+	ticker := time.NewTicker(max(t.correlation/4, time.Millisecond))
+	defer ticker.Stop()
+
+	var summaries <-chan time.Time
+	if t.latency && t.latencySummaryInterval > 0 {
+		summaryTicker := time.NewTicker(t.latencySummaryInterval)
+		defer summaryTicker.Stop()
+
+		summaries = summaryTicker.C
+	}
+
+	for {
+		select {
+		case p, ok := <-in:
+			if !ok {
+				expire(^uint64(0))
+				if summaries != nil {
+					summarize()
+				}
+
+				close(out)
+
+				return
+			}
+
+			h, ok := newSyscallHalf(p)
+			thread, hasThread := p.record["hostThreadId"].(uint32)
+			if !ok || !hasThread {
+				out <- p
+				continue
+			}
+
+			other, ok := pending[thread]
+			switch {
+			case !ok:
+				pending[thread] = h
+			case other.matches(h):
+				delete(pending, thread)
+
+				// both events were read, the merged record is counted once by ReadAsInterface
+				t.incrementTotalCount()
+
+				entry, exit := other, h
+				if !h.exit {
+					entry, exit = h, other
+				}
+
+				merged := merge(entry, exit)
+				t.observeLatency(merged.record)
+
+				out <- merged
+			default:
+				t.unmatchedEvents.Add(1)
+				out <- other.event
+
+				pending[thread] = h
+			}
+		case <-ticker.C:
+		case <-summaries:
+			summarize()
+		}
+
+		expire(monotonicNow() - uint64(t.correlation))
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// TestEventsDetector_correlate tests the correlate function. It checks that the entry and exit events
+// of a thread are merged, whichever arrives first, and that the halves left alone are counted.
+func TestEventsDetector_correlate(t *testing.T) {
+	// the timestamps are offsets from the start of the test on the kernel clock
+	base := monotonicNow()
+	event := func(name string, thread uint32, ts uint64, args ...eventparser.Arg) parsedEvent {
+		return parsedEvent{record: map[string]any{
+			"eventId":      name,
+			"hostThreadId": thread,
+			"timestamp":    base + ts,
+			"context":      args,
+		}, read: true}
+	}
+
+	fd := eventparser.Arg{Name: "fd", Value: "3"}
+	ret := func(v string) eventparser.Arg { return eventparser.Arg{Name: "return", Value: v} }
+
+	tests := []struct {
+		name          string
+		events        []parsedEvent
+		want          []string
+		wantUnmatched uint64
+	}{
+		{
+			name:   "entry and exit merged",
+			events: []parsedEvent{event("sys_close_entry", 1, 10, fd), event("sys_close_exit", 1, 15, ret("0"))},
+			want:   []string{"sys_close"},
+		},
+		{
+			name:   "exit before entry",
+			events: []parsedEvent{event("sys_close_exit", 1, 15, ret("0")), event("sys_close_entry", 1, 10, fd)},
+			want:   []string{"sys_close"},
+		},
+		{
+			name: "threads interleaved",
+			events: []parsedEvent{
+				event("sys_close_entry", 1, 10, fd),
+				event("sys_read_entry", 2, 11, fd),
+				event("sys_close_exit", 1, 12, ret("0")),
+				event("sys_read_exit", 2, 13, ret("5")),
+			},
+			want: []string{"sys_close", "sys_read"},
+		},
+		{
+			name: "entry replaced by another syscall",
+			events: []parsedEvent{
+				event("sys_execve_entry", 1, 10),
+				event("sys_close_entry", 1, 11, fd),
+				event("sys_close_exit", 1, 12, ret("0")),
+			},
+			want:          []string{"sys_execve_entry", "sys_close"},
+			wantUnmatched: 1,
+		},
+		{
+			name: "other events and errors not delayed",
+			events: []parsedEvent{
+				event("sys_close_entry", 1, 10, fd),
+				event("ssl_write", 1, 11),
+				{record: map[string]any{}, err: errors.New("read failed")},
+			},
+			want:          []string{"ssl_write", "", "sys_close_entry"},
+			wantUnmatched: 1,
+		},
+		{
+			name: "lifecycle events not taken for syscalls",
+			events: []parsedEvent{
+				event("sys_exit_group_entry", 1, 10),
+				event("sched_process_exit", 1, 11),
+				event("sys_close_entry", 2, 12, fd),
+				event("sys_close_exit", 2, 13, ret("0")),
+			},
+			want:          []string{"sched_process_exit", "sys_close", "sys_exit_group_entry"},
+			wantUnmatched: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewEventsDetector().Correlate(time.Hour)
+
+			in := make(chan parsedEvent, len(tt.events))
+			out := make(chan parsedEvent, len(tt.events))
+			for _, e := range tt.events {
+				in <- e
+			}
+
+			close(in)
+			tr.correlate(in, out)
+
+			var got []string
+			for e := range out {
+				name, _ := e.record["eventId"].(string)
+				got = append(got, name)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventsDetector.correlate() = %v, want %v", got, tt.want)
+			}
+
+			if tr.GetUnmatchedCount() != tt.wantUnmatched {
+				t.Errorf("EventsDetector.GetUnmatchedCount() = %v, want %v", tr.GetUnmatchedCount(), tt.wantUnmatched)
+			}
+		})
+	}
+}
+
+// Test_merge tests the merge function
+func Test_merge(t *testing.T) {
+	half := func(name string, exit bool, ts uint64, args ...eventparser.Arg) syscallHalf {
+		return syscallHalf{syscall: name, exit: exit, ts: ts, event: parsedEvent{record: map[string]any{
+			"eventId":   name,
+			"timestamp": ts,
+			"context":   args,
+		}, read: true}}
+	}
+
+	path := eventparser.Arg{Name: "filename", Value: "/etc/shadow"}
+
+	tests := []struct {
+		name  string
+		entry syscallHalf
+		exit  syscallHalf
+		want  map[string]any
+	}{
+		{
+			name:  "succeeded",
+			entry: half("sys_openat", false, 100, path),
+			exit:  half("sys_openat", true, 150, eventparser.Arg{Name: "return", Value: "3"}),
+			want: map[string]any{
+				"eventId":   "sys_openat",
+				"timestamp": uint64(100),
+				"context":   []eventparser.Arg{path, {Name: "return", Value: "3"}},
+				"return":    int64(3),
+				"errno":     "",
+				"duration":  uint64(50),
+			},
+		},
+		{
+			name:  "failed",
+			entry: half("sys_openat", false, 100, path),
+			exit:  half("sys_openat", true, 110, eventparser.Arg{Name: "return", Value: "-13"}),
+			want: map[string]any{
+				"eventId":   "sys_openat",
+				"timestamp": uint64(100),
+				"context":   []eventparser.Arg{path, {Name: "return", Value: "-13"}},
+				"return":    int64(-13),
+				"errno":     "EACCES",
+				"duration":  uint64(10),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := merge(tt.entry, tt.exit); !reflect.DeepEqual(got.record, tt.want) {
+				t.Errorf("merge() = %v, want %v", got.record, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,13 @@
 package detector
 
 import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/intelops/tarian-detector/pkg/err"
 	"github.com/intelops/tarian-detector/pkg/eventparser"
 )
@@ -32,6 +39,40 @@ type EventsDetector struct {
 	totalRecordsCount int                     // totalRecordsCount is the total count of records.
 	totalDetectors    int                     // totalDetectors is the total number of detectors.
 	probeRecordsCount map[string]int          // probeRecordsCount is a map of probe names to their respective counts
+	countMu           sync.Mutex              // countMu guards the counts, read while the events are read.
+	parseErrors       atomic.Uint64           // parseErrors is the number of events which could not be read or parsed.
+	enrichErrors      atomic.Uint64           // enrichErrors is the number of events an enrichment function failed on.
+
+	mu      sync.Mutex         // mu guards started, closed and cancel.
+	cancel  context.CancelFunc // cancel stops the readers started by Start.
+	readers sync.WaitGroup     // readers tracks the goroutines reading the detectors.
+	stopped chan struct{}      // stopped is closed once the readers exited and the detectors are closed.
+	stopErr error              // stopErr is the error encountered closing the detectors.
+
+	workers     int              // workers is the number of goroutines parsing the events, 0 to parse them in ReadAsInterface.
+	ordering    Ordering         // ordering is the order of the events parsed by the workers.
+	enrichers   []EnrichFunc     // enrichers add information to the parsed events.
+	trackers    []EnrichFunc     // trackers keep state across the events sorted and merged, and add information to them.
+	parsedQueue chan parsedEvent // parsedQueue is a channel that contains the events parsed by the workers.
+
+	window     time.Duration // window is the time the events are held to be sorted by timestamp, 0 to disable the sorting.
+	lateEvents atomic.Uint64 // lateEvents is the number of events arrived after the window.
+
+	correlation     time.Duration // correlation is the time the entry and exit events of a syscall wait for each other, 0 to not merge them.
+	unmatchedEvents atomic.Uint64 // unmatchedEvents is the number of entry and exit events returned without their other half.
+
+	latency                bool                             // latency reports whether the latency of the merged syscalls is recorded.
+	container              ContainerFunc                    // container returns the container of the process of an event.
+	latencySummaryInterval time.Duration                    // latencySummaryInterval is the interval of the latency summary events, 0 to not return them.
+	latencyMu              sync.Mutex                       // latencyMu guards the latency histograms, updated by correlate.
+	latencyTotal           map[LatencyKey]*LatencyHistogram // latencyTotal are the latency histograms since the start.
+	latencyInterval        map[LatencyKey]*LatencyHistogram // latencyInterval are the latency histograms since the last summary.
+
+	overflow  OverflowPolicy // overflow is the policy applied by the readers when the event queue is full.
+	dropMu    sync.Mutex     // dropMu guards dropCount, updated by the readers.
+	dropCount map[string]int // dropCount is a map of event names to the number of events dropped by the overflow policy.
+
+	progress atomic.Int64 // progress is the time, in Unix nanoseconds, the last event was taken from the event queue.
 }
 
 // NewEventsDetector creates a new EventsDetector instance
@@ -45,9 +86,119 @@ func NewEventsDetector() *EventsDetector {
 		probeRecordsCount: make(map[string]int),
 		totalRecordsCount: 0,
 		totalDetectors:    0,
+
+		dropCount: make(map[string]int),
+
+		latencyTotal:    make(map[LatencyKey]*LatencyHistogram),
+		latencyInterval: make(map[LatencyKey]*LatencyHistogram),
 	}
 }
 
+// QueueSize sets the number of events read and not yet parsed the event queue holds, 131072 by
+// default, and the policy applied when it is full. It must be called before Start.
+func (t *EventsDetector) QueueSize(n int, p OverflowPolicy) *EventsDetector {
+	if n > 0 {
+		t.eventQueue = make(chan detectorReadReturn, n)
+	}
+
+	t.overflow = p
+
+	return t
+}
+
+// GetQueueDepth returns the number of events read and not yet parsed.
+func (t *EventsDetector) GetQueueDepth() int {
+	return len(t.eventQueue)
+}
+
+// Workers sets the number of goroutines parsing and enriching the events, and the order in
+// which they are returned by ReadAsInterface. With 0 workers, the default, the events are
+// parsed by the caller of ReadAsInterface, in the order they were read. It must be called
+// before Start.
+func (t *EventsDetector) Workers(n int, o Ordering) *EventsDetector {
+	t.workers = max(n, 0)
+	t.ordering = o
+
+	return t
+}
+
+// Reorder sorts the events returned by ReadAsInterface by their kernel timestamp. Every event is
+// held for the window, so that the events captured before it on the other processors arrive first;
+// those arriving later are counted as late and returned unsorted. A window of 0, the default,
+// disables the sorting. It must be called before Start.
+func (t *EventsDetector) Reorder(window time.Duration) *EventsDetector {
+	t.window = max(window, 0)
+
+	return t
+}
+
+// GetReorderWindow returns the window of the sorting of the events, 0 if they are not sorted.
+func (t *EventsDetector) GetReorderWindow() time.Duration {
+	return t.window
+}
+
+// GetLateCount returns the number of events which arrived after the window of the sorting.
+func (t *EventsDetector) GetLateCount() uint64 {
+	return t.lateEvents.Load()
+}
+
+// Correlate merges the entry and exit events of the syscalls, e.g. sys_openat_entry and sys_openat_exit,
+// into one record named after the syscall, e.g. sys_openat, keyed by the host thread ID. The record has
+// the arguments of both events, the return value, the errno name, empty when the syscall succeeded, and
+// the duration of the syscall in nanoseconds. An event whose other half did not arrive within timeout
+// is returned alone, and counted as unmatched. A timeout of 0, the default, disables the merging. It
+// must be called before Start.
+func (t *EventsDetector) Correlate(timeout time.Duration) *EventsDetector {
+	t.correlation = max(timeout, 0)
+
+	return t
+}
+
+// GetCorrelationTimeout returns the time the entry and exit events of a syscall wait for each other,
+// 0 if they are not merged.
+func (t *EventsDetector) GetCorrelationTimeout() time.Duration {
+	return t.correlation
+}
+
+// GetUnmatchedCount returns the number of entry and exit events returned without their other half.
+func (t *EventsDetector) GetUnmatchedCount() uint64 {
+	return t.unmatchedEvents.Load()
+}
+
+// Latency records the durations of the syscalls merged by Correlate in histograms, by syscall and by
+// the container returned by container, which may be nil. With an interval, a summary event named
+// LatencyEvent is returned by ReadAsInterface every interval, with the number, mean, median, 99th
+// percentile and longest durations of every syscall and container since the previous summary. The
+// durations are those of the merged records, so Start fails unless Correlate is set too. It must be
+// called before Start.
+func (t *EventsDetector) Latency(interval time.Duration, container ContainerFunc) *EventsDetector {
+	t.latency = true
+	t.latencySummaryInterval = max(interval, 0)
+	t.container = container
+
+	return t
+}
+
+// Enrich adds a function adding information to the parsed events, run after the parsing
+// of every event. It must be called before Start.
+func (t *EventsDetector) Enrich(f EnrichFunc) *EventsDetector {
+	t.enrichers = append(t.enrichers, f)
+
+	return t
+}
+
+// Track adds a function keeping state across the events, e.g. process.Tree.Enrich or
+// process.FdTable.Enrich, and adding information to them. Unlike the enrichers, which run on the workers
+// as the events of the processors arrive, the trackers run on a single goroutine once the events are
+// sorted by Reorder and merged by Correlate, in the order they are returned by ReadAsInterface: the fork
+// of a process is seen before the events of its child. Start fails unless Reorder is set. It must be
+// called before Start.
+func (t *EventsDetector) Track(f EnrichFunc) *EventsDetector {
+	t.trackers = append(t.trackers, f)
+
+	return t
+}
+
 // Add adds an event detector to the detector.
 func (t *EventsDetector) Add(detector EventDetector) {
 	t.detectors = append(t.detectors, detector)
@@ -61,95 +212,239 @@ func (t *EventsDetector) incrementDetectorCountBy(n int) {
 
 // incrementTotalCount increments the total number of records.
 func (t *EventsDetector) incrementTotalCount() {
+	t.countMu.Lock()
+	defer t.countMu.Unlock()
+
 	t.totalRecordsCount++
 }
 
 // GetTotalCount returns the total number of records.
 func (t *EventsDetector) GetTotalCount() int {
+	t.countMu.Lock()
+	defer t.countMu.Unlock()
+
 	return t.totalRecordsCount
 }
 
 // probeCount increments the count of a probe.
 func (t *EventsDetector) probeCount(probe string) {
+	t.countMu.Lock()
+	defer t.countMu.Unlock()
+
 	t.probeRecordsCount[probe]++
 }
 
-// GetProbeCount returns the count of probes.
+// GetProbeCount returns a copy of the count of probes.
 func (t *EventsDetector) GetProbeCount() map[string]int {
-	return t.probeRecordsCount
+	t.countMu.Lock()
+	defer t.countMu.Unlock()
+
+	count := make(map[string]int, len(t.probeRecordsCount))
+	for probe, n := range t.probeRecordsCount {
+		count[probe] = n
+	}
+
+	return count
+}
+
+// GetParseErrorCount returns the number of events which could not be read or parsed.
+func (t *EventsDetector) GetParseErrorCount() uint64 {
+	return t.parseErrors.Load()
+}
+
+// GetEnrichErrorCount returns the number of events an enrichment function failed on.
+func (t *EventsDetector) GetEnrichErrorCount() uint64 {
+	return t.enrichErrors.Load()
 }
 
 // Start initiates the event detection process. It iterates over the map of each detector,
 // starts a goroutine for each map. These goroutines continuously read events from the maps
-// and send them to the event queue. If the detector is closed, the goroutines stop reading events.
-func (t *EventsDetector) Start() error {
+// and send them to the event queue until ctx is cancelled or Stop is called. The detectors
+// are then closed, which unblocks the pending reads, and the event queue is closed once every
+// reader exited, so that ReadAsInterface returns the queued events and then io.EOF.
+func (t *EventsDetector) Start(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started {
+		return detectorErr.Throw("event detector already started")
+	}
+
+	if t.closed {
+		return detectorErr.Throw("event detector closed")
+	}
+
+	if len(t.trackers) != 0 && t.window == 0 {
+		return detectorErr.Throw("the trackers see the events sorted by Reorder, which is not set")
+	}
+
+	if t.latency && t.correlation == 0 {
+		return detectorErr.Throw("the latency of the syscalls is recorded from the records merged by Correlate, which is not set")
+	}
+
+	var mapReaders []func() ([]byte, error)
 	for _, detector := range t.detectors {
-		d := detector
-		mapReaders, err := d.ReadAsInterface()
+		mrs, err := detector.ReadAsInterface()
 		if err != nil {
 			return detectorErr.Throwf("%v", err)
 		}
 
-		for _, reader := range mapReaders {
-			go func(r func() ([]byte, error)) {
-				for {
-					if t.closed {
-						return
-					}
-
-					event, err := r()
-					if err == nil {
-						if len(event) != 0 {
-							t.eventQueue <- detectorReadReturn{event, err}
-						}
-					} else {
-						t.eventQueue <- detectorReadReturn{[]byte{}, err}
-					}
-				}
-			}(reader)
+		mapReaders = append(mapReaders, mrs...)
+	}
+
+	// the events are loaded once, before they are shared by the workers
+	eventparser.LoadTarianEvents()
+
+	ctx, t.cancel = context.WithCancel(ctx)
+	t.stopped = make(chan struct{})
+	t.progressed()
+
+	if t.workers > 0 || t.window > 0 || t.correlation > 0 {
+		t.parsedQueue = make(chan parsedEvent, cap(t.eventQueue))
+
+		// the events are parsed, sorted, merged and then tracked
+		parsed := t.parsedQueue
+		if len(t.trackers) != 0 {
+			merged := make(chan parsedEvent, cap(t.eventQueue))
+			go t.track(merged, parsed)
+			parsed = merged
+		}
+
+		if t.correlation > 0 {
+			sorted := make(chan parsedEvent, cap(t.eventQueue))
+			go t.correlate(sorted, parsed)
+			parsed = sorted
 		}
+
+		if t.window > 0 {
+			unsorted := make(chan parsedEvent, cap(t.eventQueue))
+			go t.reorder(unsorted, parsed)
+			parsed = unsorted
+		}
+
+		t.startWorkers(parsed)
 	}
 
+	for _, reader := range mapReaders {
+		t.readers.Add(1)
+		go t.read(ctx, reader)
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		t.stopErr = t.closeDetectors()
+		t.readers.Wait()
+
+		close(t.eventQueue)
+		close(t.stopped)
+	}()
+
 	t.started = true
 
 	return nil
 }
 
-// Close closes the event detector.
+// read sends the events read by r to the event queue until ctx is cancelled. The
+// errors of the reads failing because the detectors are being closed are dropped.
+func (t *EventsDetector) read(ctx context.Context, r func() ([]byte, error)) {
+	defer t.readers.Done()
+
+	for {
+		event, err := r()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil && len(event) == 0 {
+			continue
+		}
+
+		if !t.enqueue(ctx, detectorReadReturn{event, err}) {
+			return
+		}
+	}
+}
+
+// Stop stops the event detector started by Start and waits for its readers to exit. The
+// events already queued are still returned by ReadAsInterface, followed by io.EOF.
+func (t *EventsDetector) Stop() error {
+	t.mu.Lock()
+	if !t.started {
+		t.mu.Unlock()
+		return t.closeDetectors()
+	}
+
+	t.cancel()
+	t.mu.Unlock()
+
+	<-t.stopped
+
+	return t.stopErr
+}
+
+// Close closes the event detector. It is equivalent to Stop.
 func (t *EventsDetector) Close() error {
+	return t.Stop()
+}
+
+// closeDetectors closes the detectors once.
+func (t *EventsDetector) closeDetectors() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil
+	}
+
 	t.closed = true
 
+	// every detector is closed, so that none of the readers is left blocked
+	var errs []error
 	for _, detector := range t.detectors {
 		err := detector.Close()
 		if err != nil {
-			return detectorErr.Throwf("%v", err)
+			errs = append(errs, detectorErr.Throwf("%v", err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // ReadAsInterface reads a byte array from the event queue, parses it, and increments the total count.
-// It also checks for the presence of an event ID and increments the probe count if found.
+// It also checks for the presence of an event ID and increments the probe count if found. With workers,
+// the sorting or the merging of the events, the event is instead read already parsed and enriched.
+// It returns io.EOF once the event detector is stopped and the event queue is drained.
 func (t *EventsDetector) ReadAsInterface() (map[string]any, error) {
-	eventparser.LoadTarianEvents()
-	r := <-t.eventQueue
-	if r.err != nil {
-		return map[string]any{}, detectorErr.Throwf("%v", r.err)
+	var p parsedEvent
+	if t.parsedQueue != nil {
+		var ok bool
+		if p, ok = <-t.parsedQueue; !ok {
+			return nil, io.EOF
+		}
+	} else {
+		r, ok := <-t.eventQueue
+		if !ok {
+			return nil, io.EOF
+		}
+
+		p = t.parse(r)
+	}
+
+	if p.read {
+		t.incrementTotalCount()
 	}
 
-	t.incrementTotalCount()
-	data, err := eventparser.ParseByteArray(r.eventData)
-	if err != nil {
-		return data, detectorErr.Throwf("%v", err)
+	if p.err != nil {
+		return p.record, p.err
 	}
 
-	probe, ok := data["eventId"]
+	probe, ok := p.record["eventId"]
 	if ok {
 		t.probeCount(probe.(string))
 	}
 
-	return data, nil
+	return p.record, nil
 }
 
 // Count returns the number of detectors active.
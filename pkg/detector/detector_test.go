@@ -4,8 +4,12 @@
 package detector
 
 import (
+	"context"
+	"errors"
+	"io"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestNewEventsDetector tests the NewEventsDetector function. It checks if the NewEventsDetector function returns an EventsDetector with the correct default values.
@@ -111,20 +115,20 @@ func TestEventsDetector_incrementDetectorCountBy(t *testing.T) {
 	}
 	tests := []struct {
 		name   string
-		fields EventsDetector
+		fields *EventsDetector
 		args   args
 		want   int
 	}{
 		{
 			name:   "increment by 1",
-			fields: *NewEventsDetector(),
+			fields: NewEventsDetector(),
 			args: args{
 				n: 1,
 			},
 			want: 1,
 		}, {
 			name:   "increment by 13",
-			fields: *NewEventsDetector(),
+			fields: NewEventsDetector(),
 			args: args{
 				n: 13,
 			},
@@ -157,12 +161,12 @@ func TestEventsDetector_incrementDetectorCountBy(t *testing.T) {
 func TestEventsDetector_incrementTotalCount(t *testing.T) {
 	tests := []struct {
 		name   string
-		fields EventsDetector
+		fields *EventsDetector
 		want   int
 	}{
 		{
 			name:   "per call increment check",
-			fields: *NewEventsDetector(),
+			fields: NewEventsDetector(),
 			want:   1,
 		},
 	}
@@ -191,25 +195,25 @@ func TestEventsDetector_incrementTotalCount(t *testing.T) {
 func TestEventsDetector_GetTotalCount(t *testing.T) {
 	tests := []struct {
 		name            string
-		fields          EventsDetector
+		fields          *EventsDetector
 		callIncrementBy int
 		want            int
 	}{
 		{
 			name:            "default",
-			fields:          *NewEventsDetector(),
+			fields:          NewEventsDetector(),
 			callIncrementBy: 0,
 			want:            0,
 		},
 		{
 			name:            "per call increment check",
-			fields:          *NewEventsDetector(),
+			fields:          NewEventsDetector(),
 			callIncrementBy: 1,
 			want:            1,
 		},
 		{
 			name:            "per call increment check",
-			fields:          *NewEventsDetector(),
+			fields:          NewEventsDetector(),
 			callIncrementBy: 27,
 			want:            27,
 		},
@@ -252,14 +256,14 @@ func TestEventsDetector_probeCount(t *testing.T) {
 
 	tests := []struct {
 		name            string
-		fields          EventsDetector
+		fields          *EventsDetector
 		args            args
 		want            want
 		callIncrementBy int
 	}{
 		{
 			name:   "increment by 1",
-			fields: *NewEventsDetector(),
+			fields: NewEventsDetector(),
 			args: args{
 				probe: "test",
 			},
@@ -270,7 +274,7 @@ func TestEventsDetector_probeCount(t *testing.T) {
 			},
 		}, {
 			name:   "increment by 38",
-			fields: *NewEventsDetector(),
+			fields: NewEventsDetector(),
 			args: args{
 				probe: "test",
 			},
@@ -313,14 +317,14 @@ func TestEventsDetector_GetProbeCount(t *testing.T) {
 
 	tests := []struct {
 		name            string
-		fields          EventsDetector
+		fields          *EventsDetector
 		args            args
 		callIncrementBy int
 		want            map[string]int
 	}{
 		{
 			name:   "default",
-			fields: *NewEventsDetector(),
+			fields: NewEventsDetector(),
 			args: args{
 				probe: "test",
 			},
@@ -329,7 +333,7 @@ func TestEventsDetector_GetProbeCount(t *testing.T) {
 		},
 		{
 			name:   "per call increment check",
-			fields: *NewEventsDetector(),
+			fields: NewEventsDetector(),
 			args: args{
 				probe: "test",
 			},
@@ -367,18 +371,18 @@ func TestEventsDetector_GetProbeCount(t *testing.T) {
 func TestEventsDetector_Count(t *testing.T) {
 	tests := []struct {
 		name            string
-		fields          EventsDetector
+		fields          *EventsDetector
 		callIncrementBy int
 		want            int
 	}{
 		{
 			name:            "default",
-			fields:          *NewEventsDetector(),
+			fields:          NewEventsDetector(),
 			callIncrementBy: 0,
 			want:            0,
 		}, {
 			name:            "increment by 7",
-			fields:          *NewEventsDetector(),
+			fields:          NewEventsDetector(),
 			callIncrementBy: 7,
 			want:            7,
 		},
@@ -404,3 +408,182 @@ func TestEventsDetector_Count(t *testing.T) {
 		})
 	}
 }
+
+// fakeDetector is an EventDetector whose reader returns the events of a channel, and
+// fails once the detector is closed.
+type fakeDetector struct {
+	events   chan []byte
+	closed   chan struct{}
+	closeErr error
+}
+
+func newFakeDetector(events ...[]byte) *fakeDetector {
+	f := &fakeDetector{
+		events: make(chan []byte, len(events)),
+		closed: make(chan struct{}),
+	}
+
+	for _, e := range events {
+		f.events <- e
+	}
+
+	return f
+}
+
+func (f *fakeDetector) Count() int { return 1 }
+
+func (f *fakeDetector) Close() error {
+	close(f.closed)
+	return f.closeErr
+}
+
+func (f *fakeDetector) ReadAsInterface() ([]func() ([]byte, error), error) {
+	return []func() ([]byte, error){
+		func() ([]byte, error) {
+			select {
+			case e := <-f.events:
+				return e, nil
+			case <-f.closed:
+				return nil, errors.New("reader closed")
+			}
+		},
+	}, nil
+}
+
+// TestEventsDetector_Start tests the Start and Stop functions. It checks that the readers are
+// stopped by the cancellation of the context and that the queued events are drained before io.EOF.
+func TestEventsDetector_Start(t *testing.T) {
+	tests := []struct {
+		name   string
+		events [][]byte
+		cancel bool
+	}{
+		{
+			name:   "stopped by Stop",
+			events: [][]byte{{1}, {2}, {3}},
+		},
+		{
+			name:   "stopped by the context",
+			events: [][]byte{{1}, {2}},
+			cancel: true,
+		},
+		{
+			name: "no events",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			fake := newFakeDetector(tt.events...)
+			tr := NewEventsDetector()
+			tr.Add(fake)
+
+			if err := tr.Start(ctx); err != nil {
+				t.Fatalf("EventsDetector.Start() error = %v", err)
+			}
+
+			if err := tr.Start(ctx); err == nil {
+				t.Errorf("EventsDetector.Start() error = %v, wantErr %v", err, true)
+			}
+
+			// wait for the reader to queue every event
+			for len(tr.eventQueue) != len(tt.events) {
+				time.Sleep(time.Millisecond)
+			}
+
+			if tt.cancel {
+				cancel()
+			}
+
+			if err := tr.Stop(); err != nil {
+				t.Errorf("EventsDetector.Stop() error = %v", err)
+			}
+
+			for range tt.events {
+				// the fake events do not parse, they are still returned
+				if _, err := tr.ReadAsInterface(); errors.Is(err, io.EOF) {
+					t.Fatalf("EventsDetector.ReadAsInterface() error = %v, want queued event", err)
+				}
+			}
+
+			if _, err := tr.ReadAsInterface(); !errors.Is(err, io.EOF) {
+				t.Errorf("EventsDetector.ReadAsInterface() error = %v, want %v", err, io.EOF)
+			}
+
+			if err := tr.Close(); err != nil {
+				t.Errorf("EventsDetector.Close() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestEventsDetector_Stop tests the Stop function with a detector failing to close. It checks that
+// the other detectors are still closed, so that Stop does not wait on their readers.
+func TestEventsDetector_Stop(t *testing.T) {
+	failing := newFakeDetector()
+	failing.closeErr = errors.New("close failed")
+	other := newFakeDetector()
+
+	tr := NewEventsDetector()
+	tr.Add(failing)
+	tr.Add(other)
+
+	if err := tr.Start(context.Background()); err != nil {
+		t.Fatalf("EventsDetector.Start() error = %v", err)
+	}
+
+	stopped := make(chan error)
+	go func() { stopped <- tr.Stop() }()
+
+	select {
+	case err := <-stopped:
+		if err == nil {
+			t.Errorf("EventsDetector.Stop() error = %v, wantErr %v", err, true)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("EventsDetector.Stop() did not return")
+	}
+}
+
+// TestEventsDetector_Start_options tests the Start function with incompatible options
+func TestEventsDetector_Start_options(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector *EventsDetector
+		wantErr  bool
+	}{
+		{
+			name:     "latency of the merged syscalls",
+			detector: NewEventsDetector().Correlate(time.Second).Latency(time.Minute, nil),
+		},
+		{
+			name:     "latency without Correlate",
+			detector: NewEventsDetector().Latency(0, nil),
+			wantErr:  true,
+		},
+		{
+			name:     "trackers of the sorted events",
+			detector: NewEventsDetector().Reorder(time.Second).Track(func(map[string]any) error { return nil }),
+		},
+		{
+			name:     "trackers without Reorder",
+			detector: NewEventsDetector().Track(func(map[string]any) error { return nil }),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.detector.Start(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("EventsDetector.Start() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := tt.detector.Stop(); err != nil {
+				t.Errorf("EventsDetector.Stop() error = %v", err)
+			}
+		})
+	}
+}
@@ -4,4 +4,10 @@
 // Package detector provides an interface and implementation for event detection.
 // It allows for the addition of multiple event detectors, each capable of reading and parsing event data.
 // The package also provides functionality for starting and stopping the event detection process.
+// The process runs until the context given to Start is cancelled or Stop is called; the events
+// already queued are then drained by ReadAsInterface, which returns io.EOF once none is left.
+// The events can be parsed and enriched by a pool of workers, and sorted by their kernel timestamp
+// within a bounded window, and the entry and exit events of the syscalls merged into one record,
+// before they are returned. The durations of the merged syscalls are counted in latency histograms. Ready and Live report whether the events are
+// read, for the readiness and liveness probes of the detector.
 package detector
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"time"
+)
+
+// progressed records that an event was taken from the event queue.
+func (t *EventsDetector) progressed() {
+	t.progress.Store(time.Now().UnixNano())
+}
+
+// Ready returns an error unless the event detector is started and not stopped.
+func (t *EventsDetector) Ready() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.started {
+		return detectorErr.Throw("event detector not started")
+	}
+
+	if t.closed {
+		return detectorErr.Throw("event detector stopped")
+	}
+
+	return nil
+}
+
+// Live returns an error if events are queued and none was taken from the event queue for longer
+// than timeout, i.e. the events are no longer read and parsed and the readers are about to block,
+// or drop events, on the full queue. An event detector without events to read is live.
+func (t *EventsDetector) Live(timeout time.Duration) error {
+	depth := len(t.eventQueue)
+	if depth == 0 {
+		return nil
+	}
+
+	last := t.progress.Load()
+	if last == 0 {
+		return nil
+	}
+
+	if idle := time.Since(time.Unix(0, last)); idle > timeout {
+		return detectorErr.Throwf("no event read for %v, %d of %d events queued", idle.Round(time.Second), depth, cap(t.eventQueue))
+	}
+
+	return nil
+}
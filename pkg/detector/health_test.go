@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestEventsDetector_Live tests the Live function
+func TestEventsDetector_Live(t *testing.T) {
+	tests := []struct {
+		name     string
+		queued   int
+		progress time.Duration // time since the last event was taken from the queue
+		wantErr  bool
+	}{
+		{
+			name:     "no event queued",
+			progress: time.Hour,
+		},
+		{
+			name:     "events read",
+			queued:   2,
+			progress: time.Second,
+		},
+		{
+			name:     "events queued and not read",
+			queued:   2,
+			progress: time.Hour,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewEventsDetector().QueueSize(4, Block)
+			for i := 0; i < tt.queued; i++ {
+				tr.enqueue(context.Background(), detectorReadReturn{eventData: []byte{1}})
+			}
+
+			tr.progress.Store(time.Now().Add(-tt.progress).UnixNano())
+
+			if err := tr.Live(time.Minute); (err != nil) != tt.wantErr {
+				t.Errorf("EventsDetector.Live() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestEventsDetector_Ready tests the Ready function
+func TestEventsDetector_Ready(t *testing.T) {
+	tr := NewEventsDetector()
+	tr.Add(newFakeDetector())
+
+	if err := tr.Ready(); err == nil {
+		t.Errorf("EventsDetector.Ready() error = %v before Start, wantErr %v", err, true)
+	}
+
+	if err := tr.Start(context.Background()); err != nil {
+		t.Fatalf("EventsDetector.Start() error = %v", err)
+	}
+
+	if err := tr.Ready(); err != nil {
+		t.Errorf("EventsDetector.Ready() error = %v, wantErr %v", err, false)
+	}
+
+	if err := tr.Stop(); err != nil {
+		t.Fatalf("EventsDetector.Stop() error = %v", err)
+	}
+
+	if err := tr.Ready(); err == nil {
+		t.Errorf("EventsDetector.Ready() error = %v after Stop, wantErr %v", err, true)
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"sort"
+	"time"
+)
+
+// LatencyEvent is the name of the summary event of the latency of the syscalls.
+const LatencyEvent = "syscall_latency"
+
+// LatencyBuckets are the upper bounds of the buckets of the latency histograms.
+var LatencyBuckets = []time.Duration{
+	time.Microsecond, 5 * time.Microsecond, 10 * time.Microsecond, 50 * time.Microsecond,
+	100 * time.Microsecond, 500 * time.Microsecond, time.Millisecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 5 * time.Second,
+}
+
+// ContainerFunc returns the container of the process of an event, empty for the processes of the host.
+type ContainerFunc func(event map[string]any) string
+
+// LatencyKey identifies the latency histogram of a syscall in a container.
+type LatencyKey struct {
+	Syscall   string // Syscall is the name of the syscall, e.g. sys_openat.
+	Container string // Container is the container the syscall ran in, empty for the host.
+}
+
+// LatencyHistogram counts the durations of a syscall by the buckets of LatencyBuckets.
+type LatencyHistogram struct {
+	Buckets []uint64      // Buckets counts the durations up to the bound of LatencyBuckets of the same index, and above every bound for the last one.
+	Count   uint64        // Count is the number of durations.
+	Sum     time.Duration // Sum is the sum of the durations.
+	Max     time.Duration // Max is the longest duration.
+}
+
+// observe adds the duration d to the histogram.
+func (h *LatencyHistogram) observe(d time.Duration) {
+	if h.Buckets == nil {
+		h.Buckets = make([]uint64, len(LatencyBuckets)+1)
+	}
+
+	h.Buckets[sort.Search(len(LatencyBuckets), func(i int) bool { return d <= LatencyBuckets[i] })]++
+	h.Count++
+	h.Sum += d
+	h.Max = max(h.Max, d)
+}
+
+// Quantile returns the upper bound of the bucket of the q-quantile of the durations, or the longest
+// duration when it is above every bound.
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	rank := uint64(q * float64(h.Count))
+
+	var n uint64
+	for i, c := range h.Buckets {
+		n += c
+		if n > rank && i < len(LatencyBuckets) {
+			return min(LatencyBuckets[i], h.Max)
+		}
+	}
+
+	return h.Max
+}
+
+// LatencySummary sums up the durations of a syscall in a container.
+type LatencySummary struct {
+	Syscall   string        // Syscall is the name of the syscall, e.g. sys_openat.
+	Container string        // Container is the container the syscall ran in, empty for the host.
+	Count     uint64        // Count is the number of syscalls.
+	Mean      time.Duration // Mean is the mean duration.
+	P50       time.Duration // P50 is the upper bound of the bucket of the median duration.
+	P99       time.Duration // P99 is the upper bound of the bucket of the 99th percentile of the durations.
+	Max       time.Duration // Max is the longest duration.
+}
+
+// observeLatency adds the duration of the syscall record merged by correlate to its histograms.
+func (t *EventsDetector) observeLatency(record map[string]any) {
+	if !t.latency {
+		return
+	}
+
+	duration, ok := record["duration"].(uint64)
+	if !ok {
+		return
+	}
+
+	key := LatencyKey{}
+	key.Syscall, _ = record["eventId"].(string)
+	if t.container != nil {
+		key.Container = t.container(record)
+	}
+
+	t.latencyMu.Lock()
+	defer t.latencyMu.Unlock()
+
+	for _, histograms := range []map[LatencyKey]*LatencyHistogram{t.latencyTotal, t.latencyInterval} {
+		h, ok := histograms[key]
+		if !ok {
+			h = &LatencyHistogram{}
+			histograms[key] = h
+		}
+
+		h.observe(time.Duration(duration))
+	}
+}
+
+// latencySummary returns the summary event of the durations observed since the previous summary,
+// false if there are none.
+func (t *EventsDetector) latencySummary() (parsedEvent, bool) {
+	t.latencyMu.Lock()
+	defer t.latencyMu.Unlock()
+
+	if len(t.latencyInterval) == 0 {
+		return parsedEvent{}, false
+	}
+
+	summaries := make([]LatencySummary, 0, len(t.latencyInterval))
+	for key, h := range t.latencyInterval {
+		summaries = append(summaries, LatencySummary{
+			Syscall:   key.Syscall,
+			Container: key.Container,
+			Count:     h.Count,
+			Mean:      h.Sum / time.Duration(h.Count),
+			P50:       h.Quantile(0.5),
+			P99:       h.Quantile(0.99),
+			Max:       h.Max,
+		})
+	}
+
+	t.latencyInterval = make(map[LatencyKey]*LatencyHistogram)
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Syscall != summaries[j].Syscall {
+			return summaries[i].Syscall < summaries[j].Syscall
+		}
+
+		return summaries[i].Container < summaries[j].Container
+	})
+
+	return parsedEvent{record: map[string]any{
+		"eventId":   LatencyEvent,
+		"timestamp": monotonicNow(),
+		"interval":  t.latencySummaryInterval,
+		"latency":   summaries,
+	}}, true
+}
+
+// GetLatency returns a copy of the latency histograms of the syscalls, by syscall and container.
+func (t *EventsDetector) GetLatency() map[LatencyKey]LatencyHistogram {
+	t.latencyMu.Lock()
+	defer t.latencyMu.Unlock()
+
+	histograms := make(map[LatencyKey]LatencyHistogram, len(t.latencyTotal))
+	for key, h := range t.latencyTotal {
+		c := *h
+		c.Buckets = append([]uint64(nil), h.Buckets...)
+		histograms[key] = c
+	}
+
+	return histograms
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestLatencyHistogram_Quantile tests the observe and Quantile functions
+func TestLatencyHistogram_Quantile(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		q         float64
+		want      time.Duration
+	}{
+		{
+			name: "no duration",
+			q:    0.5,
+			want: 0,
+		},
+		{
+			name:      "median",
+			durations: []time.Duration{2 * time.Microsecond, 3 * time.Microsecond, 20 * time.Microsecond},
+			q:         0.5,
+			want:      5 * time.Microsecond,
+		},
+		{
+			name:      "bound above the longest duration",
+			durations: []time.Duration{2 * time.Microsecond, 3 * time.Microsecond},
+			q:         0.99,
+			want:      3 * time.Microsecond,
+		},
+		{
+			name:      "above every bound",
+			durations: []time.Duration{time.Microsecond, time.Minute},
+			q:         0.99,
+			want:      time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h LatencyHistogram
+			for _, d := range tt.durations {
+				h.observe(d)
+			}
+
+			if got := h.Quantile(tt.q); got != tt.want {
+				t.Errorf("LatencyHistogram.Quantile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEventsDetector_latencySummary tests the observeLatency and latencySummary functions. It checks
+// that the durations are summed up by syscall and container, and that the summary is reset.
+func TestEventsDetector_latencySummary(t *testing.T) {
+	tr := NewEventsDetector().Latency(time.Minute, func(event map[string]any) string {
+		container, _ := event["container"].(string)
+		return container
+	})
+
+	for _, record := range []map[string]any{
+		{"eventId": "sys_openat", "container": "nfs", "duration": uint64(3 * time.Millisecond)},
+		{"eventId": "sys_openat", "container": "nfs", "duration": uint64(time.Millisecond)},
+		{"eventId": "sys_openat", "duration": uint64(2 * time.Microsecond)},
+		{"eventId": "sys_connect_entry"},
+	} {
+		tr.observeLatency(record)
+	}
+
+	p, ok := tr.latencySummary()
+	if !ok {
+		t.Fatalf("EventsDetector.latencySummary() = %v, want a summary", ok)
+	}
+
+	want := []LatencySummary{
+		{Syscall: "sys_openat", Count: 1, Mean: 2 * time.Microsecond, P50: 2 * time.Microsecond, P99: 2 * time.Microsecond, Max: 2 * time.Microsecond},
+		{Syscall: "sys_openat", Container: "nfs", Count: 2, Mean: 2 * time.Millisecond, P50: 3 * time.Millisecond, P99: 3 * time.Millisecond, Max: 3 * time.Millisecond},
+	}
+
+	if got := p.record["latency"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("EventsDetector.latencySummary() = %+v, want %+v", got, want)
+	}
+
+	if _, ok := tr.latencySummary(); ok {
+		t.Errorf("EventsDetector.latencySummary() = %v after a summary, want %v", ok, false)
+	}
+
+	if got := tr.GetLatency()[LatencyKey{Syscall: "sys_openat", Container: "nfs"}].Count; got != 2 {
+		t.Errorf("EventsDetector.GetLatency() count = %v, want %v", got, 2)
+	}
+}
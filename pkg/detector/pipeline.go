@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// Ordering selects the order in which the events parsed by the workers are returned.
+type Ordering int
+
+const (
+	Unordered      Ordering = iota // Unordered returns the events as soon as a worker parsed them.
+	PerCpuOrder                    // PerCpuOrder returns the events of a processor in the order they were read.
+	PerThreadOrder                 // PerThreadOrder returns the events of a thread in the order they were read.
+)
+
+// String returns the name of the ordering.
+func (o Ordering) String() string {
+	switch o {
+	case Unordered:
+		return "unordered"
+	case PerCpuOrder:
+		return "cpu"
+	case PerThreadOrder:
+		return "thread"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(o))
+	}
+}
+
+// ParseOrdering returns the Ordering named s.
+func ParseOrdering(s string) (Ordering, error) {
+	for _, o := range []Ordering{Unordered, PerCpuOrder, PerThreadOrder} {
+		if o.String() == s {
+			return o, nil
+		}
+	}
+
+	return Unordered, detectorErr.Throwf("unknown ordering %q, expected %s, %s or %s", s, Unordered, PerCpuOrder, PerThreadOrder)
+}
+
+// EnrichFunc adds information to a parsed event. As an enricher, it runs on the parsing
+// workers, so it must be safe for concurrent use when more than one worker is configured,
+// and it sees the events of the processors in no particular order; as a tracker, see
+// Track, it sees the events in order. Its errors are counted, see GetEnrichErrorCount,
+// and the event is returned regardless.
+type EnrichFunc func(event map[string]any) error
+
+// parsedEvent is the result of the parsing of a detectorReadReturn.
+type parsedEvent struct {
+	record map[string]any // record is the parsed event.
+	read   bool           // read reports whether the event was read, i.e. err is not a read error.
+	err    error          // err contains any error that occurred reading or parsing the event.
+}
+
+// parse parses and enriches the event read by a detector.
+func (t *EventsDetector) parse(r detectorReadReturn) parsedEvent {
+	t.progressed()
+
+	if r.err != nil {
+		t.parseErrors.Add(1)
+		return parsedEvent{record: map[string]any{}, err: detectorErr.Throwf("%v", r.err)}
+	}
+
+	data, err := eventparser.ParseByteArray(r.eventData)
+	if err != nil {
+		t.parseErrors.Add(1)
+		return parsedEvent{record: data, read: true, err: detectorErr.Throwf("%v", err)}
+	}
+
+	for _, enrich := range t.enrichers {
+		if err := enrich(data); err != nil {
+			t.enrichErrors.Add(1)
+		}
+	}
+
+	return parsedEvent{record: data, read: true}
+}
+
+// track runs the trackers on the events of in, sorted and merged, and sends them to out, which is
+// closed once in is closed and drained. The events which could not be parsed and those of the
+// detector itself, e.g. the latency summaries, are not tracked.
+func (t *EventsDetector) track(in <-chan parsedEvent, out chan<- parsedEvent) {
+	for p := range in {
+		if p.read && p.err == nil {
+			for _, track := range t.trackers {
+				if err := track(p.record); err != nil {
+					t.enrichErrors.Add(1)
+				}
+			}
+		}
+
+		out <- p
+	}
+
+	close(out)
+}
+
+// startWorkers starts the workers parsing the events of the event queue into out, which is
+// closed once the event queue is closed and drained. With an ordering, the events of a processor
+// or a thread are all parsed by the same worker, so that they keep the order in which they were
+// read. A single worker is started when none is configured.
+func (t *EventsDetector) startWorkers(out chan<- parsedEvent) {
+	var workers sync.WaitGroup
+
+	work := func(in <-chan detectorReadReturn) {
+		defer workers.Done()
+
+		for r := range in {
+			out <- t.parse(r)
+		}
+	}
+
+	n := max(t.workers, 1)
+	workers.Add(n)
+	if t.ordering == Unordered {
+		for i := 0; i < n; i++ {
+			go work(t.eventQueue)
+		}
+	} else {
+		shards := make([]chan detectorReadReturn, n)
+		for i := range shards {
+			shards[i] = make(chan detectorReadReturn, cap(t.eventQueue)/n+1)
+			go work(shards[i])
+		}
+
+		go func() {
+			for r := range t.eventQueue {
+				shards[t.shard(r, n)] <- r
+			}
+
+			for _, shard := range shards {
+				close(shard)
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(out)
+	}()
+}
+
+// shard returns the index of the worker, out of n, parsing the event, from its processor or its
+// thread. The read errors and the events too short to be keyed are parsed by the first worker.
+func (t *EventsDetector) shard(r detectorReadReturn, n int) int {
+	if r.err != nil {
+		return 0
+	}
+
+	var key uint32
+	switch t.ordering {
+	case PerCpuOrder:
+		cpu, err := eventparser.GetProcessor(r.eventData)
+		if err != nil {
+			return 0
+		}
+
+		key = uint32(cpu)
+	case PerThreadOrder:
+		pid, err := eventparser.GetHostPid(r.eventData)
+		if err != nil {
+			return 0
+		}
+
+		key = pid
+	}
+
+	return int(key % uint32(n))
+}
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// closeEvent returns a raw sys_close_entry event captured on the processor cpu by the thread pid.
+func closeEvent(t testing.TB, cpu uint16, pid uint32, ts uint64) []byte {
+	var md eventparser.TarianMetaData
+	md.MetaData.Event = int32(eventparser.TDE_SYSCALL_CLOSE_E)
+	md.MetaData.Nparams = 1
+	md.MetaData.Ts = ts
+	md.MetaData.Processor = cpu
+	md.MetaData.Task.HostPid = pid
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, md); err != nil {
+		t.Fatal(err)
+	}
+
+	// fd
+	buf.Write([]byte{3, 0, 0, 0})
+
+	return buf.Bytes()
+}
+
+// streamDetector is an EventDetector whose reader returns its events in turn, n events
+// in total, and then blocks until it is closed.
+type streamDetector struct {
+	events [][]byte
+	n      int
+	closed chan struct{}
+}
+
+func (s *streamDetector) Count() int { return 1 }
+
+func (s *streamDetector) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func (s *streamDetector) ReadAsInterface() ([]func() ([]byte, error), error) {
+	return []func() ([]byte, error){
+		func() ([]byte, error) {
+			if s.n == 0 {
+				<-s.closed
+			}
+
+			select {
+			case <-s.closed:
+				return nil, errors.New("reader closed")
+			default:
+				s.n--
+				return s.events[s.n%len(s.events)], nil
+			}
+		},
+	}, nil
+}
+
+// TestParseOrdering tests the ParseOrdering function.
+func TestParseOrdering(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Ordering
+		wantErr bool
+	}{
+		{name: "unordered", s: "unordered", want: Unordered},
+		{name: "cpu", s: "cpu", want: PerCpuOrder},
+		{name: "thread", s: "thread", want: PerThreadOrder},
+		{name: "unknown", s: "time", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOrdering(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseOrdering() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseOrdering() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEventsDetector_Workers tests the parsing of the events by the workers. It checks that every
+// event is parsed and enriched, and that the events of a processor or a thread keep their order.
+func TestEventsDetector_Workers(t *testing.T) {
+	const perKey = 200
+
+	tests := []struct {
+		name     string
+		workers  int
+		ordering Ordering
+		key      string
+	}{
+		{name: "no workers", workers: 0, ordering: Unordered, key: "processor"},
+		{name: "cpu order", workers: 4, ordering: PerCpuOrder, key: "processor"},
+		{name: "thread order", workers: 3, ordering: PerThreadOrder, key: "hostProcessId"},
+		{name: "unordered", workers: 4, ordering: Unordered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the events of 8 processors, or threads, interleaved
+			var events [][]byte
+			for ts := uint64(1); ts <= perKey; ts++ {
+				for k := 0; k < 8; k++ {
+					events = append(events, closeEvent(t, uint16(k), uint32(k), ts))
+				}
+			}
+
+			tr := NewEventsDetector().Workers(tt.workers, tt.ordering)
+			tr.Add(newFakeDetector(events...))
+			tr.Enrich(func(e map[string]any) error {
+				e["enriched"] = true
+				return nil
+			})
+
+			if err := tr.Start(context.Background()); err != nil {
+				t.Fatalf("EventsDetector.Start() error = %v", err)
+			}
+
+			last := make(map[string]uint64)
+			for range events {
+				e, err := tr.ReadAsInterface()
+				if err != nil {
+					t.Fatalf("EventsDetector.ReadAsInterface() error = %v", err)
+				}
+
+				if e["enriched"] != true {
+					t.Errorf("EventsDetector.ReadAsInterface() event not enriched: %v", e)
+				}
+
+				if len(tt.key) == 0 {
+					continue
+				}
+
+				key := fmt.Sprint(e[tt.key])
+				ts := e["timestamp"].(uint64)
+				if ts <= last[key] {
+					t.Errorf("EventsDetector.ReadAsInterface() %s %s: timestamp %d after %d", tt.key, key, ts, last[key])
+				}
+
+				last[key] = ts
+			}
+
+			if err := tr.Stop(); err != nil {
+				t.Errorf("EventsDetector.Stop() error = %v", err)
+			}
+
+			if _, err := tr.ReadAsInterface(); !errors.Is(err, io.EOF) {
+				t.Errorf("EventsDetector.ReadAsInterface() error = %v, want %v", err, io.EOF)
+			}
+
+			if tr.GetTotalCount() != len(events) {
+				t.Errorf("EventsDetector.GetTotalCount() = %v, want %v", tr.GetTotalCount(), len(events))
+			}
+		})
+	}
+}
+
+// TestEventsDetector_Track tests the Track function. It checks that the trackers see the events
+// sorted, although they arrive out of order and are parsed by concurrent workers.
+func TestEventsDetector_Track(t *testing.T) {
+	// the timestamps are offsets from the start of the test on the kernel clock
+	base := monotonicNow()
+
+	var events [][]byte
+	for _, ts := range []uint64{5, 2, 8, 1, 7, 3, 6, 4} {
+		events = append(events, closeEvent(t, uint16(ts%2), uint32(ts), base+ts))
+	}
+
+	var tracked []uint64
+	tr := NewEventsDetector().Workers(4, Unordered).Reorder(50 * time.Millisecond)
+	tr.Add(newFakeDetector(events...))
+	tr.Track(func(e map[string]any) error {
+		tracked = append(tracked, e["timestamp"].(uint64)-base)
+		e["tracked"] = len(tracked)
+		return nil
+	})
+
+	if err := tr.Start(context.Background()); err != nil {
+		t.Fatalf("EventsDetector.Start() error = %v", err)
+	}
+
+	var got []any
+	for range events {
+		e, err := tr.ReadAsInterface()
+		if err != nil {
+			t.Fatalf("EventsDetector.ReadAsInterface() error = %v", err)
+		}
+
+		got = append(got, e["tracked"])
+	}
+
+	if err := tr.Stop(); err != nil {
+		t.Errorf("EventsDetector.Stop() error = %v", err)
+	}
+
+	if want := []uint64{1, 2, 3, 4, 5, 6, 7, 8}; !reflect.DeepEqual(tracked, want) {
+		t.Errorf("EventsDetector.Track() timestamps = %v, want %v", tracked, want)
+	}
+
+	if want := []any{1, 2, 3, 4, 5, 6, 7, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EventsDetector.ReadAsInterface() tracked = %v, want %v", got, want)
+	}
+}
+
+// BenchmarkEventsDetector_Workers measures the events parsed per second by the workers, for
+// the orderings and an increasing number of workers.
+func BenchmarkEventsDetector_Workers(b *testing.B) {
+	// the events of 64 processors and threads, so that they are spread across the workers
+	var events [][]byte
+	for k := 0; k < 64; k++ {
+		events = append(events, closeEvent(b, uint16(k), uint32(1000+k), 1))
+	}
+
+	for _, ordering := range []Ordering{Unordered, PerCpuOrder, PerThreadOrder} {
+		for _, workers := range []int{0, 1, 2, 4, 8, 16} {
+			if workers == 0 && ordering != Unordered {
+				continue
+			}
+
+			b.Run(fmt.Sprintf("%s/workers=%d", ordering, workers), func(b *testing.B) {
+				tr := NewEventsDetector().Workers(workers, ordering)
+				tr.Add(&streamDetector{events: events, n: b.N, closed: make(chan struct{})})
+
+				if err := tr.Start(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := tr.ReadAsInterface(); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.StopTimer()
+
+				if err := tr.Stop(); err != nil {
+					b.Fatal(err)
+				}
+			})
+		}
+	}
+}
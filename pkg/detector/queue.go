@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// OverflowPolicy selects what the readers do with an event when the event queue is full.
+type OverflowPolicy int
+
+const (
+	Block           OverflowPolicy = iota // Block waits for room in the queue, the kernel drops the events it can not buffer meanwhile.
+	DropNewest                            // DropNewest drops the event read.
+	DropOldest                            // DropOldest drops the oldest event of the queue to make room for the event read.
+	DropLowPriority                       // DropLowPriority drops the read & write events once the queue is 3/4 full, and blocks for the others.
+)
+
+// String returns the name of the overflow policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	case DropLowPriority:
+		return "priority"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(p))
+	}
+}
+
+// ParseOverflowPolicy returns the OverflowPolicy named s.
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	for _, p := range []OverflowPolicy{Block, DropNewest, DropOldest, DropLowPriority} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+
+	return Block, detectorErr.Throwf("unknown overflow policy %q, expected %s, %s, %s or %s", s, Block, DropNewest, DropOldest, DropLowPriority)
+}
+
+// lowPriorityEvents holds the events dropped first by the DropLowPriority policy, the data read
+// and written on file descriptors, which outnumber the process, file and network events.
+var lowPriorityEvents = map[eventparser.TarianEventsE]bool{
+	eventparser.TDE_SYSCALL_READ_E:   true,
+	eventparser.TDE_SYSCALL_READ_R:   true,
+	eventparser.TDE_SYSCALL_WRITE_E:  true,
+	eventparser.TDE_SYSCALL_WRITE_R:  true,
+	eventparser.TDE_SYSCALL_READV_E:  true,
+	eventparser.TDE_SYSCALL_READV_R:  true,
+	eventparser.TDE_SYSCALL_WRITEV_E: true,
+	eventparser.TDE_SYSCALL_WRITEV_R: true,
+	eventparser.TDE_SYSCALL_READ:     true,
+	eventparser.TDE_SYSCALL_WRITE:    true,
+	eventparser.TDE_SYSCALL_READV:    true,
+	eventparser.TDE_SYSCALL_WRITEV:   true,
+}
+
+// enqueue sends the event read to the event queue according to the overflow policy. It returns
+// false if ctx was cancelled while waiting for room in the queue.
+func (t *EventsDetector) enqueue(ctx context.Context, r detectorReadReturn) bool {
+	switch t.overflow {
+	case DropNewest:
+		select {
+		case t.eventQueue <- r:
+		default:
+			t.drop(r)
+		}
+
+		return true
+	case DropOldest:
+		for {
+			select {
+			case t.eventQueue <- r:
+				return true
+			default:
+			}
+
+			// the consumers may have emptied the queue meanwhile
+			select {
+			case old := <-t.eventQueue:
+				t.drop(old)
+			default:
+			}
+		}
+	case DropLowPriority:
+		if len(t.eventQueue) >= cap(t.eventQueue)*3/4 && t.lowPriority(r) {
+			t.drop(r)
+			return true
+		}
+	}
+
+	select {
+	case t.eventQueue <- r:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// lowPriority reports whether the event is dropped first by the DropLowPriority policy.
+func (t *EventsDetector) lowPriority(r detectorReadReturn) bool {
+	if r.err != nil {
+		return false
+	}
+
+	id, err := eventparser.GetEventId(r.eventData)
+
+	return err == nil && lowPriorityEvents[id]
+}
+
+// drop counts the event dropped by its event name.
+func (t *EventsDetector) drop(r detectorReadReturn) {
+	name := "read_error"
+	if r.err == nil {
+		name = "unknown"
+		if id, err := eventparser.GetEventId(r.eventData); err == nil {
+			if event, ok := eventparser.Events[id]; ok {
+				name = event.GetName()
+			}
+		}
+	}
+
+	t.dropMu.Lock()
+	defer t.dropMu.Unlock()
+
+	if t.dropCount == nil {
+		t.dropCount = make(map[string]int)
+	}
+
+	t.dropCount[name]++
+}
+
+// GetDropCount returns the number of events dropped by the overflow policy, by event name.
+func (t *EventsDetector) GetDropCount() map[string]int {
+	t.dropMu.Lock()
+	defer t.dropMu.Unlock()
+
+	count := make(map[string]int, len(t.dropCount))
+	for name, n := range t.dropCount {
+		count[name] = n
+	}
+
+	return count
+}
@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// TestParseOverflowPolicy tests the ParseOverflowPolicy function.
+func TestParseOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    OverflowPolicy
+		wantErr bool
+	}{
+		{name: "block", s: "block", want: Block},
+		{name: "drop newest", s: "drop-newest", want: DropNewest},
+		{name: "drop oldest", s: "drop-oldest", want: DropOldest},
+		{name: "priority", s: "priority", want: DropLowPriority},
+		{name: "unknown", s: "drop", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOverflowPolicy(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseOverflowPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseOverflowPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEventsDetector_enqueue tests the enqueue function. It checks the events queued and dropped
+// by every overflow policy when the event queue is full.
+func TestEventsDetector_enqueue(t *testing.T) {
+	eventparser.LoadTarianEvents()
+
+	event := func(id eventparser.TarianEventsE, ts uint64) detectorReadReturn {
+		data := closeEvent(t, 0, 0, ts)
+		binary.LittleEndian.PutUint32(data, uint32(id))
+
+		return detectorReadReturn{eventData: data}
+	}
+
+	read := event(eventparser.TDE_SYSCALL_READ_E, 1)
+	execve := event(eventparser.TDE_SYSCALL_EXECVE_E, 2)
+	closeE := event(eventparser.TDE_SYSCALL_CLOSE_E, 3)
+
+	tests := []struct {
+		name      string
+		policy    OverflowPolicy
+		queued    []detectorReadReturn
+		event     detectorReadReturn
+		wantOk    bool
+		wantQueue []detectorReadReturn
+		wantDrops map[string]int
+	}{
+		{
+			name:      "block until cancelled",
+			policy:    Block,
+			queued:    []detectorReadReturn{read, execve},
+			event:     closeE,
+			wantQueue: []detectorReadReturn{read, execve},
+			wantDrops: map[string]int{},
+		},
+		{
+			name:      "drop newest",
+			policy:    DropNewest,
+			queued:    []detectorReadReturn{read, execve},
+			event:     closeE,
+			wantOk:    true,
+			wantQueue: []detectorReadReturn{read, execve},
+			wantDrops: map[string]int{"sys_close_entry": 1},
+		},
+		{
+			name:      "drop oldest",
+			policy:    DropOldest,
+			queued:    []detectorReadReturn{read, execve},
+			event:     closeE,
+			wantOk:    true,
+			wantQueue: []detectorReadReturn{execve, closeE},
+			wantDrops: map[string]int{"sys_read_entry": 1},
+		},
+		{
+			name:      "drop low priority",
+			policy:    DropLowPriority,
+			queued:    []detectorReadReturn{execve, closeE},
+			event:     read,
+			wantOk:    true,
+			wantQueue: []detectorReadReturn{execve, closeE},
+			wantDrops: map[string]int{"sys_read_entry": 1},
+		},
+		{
+			name:      "block high priority",
+			policy:    DropLowPriority,
+			queued:    []detectorReadReturn{read, read},
+			event:     execve,
+			wantQueue: []detectorReadReturn{read, read},
+			wantDrops: map[string]int{},
+		},
+		{
+			name:      "drop read error",
+			policy:    DropNewest,
+			queued:    []detectorReadReturn{read, read},
+			event:     detectorReadReturn{err: errors.New("read failed")},
+			wantOk:    true,
+			wantQueue: []detectorReadReturn{read, read},
+			wantDrops: map[string]int{"read_error": 1},
+		},
+		{
+			name:      "room in the queue",
+			policy:    DropNewest,
+			queued:    []detectorReadReturn{read},
+			event:     execve,
+			wantOk:    true,
+			wantQueue: []detectorReadReturn{read, execve},
+			wantDrops: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewEventsDetector().QueueSize(2, tt.policy)
+			for _, r := range tt.queued {
+				tr.eventQueue <- r
+			}
+
+			// a full queue blocks until ctx is cancelled
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			if ok := tr.enqueue(ctx, tt.event); ok != tt.wantOk {
+				t.Errorf("EventsDetector.enqueue() = %v, want %v", ok, tt.wantOk)
+			}
+
+			close(tr.eventQueue)
+
+			var queue []detectorReadReturn
+			for r := range tr.eventQueue {
+				queue = append(queue, r)
+			}
+
+			if !reflect.DeepEqual(queue, tt.wantQueue) {
+				t.Errorf("EventsDetector.enqueue() queue = %v, want %v", queue, tt.wantQueue)
+			}
+
+			if got := tr.GetDropCount(); !reflect.DeepEqual(got, tt.wantDrops) {
+				t.Errorf("EventsDetector.GetDropCount() = %v, want %v", got, tt.wantDrops)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"container/heap"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// eventHeap is a min-heap of parsed events ordered by their kernel timestamp.
+type eventHeap []timedEvent
+
+// timedEvent is a parsed event and its kernel timestamp.
+type timedEvent struct {
+	ts    uint64
+	event parsedEvent
+}
+
+func (h eventHeap) Len() int           { return len(h) }
+func (h eventHeap) Less(i, j int) bool { return h[i].ts < h[j].ts }
+func (h eventHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *eventHeap) Push(x any)        { *h = append(*h, x.(timedEvent)) }
+func (h *eventHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return e
+}
+
+// monotonicNow returns the time of the clock of the kernel timestamps, CLOCK_MONOTONIC, in nanoseconds.
+func monotonicNow() uint64 {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return 0
+	}
+
+	return uint64(ts.Nano())
+}
+
+// reorder buffers the events of in and sends them to out sorted by their kernel timestamp. An event
+// is held until the kernel clock passed its timestamp by the window, so that the events captured
+// before it on the other processors arrive first. The events arriving after an event with a later
+// timestamp was sent are late: they are counted and sent right away. The events without timestamp,
+// the read and parse errors, are not delayed. out is closed once in is closed and drained.
+func (t *EventsDetector) reorder(in <-chan parsedEvent, out chan<- parsedEvent) {
+	var (
+		pending eventHeap
+		last    uint64 // timestamp of the last event sent
+	)
+
+	flush := func(watermark uint64) {
+		for len(pending) != 0 && pending[0].ts <= watermark {
+			e := heap.Pop(&pending).(timedEvent)
+			last = e.ts
+			out <- e.event
+		}
+	}
+
+	ticker := time.NewTicker(max(t.window/4, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case p, ok := <-in:
+			if !ok {
+				flush(^uint64(0))
+				close(out)
+
+				return
+			}
+
+			ts, ok := p.record["timestamp"].(uint64)
+			if p.err != nil || !ok {
+				out <- p
+				continue
+			}
+
+			if ts < last {
+				t.lateEvents.Add(1)
+				out <- p
+				continue
+			}
+
+			heap.Push(&pending, timedEvent{ts: ts, event: p})
+		case <-ticker.C:
+		}
+
+		flush(monotonicNow() - uint64(t.window))
+	}
+}
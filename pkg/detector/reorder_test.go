@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package detector
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestEventsDetector_reorder tests the reorder function. It checks that the events are sorted by
+// timestamp within the window, and that the events arriving after it are counted as late.
+func TestEventsDetector_reorder(t *testing.T) {
+	// the timestamps are offsets from the start of the test on the kernel clock
+	base := monotonicNow()
+	event := func(ts uint64) parsedEvent {
+		return parsedEvent{record: map[string]any{"timestamp": base + ts}, read: true}
+	}
+
+	tests := []struct {
+		name     string
+		window   time.Duration
+		batches  [][]parsedEvent // batches sent after the previous batch is received
+		want     []uint64
+		wantLate uint64
+	}{
+		{
+			name:   "sorted within the window",
+			window: time.Hour,
+			batches: [][]parsedEvent{
+				{event(3), event(1), event(4), event(2)},
+			},
+			want: []uint64{1, 2, 3, 4},
+		},
+		{
+			name:   "late event",
+			window: 50 * time.Millisecond,
+			batches: [][]parsedEvent{
+				{event(5), event(3)},
+				{event(1), event(7)},
+			},
+			want:     []uint64{3, 5, 1, 7},
+			wantLate: 1,
+		},
+		{
+			name:   "errors are not delayed",
+			window: time.Hour,
+			batches: [][]parsedEvent{
+				{event(2), {record: map[string]any{}, err: errors.New("read failed")}, event(1)},
+			},
+			want: []uint64{0, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewEventsDetector().Reorder(tt.window)
+
+			in := make(chan parsedEvent)
+			out := make(chan parsedEvent, len(tt.want))
+			go tr.reorder(in, out)
+
+			var got []uint64
+			for i, batch := range tt.batches {
+				for _, e := range batch {
+					in <- e
+				}
+
+				if i == len(tt.batches)-1 {
+					close(in)
+				}
+
+				// the events past the window are sent on the following tick, the others once in is closed
+				for range batch {
+					e := <-out
+					ts, ok := e.record["timestamp"].(uint64)
+					if ok {
+						ts -= base
+					}
+
+					got = append(got, ts)
+				}
+			}
+
+			if _, ok := <-out; ok {
+				t.Errorf("EventsDetector.reorder() out not closed")
+			}
+
+			for i := range tt.want {
+				if i >= len(got) || got[i] != tt.want[i] {
+					t.Fatalf("EventsDetector.reorder() = %v, want %v", got, tt.want)
+				}
+			}
+
+			if tr.GetLateCount() != tt.wantLate {
+				t.Errorf("EventsDetector.GetLateCount() = %v, want %v", tr.GetLateCount(), tt.wantLate)
+			}
+		})
+	}
+}
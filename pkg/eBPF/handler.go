@@ -4,17 +4,53 @@
 package ebpf
 
 import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+
 	"github.com/cilium/ebpf/link"
 	"github.com/intelops/tarian-detector/pkg/err"
 )
 
 var handlerErr = err.New("ebpf.handler")
 
+// PauseFunc pauses, or resumes, the events of the named probe. The programs of a paused
+// probe stay attached.
+type PauseFunc func(probe string, paused bool) error
+
+// StatsFunc returns the counters the eBPF programs keep in the kernel, by counter name.
+type StatsFunc func() (map[string]uint64, error)
+
+// ProbeState describes the programs of a probe managed by a Handler.
+type ProbeState struct {
+	Name     string `json:"name"`     // Name of the probe.
+	Programs int    `json:"programs"` // Number of programs of the probe.
+	Attached int    `json:"attached"` // Number of programs of the probe attached.
+	Paused   bool   `json:"paused"`   // Whether the events of the probe are paused.
+}
+
 // Handler represents an eBPF handler. It includes the name of the handler, a list of map readers, and a list of probe links.
 type Handler struct {
-	name       string      // Name of the handler
-	mapReaders []any       // List of map readers
-	probeLinks []link.Link // List of probe links
+	name       string          // Name of the handler
+	mapReaders []any           // List of map readers
+	probeLinks []link.Link     // List of probe links
+	sources    []ProgramSource // Sources of the programs discovered at runtime
+	pruners    []ProgramPruner // Pruners of the programs discovered at runtime
+
+	programs []*ProgramInfo             // Programs of the named probes, attached or not
+	links    map[*ProgramInfo]link.Link // Links of the attached programs of the named probes
+	detached map[string]bool            // Probes detached at runtime, their new programs are not attached
+	paused   map[string]bool            // Probes paused at runtime
+	pause    PauseFunc                  // Pauses the events of a probe, nil if not supported
+	prepared []*ProgramInfo             // Programs of the named probes attached by Module.Prepare
+
+	stats       StatsFunc     // Reads the kernel counters, nil if there are none
+	lostSamples atomic.Uint64 // Number of samples lost by the perf event readers
+	closed      bool          // Whether the probes are detached and the map readers closed
+
+	mu sync.Mutex // guards the probes, which change on Refresh and at runtime
 }
 
 // NewHandler creates a new eBPF handler with the given name.
@@ -28,9 +64,302 @@ func NewHandler(n string) *Handler {
 
 // AddProbeLink adds a probe link to the handler.
 func (h *Handler) AddProbeLink(l link.Link) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.probeLinks = append(h.probeLinks, l)
 }
 
+// addProgram attaches the program if it should be attached, and keeps track of it
+// if it belongs to a probe. The programs discovered once the handler is closed are
+// ignored, so that no link outlives Close.
+func (h *Handler) addProgram(prog *ProgramInfo) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil
+	}
+
+	if len(prog.probe) != 0 {
+		h.programs = append(h.programs, prog)
+	}
+
+	if !prog.shouldAttach || h.detached[prog.probe] {
+		return nil
+	}
+
+	return h.attach(prog)
+}
+
+// attach attaches the program and records its link. The caller holds h.mu.
+func (h *Handler) attach(prog *ProgramInfo) error {
+	pL, err := prog.hook.AttachProbe(prog.name)
+	if err != nil {
+		return err
+	}
+
+	h.probeLinks = append(h.probeLinks, pL)
+
+	if len(prog.probe) != 0 {
+		if h.links == nil {
+			h.links = make(map[*ProgramInfo]link.Link)
+		}
+
+		h.links[prog] = pL
+	}
+
+	return nil
+}
+
+// Refresh attaches the programs newly discovered by the program sources of the module, and
+// detaches and forgets those its program pruners return. A failing program does not prevent
+// the others from being attached, all the errors are returned. It does nothing once the
+// handler is closed.
+func (h *Handler) Refresh() error {
+	if h.isClosed() {
+		return nil
+	}
+
+	var errs []error
+	for _, src := range h.sources {
+		progs, err := src()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		for _, prog := range progs {
+			if err := h.addProgram(prog); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, prune := range h.pruners {
+		for _, prog := range prune() {
+			if err := h.removeProgram(prog); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) != 0 {
+		return handlerErr.Throwf("%v", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// removeProgram detaches the program, if it is attached, and forgets it.
+func (h *Handler) removeProgram(prog *ProgramInfo) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.programs = slices.DeleteFunc(h.programs, func(p *ProgramInfo) bool { return p == prog })
+	h.prepared = slices.DeleteFunc(h.prepared, func(p *ProgramInfo) bool { return p == prog })
+
+	l, ok := h.links[prog]
+	if !ok {
+		return nil
+	}
+
+	delete(h.links, prog)
+	h.probeLinks = slices.DeleteFunc(h.probeLinks, func(pL link.Link) bool { return pL == l })
+
+	return detachProbe(l)
+}
+
+// isClosed reports whether the handler is closed.
+func (h *Handler) isClosed() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.closed
+}
+
+// Attach attaches the programs of the named probe which are not attached, including
+// those disabled when the module was prepared.
+func (h *Handler) Attach(probe string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return handlerErr.Throwf("%s is closed", h.name)
+	}
+
+	progs := h.probePrograms(probe)
+	if len(progs) == 0 {
+		return handlerErr.Throwf("unknown probe %q", probe)
+	}
+
+	delete(h.detached, probe)
+
+	for _, prog := range progs {
+		if _, ok := h.links[prog]; ok {
+			continue
+		}
+
+		if err := h.attach(prog); err != nil {
+			return handlerErr.Throwf("%v", err)
+		}
+	}
+
+	return nil
+}
+
+// Detach detaches the programs of the named probe. The programs stay loaded, so that
+// the probe can be attached again, and those discovered later are not attached.
+func (h *Handler) Detach(probe string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	progs := h.probePrograms(probe)
+	if len(progs) == 0 {
+		return handlerErr.Throwf("unknown probe %q", probe)
+	}
+
+	if h.detached == nil {
+		h.detached = make(map[string]bool)
+	}
+
+	h.detached[probe] = true
+
+	for _, prog := range progs {
+		l, ok := h.links[prog]
+		if !ok {
+			continue
+		}
+
+		if err := detachProbe(l); err != nil {
+			return handlerErr.Throwf("%v", err)
+		}
+
+		delete(h.links, prog)
+		h.probeLinks = slices.DeleteFunc(h.probeLinks, func(pL link.Link) bool { return pL == l })
+	}
+
+	return nil
+}
+
+// Pause stops the events of the named probe, its programs stay attached.
+func (h *Handler) Pause(probe string) error {
+	return h.setPaused(probe, true)
+}
+
+// Resume restarts the events of the named probe paused with Pause.
+func (h *Handler) Resume(probe string) error {
+	return h.setPaused(probe, false)
+}
+
+// setPaused pauses or resumes the events of the named probe.
+func (h *Handler) setPaused(probe string, paused bool) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.probePrograms(probe)) == 0 {
+		return handlerErr.Throwf("unknown probe %q", probe)
+	}
+
+	if h.pause == nil {
+		return handlerErr.Throwf("pausing probes is not supported by %s", h.name)
+	}
+
+	if err := h.pause(probe, paused); err != nil {
+		return handlerErr.Throwf("%v", err)
+	}
+
+	if h.paused == nil {
+		h.paused = make(map[string]bool)
+	}
+
+	h.paused[probe] = paused
+
+	return nil
+}
+
+// Probes returns the state of the probes of the handler, sorted by name.
+func (h *Handler) Probes() []ProbeState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	states := make(map[string]*ProbeState)
+	for _, prog := range h.programs {
+		st, ok := states[prog.probe]
+		if !ok {
+			st = &ProbeState{Name: prog.probe, Paused: h.paused[prog.probe]}
+			states[prog.probe] = st
+		}
+
+		st.Programs++
+		if _, ok := h.links[prog]; ok {
+			st.Attached++
+		}
+	}
+
+	probes := make([]ProbeState, 0, len(states))
+	for _, st := range states {
+		probes = append(probes, *st)
+	}
+
+	sort.Slice(probes, func(i, j int) bool { return probes[i].Name < probes[j].Name })
+
+	return probes
+}
+
+// setPrepared records the programs attached so far, Ready checks they stay attached.
+func (h *Handler) setPrepared() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.prepared = make([]*ProgramInfo, 0, len(h.links))
+	for _, prog := range h.programs {
+		if _, ok := h.links[prog]; ok {
+			h.prepared = append(h.prepared, prog)
+		}
+	}
+}
+
+// Ready returns an error unless the programs attached by Module.Prepare are attached, but for
+// the probes detached at runtime, and the map readers are open.
+func (h *Handler) Ready() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return handlerErr.Throwf("%s is closed", h.name)
+	}
+
+	if len(h.mapReaders) == 0 {
+		return handlerErr.Throwf("%s has no map reader", h.name)
+	}
+
+	var probes []string
+	for _, prog := range h.prepared {
+		if _, ok := h.links[prog]; !ok && !h.detached[prog.probe] && !slices.Contains(probes, prog.probe) {
+			probes = append(probes, prog.probe)
+		}
+	}
+
+	if len(probes) != 0 {
+		sort.Strings(probes)
+		return handlerErr.Throwf("programs of the probes %v are not attached", probes)
+	}
+
+	return nil
+}
+
+// probePrograms returns the programs of the named probe. The caller holds h.mu.
+func (h *Handler) probePrograms(probe string) []*ProgramInfo {
+	var progs []*ProgramInfo
+	for _, prog := range h.programs {
+		if prog.probe == probe {
+			progs = append(progs, prog)
+		}
+	}
+
+	return progs
+}
+
 // AddMapReaders adds map readers to the handler.
 func (h *Handler) AddMapReaders(mrs []any) {
 	h.mapReaders = append(h.mapReaders, mrs...)
@@ -38,21 +367,51 @@ func (h *Handler) AddMapReaders(mrs []any) {
 
 // ReadAsInterface returns a slice of functions that read data from maps.
 func (h *Handler) ReadAsInterface() ([]func() ([]byte, error), error) {
-	return read(h.mapReaders)
+	return read(h.mapReaders, &h.lostSamples)
+}
+
+// LostSamples returns the number of samples the kernel could not write to the perf event
+// buffers, full because they were not read fast enough. The ring buffer drops are counted
+// by the eBPF programs, see Stats.
+func (h *Handler) LostSamples() uint64 {
+	return h.lostSamples.Load()
+}
+
+// Stats returns the counters the eBPF programs keep in the kernel, by counter name.
+func (h *Handler) Stats() (map[string]uint64, error) {
+	if h.stats == nil {
+		return nil, handlerErr.Throwf("kernel counters are not supported by %s", h.name)
+	}
+
+	stats, err := h.stats()
+	if err != nil {
+		return nil, handlerErr.Throwf("%v", err)
+	}
+
+	return stats, nil
 }
 
 // Count returns the number of probe links in the handler.
 func (h *Handler) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	return len(h.probeLinks)
 }
 
 // Close detaches probes and closes map readers.
 func (h *Handler) Close() error {
-	if err := detachProbes(h.probeLinks); err != nil {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	// the map readers are closed even if a probe fails to detach
+	if err := errors.Join(detachProbes(h.probeLinks), closeMapReaders(h.mapReaders)); err != nil {
 		return handlerErr.Throwf("%v", err)
 	}
 
-	return closeMapReaders(h.mapReaders)
+	return nil
 }
 
 // GetName returns the name of the handler.
@@ -65,7 +424,10 @@ func (h *Handler) GetMapReaders() []any {
 	return h.mapReaders
 }
 
-// GetProbeLinks returns the probe links associated with the handler.
+// GetProbeLinks returns a copy of the probe links associated with the handler.
 func (h *Handler) GetProbeLinks() []link.Link {
-	return h.probeLinks
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return slices.Clone(h.probeLinks)
 }
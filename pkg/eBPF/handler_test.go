@@ -4,6 +4,7 @@
 package ebpf
 
 import (
+	"errors"
 	"os"
 	"reflect"
 	"testing"
@@ -190,6 +191,358 @@ func TestHandler_Count(t *testing.T) {
 	}
 }
 
+// TestHandler_Refresh tests the Refresh function
+func TestHandler_Refresh(t *testing.T) {
+	prog := dummy_kprobe_prog(t)
+
+	tests := []struct {
+		name    string
+		sources []ProgramSource
+		closed  bool
+		calls   int
+		wantErr bool
+	}{
+		{
+			name:  "no sources",
+			calls: 0,
+		},
+		{
+			name: "closed handler",
+			sources: []ProgramSource{
+				func() ([]*ProgramInfo, error) {
+					return []*ProgramInfo{NewProgram(prog, NewHookInfo().Kprobe("vprintk"))}, nil
+				},
+			},
+			closed: true,
+			calls:  0,
+		},
+		{
+			name: "disabled programs",
+			sources: []ProgramSource{
+				func() ([]*ProgramInfo, error) {
+					return []*ProgramInfo{NewProgram(prog, NewHookInfo().Kprobe("vprintk")).Disable()}, nil
+				},
+			},
+			calls: 1,
+		},
+		{
+			name: "failing source",
+			sources: []ProgramSource{
+				func() ([]*ProgramInfo, error) { return nil, errors.New("discovery failed") },
+				func() ([]*ProgramInfo, error) { return nil, nil },
+			},
+			calls:   2,
+			wantErr: true,
+		},
+		{
+			name: "failing program",
+			sources: []ProgramSource{
+				func() ([]*ProgramInfo, error) {
+					return []*ProgramInfo{NewProgram(prog, NewHookInfo().Kprobe(""))}, nil
+				},
+			},
+			calls:   1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			sources := make([]ProgramSource, 0, len(tt.sources))
+			for _, src := range tt.sources {
+				sources = append(sources, func() ([]*ProgramInfo, error) {
+					calls++
+					return src()
+				})
+			}
+
+			h := &Handler{
+				name:    "test",
+				sources: sources,
+				closed:  tt.closed,
+			}
+
+			if err := h.Refresh(); (err != nil) != tt.wantErr {
+				t.Errorf("Handler.Refresh() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if calls != tt.calls {
+				t.Errorf("Handler.Refresh() calls = %v, want %v", calls, tt.calls)
+			}
+
+			if h.Count() != 0 {
+				t.Errorf("Handler.Refresh() links = %v, want %v", h.Count(), 0)
+			}
+		})
+	}
+}
+
+// TestHandler_removeProgram tests the removeProgram function through the pruners of Refresh.
+func TestHandler_removeProgram(t *testing.T) {
+	read := &ProgramInfo{probe: "ssl_read"}
+	write := &ProgramInfo{probe: "ssl_write"}
+
+	tests := []struct {
+		name    string
+		pruned  []*ProgramInfo
+		want    []ProbeState
+		wantErr bool
+	}{
+		{
+			name: "nothing pruned",
+			want: []ProbeState{{Name: "ssl_read", Programs: 1}, {Name: "ssl_write", Programs: 1}},
+		},
+		{
+			name:   "program not attached",
+			pruned: []*ProgramInfo{write},
+			want:   []ProbeState{{Name: "ssl_read", Programs: 1}},
+		},
+		{
+			name:   "unknown program",
+			pruned: []*ProgramInfo{{probe: "ssl_write"}},
+			want:   []ProbeState{{Name: "ssl_read", Programs: 1}, {Name: "ssl_write", Programs: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				name:     "test",
+				programs: []*ProgramInfo{read, write},
+				prepared: []*ProgramInfo{read, write},
+				pruners:  []ProgramPruner{func() []*ProgramInfo { return tt.pruned }},
+			}
+
+			if err := h.Refresh(); (err != nil) != tt.wantErr {
+				t.Errorf("Handler.Refresh() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := h.Probes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Handler.Probes() = %v, want %v", got, tt.want)
+			}
+
+			if len(h.prepared) != len(h.programs) {
+				t.Errorf("Handler.prepared = %v, want %v", h.prepared, h.programs)
+			}
+		})
+	}
+}
+
+// TestHandler_Probes tests the Probes function.
+func TestHandler_Probes(t *testing.T) {
+	prog := dummy_kprobe_prog(t)
+
+	tests := []struct {
+		name     string
+		programs []*ProgramInfo
+		paused   map[string]bool
+		want     []ProbeState
+	}{
+		{
+			name: "no programs",
+			want: []ProbeState{},
+		},
+		{
+			name: "programs of probes sorted by name",
+			programs: []*ProgramInfo{
+				NewProgram(prog, NewHookInfo().Kprobe("vprintk")).Probe("write").Disable(),
+				NewProgram(prog, NewHookInfo().Kprobe("vprintk")).Probe("read").Disable(),
+				NewProgram(prog, NewHookInfo().Kprobe("vprintk")).Probe("read").Disable(),
+				NewProgram(prog, NewHookInfo().Kprobe("vprintk")).Disable(),
+			},
+			paused: map[string]bool{"write": true},
+			want: []ProbeState{
+				{Name: "read", Programs: 2},
+				{Name: "write", Programs: 1, Paused: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{name: "test", paused: tt.paused}
+			for _, prog := range tt.programs {
+				if err := h.addProgram(prog); err != nil {
+					t.Fatalf("Handler.addProgram() error = %v", err)
+				}
+			}
+
+			if got := h.Probes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Handler.Probes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHandler_Pause tests the Pause and Resume functions.
+func TestHandler_Pause(t *testing.T) {
+	prog := dummy_kprobe_prog(t)
+
+	tests := []struct {
+		name    string
+		probe   string
+		pause   PauseFunc
+		wantErr bool
+	}{
+		{
+			name:  "known probe",
+			probe: "read",
+			pause: func(probe string, paused bool) error { return nil },
+		},
+		{
+			name:    "unknown probe",
+			probe:   "write",
+			pause:   func(probe string, paused bool) error { return nil },
+			wantErr: true,
+		},
+		{
+			name:    "pausing not supported",
+			probe:   "read",
+			wantErr: true,
+		},
+		{
+			name:    "failing pause",
+			probe:   "read",
+			pause:   func(probe string, paused bool) error { return errors.New("map update failed") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{name: "test", pause: tt.pause}
+			if err := h.addProgram(NewProgram(prog, NewHookInfo().Kprobe("vprintk")).Probe("read").Disable()); err != nil {
+				t.Fatalf("Handler.addProgram() error = %v", err)
+			}
+
+			if err := h.Pause(tt.probe); (err != nil) != tt.wantErr {
+				t.Errorf("Handler.Pause() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := h.Probes()[0].Paused; got == tt.wantErr {
+				t.Errorf("Handler.Pause() paused = %v, want %v", got, !tt.wantErr)
+			}
+
+			if err := h.Resume(tt.probe); (err != nil) != tt.wantErr {
+				t.Errorf("Handler.Resume() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if h.Probes()[0].Paused {
+				t.Errorf("Handler.Resume() paused = %v, want %v", true, false)
+			}
+		})
+	}
+}
+
+// TestHandler_Detach tests the Attach and Detach functions.
+func TestHandler_Detach(t *testing.T) {
+	prog := dummy_kprobe_prog(t)
+
+	tests := []struct {
+		name          string
+		probe         string
+		wantDetachErr bool
+		wantAttachErr bool
+	}{
+		{
+			name:          "unknown probe",
+			probe:         "write",
+			wantDetachErr: true,
+			wantAttachErr: true,
+		},
+		{
+			name:          "failing program",
+			probe:         "read",
+			wantAttachErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{name: "test"}
+			if err := h.addProgram(NewProgram(prog, NewHookInfo().Kprobe("")).Probe("read").Disable()); err != nil {
+				t.Fatalf("Handler.addProgram() error = %v", err)
+			}
+
+			if err := h.Detach(tt.probe); (err != nil) != tt.wantDetachErr {
+				t.Errorf("Handler.Detach() error = %v, wantErr %v", err, tt.wantDetachErr)
+			}
+
+			if err := h.Attach(tt.probe); (err != nil) != tt.wantAttachErr {
+				t.Errorf("Handler.Attach() error = %v, wantErr %v", err, tt.wantAttachErr)
+			}
+
+			if h.Count() != 0 {
+				t.Errorf("Handler.Attach() links = %v, want %v", h.Count(), 0)
+			}
+		})
+	}
+}
+
+// TestHandler_Ready tests the Ready function
+func TestHandler_Ready(t *testing.T) {
+	read := &ProgramInfo{probe: "read"}
+	write := &ProgramInfo{probe: "write"}
+
+	tests := []struct {
+		name       string
+		mapReaders []any
+		links      map[*ProgramInfo]link.Link
+		detached   map[string]bool
+		closed     bool
+		wantErr    bool
+	}{
+		{
+			name:       "programs attached",
+			mapReaders: []any{&perf.Reader{}},
+			links:      map[*ProgramInfo]link.Link{read: nil, write: nil},
+		},
+		{
+			name:       "probe detached at runtime",
+			mapReaders: []any{&perf.Reader{}},
+			links:      map[*ProgramInfo]link.Link{read: nil},
+			detached:   map[string]bool{"write": true},
+		},
+		{
+			name:       "program not attached",
+			mapReaders: []any{&perf.Reader{}},
+			links:      map[*ProgramInfo]link.Link{read: nil},
+			wantErr:    true,
+		},
+		{
+			name:    "no map reader",
+			links:   map[*ProgramInfo]link.Link{read: nil, write: nil},
+			wantErr: true,
+		},
+		{
+			name:       "closed",
+			mapReaders: []any{&perf.Reader{}},
+			links:      map[*ProgramInfo]link.Link{read: nil, write: nil},
+			closed:     true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				name:       "test",
+				mapReaders: tt.mapReaders,
+				programs:   []*ProgramInfo{read, write},
+				prepared:   []*ProgramInfo{read, write},
+				links:      tt.links,
+				detached:   tt.detached,
+				closed:     tt.closed,
+			}
+
+			if err := h.Ready(); (err != nil) != tt.wantErr {
+				t.Errorf("Handler.Ready() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // TestHandler_ReadAsInterface tests the ReadAsInterface function
 func TestHandler_ReadAsInterface(t *testing.T) {
 	mapP := dummy_perf_map(t)
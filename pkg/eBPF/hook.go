@@ -4,6 +4,7 @@
 package ebpf
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/cilium/ebpf"
@@ -16,10 +17,11 @@ var hookErr = err.New("ebpf.hook")
 // HookInfoType is an integer type used to represent different types of eBPF hooks.
 type HookInfoType int
 
-// HookInfo represents information about an eBPF hook. It includes the type of the eBPF hook, the group name (required for Tracepoint type hooks), the name of the hook, and options for the hook (which vary based on the hook type).
+// HookInfo represents information about an eBPF hook. It includes the type of the eBPF hook, the group name (required for Tracepoint type hooks), the path of the executable (required for Uprobe type hooks), the name of the hook, and options for the hook (which vary based on the hook type).
 type HookInfo struct {
 	hookType HookInfoType // Type of the eBPF hook
 	group    string       // Group name, required for Tracepoint type hooks
+	path     string       // Path of the executable, required for Uprobe type hooks
 	name     string       // Name of the hook
 	opts     any          // Options for the hook, varies based on the hook type
 }
@@ -31,6 +33,13 @@ const (
 	Kprobe
 	Kretprobe
 	Cgroup
+	Fentry
+	Fexit
+	FmodRet
+	Lsm
+	Uprobe
+	Uretprobe
+	TailCall
 )
 
 const (
@@ -44,6 +53,7 @@ func NewHookInfo() *HookInfo {
 	return &HookInfo{
 		name:     "",
 		group:    "",
+		path:     "",
 		opts:     nil,
 		hookType: -1,
 	}
@@ -108,6 +118,133 @@ func (hi *HookInfo) Cgroup(op link.CgroupOptions) *HookInfo {
 	return hi
 }
 
+// Fentry sets the HookInfo instance to represent a Fentry type hook. The traced
+// function n is resolved from the program's BTF when the program is loaded.
+func (hi *HookInfo) Fentry(n string, op ...*link.TracingOptions) *HookInfo {
+	return hi.tracing(Fentry, n, op...)
+}
+
+// Fexit sets the HookInfo instance to represent a Fexit type hook. The program
+// receives the arguments and the return value of the traced function n.
+func (hi *HookInfo) Fexit(n string, op ...*link.TracingOptions) *HookInfo {
+	return hi.tracing(Fexit, n, op...)
+}
+
+// FmodRet sets the HookInfo instance to represent a FmodRet type hook, which can
+// override the return value of the traced function n.
+func (hi *HookInfo) FmodRet(n string, op ...*link.TracingOptions) *HookInfo {
+	return hi.tracing(FmodRet, n, op...)
+}
+
+// tracing sets the HookInfo instance to represent a BTF trampoline based hook.
+func (hi *HookInfo) tracing(hit HookInfoType, n string, op ...*link.TracingOptions) *HookInfo {
+	if len(op) > 0 {
+		hi.opts = op[0]
+	} else {
+		hi.opts = &link.TracingOptions{}
+	}
+
+	hi.hookType = hit
+	hi.name = n
+
+	return hi
+}
+
+// Lsm sets the HookInfo instance to represent a Lsm type hook. The LSM hook is
+// resolved from the program's BTF when the program is loaded.
+func (hi *HookInfo) Lsm(op ...link.LSMOptions) *HookInfo {
+	if len(op) > 0 {
+		hi.opts = op[0]
+	} else {
+		hi.opts = link.LSMOptions{}
+	}
+
+	hi.hookType = Lsm
+
+	return hi
+}
+
+// Uprobe sets the HookInfo instance to represent a Uprobe type hook on the symbol n of the
+// executable or library at path. The hook is limited to a single process by setting the PID
+// of the options, see k8s.ContainerPath for executables inside containers.
+func (hi *HookInfo) Uprobe(path string, n string, op ...*link.UprobeOptions) *HookInfo {
+	return hi.uprobe(Uprobe, path, n, op...)
+}
+
+// Uretprobe sets the HookInfo instance to represent a Uretprobe type hook on the symbol n of
+// the executable or library at path.
+func (hi *HookInfo) Uretprobe(path string, n string, op ...*link.UprobeOptions) *HookInfo {
+	return hi.uprobe(Uretprobe, path, n, op...)
+}
+
+// uprobe sets the HookInfo instance to represent a user space probe.
+func (hi *HookInfo) uprobe(hit HookInfoType, path string, n string, op ...*link.UprobeOptions) *HookInfo {
+	if len(op) > 0 {
+		hi.opts = op[0]
+	} else {
+		hi.opts = &link.UprobeOptions{}
+	}
+
+	hi.hookType = hit
+	hi.path = path
+	hi.name = n
+
+	return hi
+}
+
+// TailCallOptions are the options of a TailCall type hook.
+type TailCallOptions struct {
+	Map *ebpf.Map // Map is the program array the program is stored in.
+	Key uint32    // Key is the slot of the program in the array.
+}
+
+// TailCall sets the HookInfo instance to represent a slot of a program array, whose program is
+// called by a dispatcher program with bpf_tail_call, e.g. a handler of the raw_syscalls tracepoints.
+// The program is attached by storing it in the slot and detached by deleting the slot.
+func (hi *HookInfo) TailCall(op TailCallOptions) *HookInfo {
+	hi.hookType = TailCall
+	hi.opts = op
+
+	return hi
+}
+
+// tailCallLink is the link of a program stored in a slot of a program array.
+type tailCallLink struct {
+	link.Link // Link is nil, it only makes tailCallLink a link.Link.
+
+	progs *ebpf.Map // progs is the program array.
+	key   uint32    // key is the slot of the program.
+}
+
+// Update stores the program p in the slot.
+func (l *tailCallLink) Update(p *ebpf.Program) error {
+	return l.progs.Put(l.key, p)
+}
+
+// Pin is not supported by a slot of a program array.
+func (l *tailCallLink) Pin(string) error {
+	return fmt.Errorf("pin tail call: %w", ebpf.ErrNotSupported)
+}
+
+// Unpin is not supported by a slot of a program array.
+func (l *tailCallLink) Unpin() error {
+	return fmt.Errorf("unpin tail call: %w", ebpf.ErrNotSupported)
+}
+
+// Close deletes the slot, the dispatcher program no longer calls the program.
+func (l *tailCallLink) Close() error {
+	if err := l.progs.Delete(l.key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Info is not supported by a slot of a program array.
+func (l *tailCallLink) Info() (*link.Info, error) {
+	return nil, fmt.Errorf("tail call info: %w", ebpf.ErrNotSupported)
+}
+
 // AttachProbe attaches the eBPF program to the hook represented by the HookInfo instance.
 func (hi *HookInfo) AttachProbe(programName *ebpf.Program) (link.Link, error) {
 	switch hi.hookType {
@@ -155,6 +292,79 @@ func (hi *HookInfo) AttachProbe(programName *ebpf.Program) (link.Link, error) {
 		}
 
 		return link.AttachCgroup(opts)
+	case Fentry, Fexit, FmodRet:
+		if len(hi.name) == 0 {
+			return nil, hookErr.Throwf(ErrMissingOptionsForBpfHookType, "'Name'", hi.hookType)
+		}
+
+		opts, ok := hi.opts.(*link.TracingOptions)
+		if !ok {
+			return nil, hookErr.Throwf(ErrInvalidOptionsTypeForBpfHookType, &link.TracingOptions{}, hi.opts)
+		}
+
+		topts := link.TracingOptions{Program: programName}
+		if opts != nil {
+			topts = *opts
+			if topts.Program == nil {
+				topts.Program = programName
+			}
+		}
+
+		if topts.AttachType == ebpf.AttachNone {
+			topts.AttachType = hi.hookType.attachType()
+		}
+
+		return link.AttachTracing(topts)
+	case Lsm:
+		opts, ok := hi.opts.(link.LSMOptions)
+		if !ok {
+			return nil, hookErr.Throwf(ErrInvalidOptionsTypeForBpfHookType, link.LSMOptions{}, hi.opts)
+		}
+
+		if opts.Program == nil {
+			opts.Program = programName
+		}
+
+		return link.AttachLSM(opts)
+	case Uprobe, Uretprobe:
+		if len(hi.name) == 0 {
+			return nil, hookErr.Throwf(ErrMissingOptionsForBpfHookType, "'Name'", hi.hookType)
+		}
+
+		if len(hi.path) == 0 {
+			return nil, hookErr.Throwf(ErrMissingOptionsForBpfHookType, "'Path'", hi.hookType)
+		}
+
+		opts, ok := hi.opts.(*link.UprobeOptions)
+		if !ok {
+			return nil, hookErr.Throwf(ErrInvalidOptionsTypeForBpfHookType, &link.UprobeOptions{}, hi.opts)
+		}
+
+		ex, err := link.OpenExecutable(hi.path)
+		if err != nil {
+			return nil, hookErr.Throwf("%v", err)
+		}
+
+		if hi.hookType == Uprobe {
+			return ex.Uprobe(hi.name, programName, opts)
+		}
+
+		return ex.Uretprobe(hi.name, programName, opts)
+	case TailCall:
+		opts, ok := hi.opts.(TailCallOptions)
+		if !ok {
+			return nil, hookErr.Throwf(ErrInvalidOptionsTypeForBpfHookType, TailCallOptions{}, hi.opts)
+		}
+
+		if opts.Map == nil {
+			return nil, hookErr.Throwf(ErrMissingOptionsForBpfHookType, "'Map'", hi.hookType)
+		}
+
+		if err := opts.Map.Put(opts.Key, programName); err != nil {
+			return nil, hookErr.Throwf("%v", err)
+		}
+
+		return &tailCallLink{progs: opts.Map, key: opts.Key}, nil
 	default:
 		return nil, hookErr.Throwf(ErrInvalidBpfHookType, hi.hookType)
 	}
@@ -162,13 +372,18 @@ func (hi *HookInfo) AttachProbe(programName *ebpf.Program) (link.Link, error) {
 
 // detachProbes detaches all the probes represented by the links in the provided slice.
 func detachProbes(lns []link.Link) error {
+	var errs []error
 	for _, l := range lns {
 		err := detachProbe(l)
 		if err != nil {
-			return hookErr.Throwf("%v", err)
+			errs = append(errs, err)
 		}
 	}
 
+	if err := errors.Join(errs...); err != nil {
+		return hookErr.Throwf("%v", err)
+	}
+
 	return nil
 }
 
@@ -192,6 +407,11 @@ func (hi *HookInfo) GetHookGroup() string {
 	return hi.group
 }
 
+// GetHookPath returns the path of the executable of the hook represented by the HookInfo instance.
+func (hi *HookInfo) GetHookPath() string {
+	return hi.path
+}
+
 // GetOptions returns the opts of the hook represented by the HookInfo instance.
 func (hi *HookInfo) GetOptions() interface{} {
 	return hi.opts
@@ -210,7 +430,35 @@ func (hit HookInfoType) String() string {
 		return "Kretprobe"
 	case Cgroup:
 		return "Cgroup"
+	case Fentry:
+		return "Fentry"
+	case Fexit:
+		return "Fexit"
+	case FmodRet:
+		return "FmodRet"
+	case Lsm:
+		return "Lsm"
+	case Uprobe:
+		return "Uprobe"
+	case Uretprobe:
+		return "Uretprobe"
+	case TailCall:
+		return "TailCall"
 	default:
 		return fmt.Sprintf("unknown HookInfoType(%d)", int(hit))
 	}
 }
+
+// attachType returns the eBPF attach type of the tracing HookInfoType.
+func (hit HookInfoType) attachType() ebpf.AttachType {
+	switch hit {
+	case Fentry:
+		return ebpf.AttachTraceFEntry
+	case Fexit:
+		return ebpf.AttachTraceFExit
+	case FmodRet:
+		return ebpf.AttachModifyReturn
+	default:
+		return ebpf.AttachNone
+	}
+}
@@ -58,6 +58,46 @@ func TestHookInfo(t *testing.T) {
 			hi:   NewHookInfo().Cgroup(link.CgroupOptions{}),
 			want: "Cgroup",
 		},
+		{
+			name: "Fentry",
+			hi:   NewHookInfo().Fentry("name"),
+			want: "Fentry",
+		},
+		{
+			name: "Fexit",
+			hi:   NewHookInfo().Fexit("name"),
+			want: "Fexit",
+		},
+		{
+			name: "Fexit with options",
+			hi:   NewHookInfo().Fexit("name", nil),
+			want: "Fexit",
+		},
+		{
+			name: "FmodRet",
+			hi:   NewHookInfo().FmodRet("name"),
+			want: "FmodRet",
+		},
+		{
+			name: "Lsm",
+			hi:   NewHookInfo().Lsm(),
+			want: "Lsm",
+		},
+		{
+			name: "Uprobe",
+			hi:   NewHookInfo().Uprobe("/bin/bash", "readline"),
+			want: "Uprobe",
+		},
+		{
+			name: "Uretprobe with options",
+			hi:   NewHookInfo().Uretprobe("/bin/bash", "readline", &link.UprobeOptions{PID: 1}),
+			want: "Uretprobe",
+		},
+		{
+			name: "TailCall",
+			hi:   NewHookInfo().TailCall(TailCallOptions{Key: 3}),
+			want: "TailCall",
+		},
 	}
 
 	for _, tt := range tests {
@@ -158,6 +198,77 @@ func TestHookInfo_AttachProbe(t *testing.T) {
 			hi:      NewHookInfo().Cgroup(link.CgroupOptions{Path: "", Attach: 0, Program: prog}),
 			wantErr: true,
 		},
+		{
+			name:    "Fentry with missing name",
+			hi:      NewHookInfo().Fentry(""),
+			wantErr: true,
+		},
+		{
+			name: "Fexit with wrong options type",
+			hi: func() *HookInfo {
+				hi := NewHookInfo().Fexit("vprintk")
+				hi.opts = link.KprobeOptions{}
+				return hi
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "FmodRet with invalid program",
+			hi:      NewHookInfo().FmodRet("vprintk"),
+			wantErr: true,
+		},
+		{
+			name: "Lsm with wrong options type",
+			hi: func() *HookInfo {
+				hi := NewHookInfo().Lsm()
+				hi.opts = &link.LSMOptions{}
+				return hi
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "Lsm with invalid program",
+			hi:      NewHookInfo().Lsm(),
+			wantErr: true,
+		},
+		{
+			name:    "Uprobe with missing name",
+			hi:      NewHookInfo().Uprobe("/bin/bash", ""),
+			wantErr: true,
+		},
+		{
+			name:    "Uprobe with missing path",
+			hi:      NewHookInfo().Uprobe("", "readline"),
+			wantErr: true,
+		},
+		{
+			name: "Uretprobe with wrong options type",
+			hi: func() *HookInfo {
+				hi := NewHookInfo().Uretprobe("/bin/bash", "readline")
+				hi.opts = link.KprobeOptions{}
+				return hi
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "Uprobe with missing executable",
+			hi:      NewHookInfo().Uprobe("/nonexistent/binary", "readline"),
+			wantErr: true,
+		},
+		{
+			name: "TailCall with wrong options type",
+			hi: func() *HookInfo {
+				hi := NewHookInfo().TailCall(TailCallOptions{})
+				hi.opts = &TailCallOptions{}
+				return hi
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "TailCall with missing map",
+			hi:      NewHookInfo().TailCall(TailCallOptions{Key: 3}),
+			wantErr: true,
+		},
 		{
 			name:    "Invalid HookInfoType",
 			hi:      &HookInfo{hookType: HookInfoType(999)}, // An unknown HookInfoType
@@ -241,6 +352,34 @@ func TestHookInfo_GetHookGroup(t *testing.T) {
 	}
 }
 
+// TestHookInfo_GetHookPath tests the GetHookPath function
+func TestHookInfo_GetHookPath(t *testing.T) {
+	tests := []struct {
+		name string
+		hi   *HookInfo
+		want string
+	}{
+		{
+			name: "Uprobe",
+			hi:   NewHookInfo().Uprobe("/bin/bash", "readline"),
+			want: "/bin/bash",
+		},
+		{
+			name: "Kprobe",
+			hi:   NewHookInfo().Kprobe("name"),
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hi.GetHookPath(); got != tt.want {
+				t.Errorf("HookInfo.GetHookPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestHookInfo_GetOptions tests the GetOptions function
 func TestHookInfo_GetOptions(t *testing.T) {
 	tests := []struct {
@@ -317,6 +456,36 @@ func TestHookInfoType_String(t *testing.T) {
 			hit:  Cgroup,
 			want: "Cgroup",
 		},
+		{
+			name: "Fentry",
+			hit:  Fentry,
+			want: "Fentry",
+		},
+		{
+			name: "Fexit",
+			hit:  Fexit,
+			want: "Fexit",
+		},
+		{
+			name: "FmodRet",
+			hit:  FmodRet,
+			want: "FmodRet",
+		},
+		{
+			name: "Lsm",
+			hit:  Lsm,
+			want: "Lsm",
+		},
+		{
+			name: "Uprobe",
+			hit:  Uprobe,
+			want: "Uprobe",
+		},
+		{
+			name: "Uretprobe",
+			hit:  Uretprobe,
+			want: "Uretprobe",
+		},
 		{
 			name: "Unknown",
 			hit:  HookInfoType(999), // An unknown HookInfoType
@@ -4,8 +4,10 @@
 package ebpf
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 
 	"github.com/cilium/ebpf"
 	"github.com/cilium/ebpf/perf"
@@ -197,8 +199,9 @@ func (mi *MapInfo) perfReader() (*perf.Reader, error) {
 	return p, nil
 }
 
-// read creates functions to read from a list of readers.
-func read(readers []any) ([]func() ([]byte, error), error) {
+// read creates functions to read from a list of readers. The samples lost by the perf event
+// readers are added to lost.
+func read(readers []any, lost *atomic.Uint64) ([]func() ([]byte, error), error) {
 	var funcs []func() ([]byte, error)
 
 	for _, reader := range readers {
@@ -211,7 +214,7 @@ func read(readers []any) ([]func() ([]byte, error), error) {
 
 			funcs = append(funcs, f)
 		case *perf.Reader:
-			f, err := readPerf(r)
+			f, err := readPerf(r, lost)
 			if err != nil {
 				return nil, err
 			}
@@ -241,8 +244,9 @@ func readRingbuf(r *ringbuf.Reader) (func() ([]byte, error), error) {
 	}, nil
 }
 
-// readPerf creates a function to read from a perf event array eBPF map.
-func readPerf(pr *perf.Reader) (func() ([]byte, error), error) {
+// readPerf creates a function to read from a perf event array eBPF map. The samples lost,
+// reported by the records without sample, are added to lost.
+func readPerf(pr *perf.Reader, lost *atomic.Uint64) (func() ([]byte, error), error) {
 	if pr == nil {
 		return nil, mapErr.Throw(ErrNilMapReader)
 	}
@@ -253,30 +257,35 @@ func readPerf(pr *perf.Reader) (func() ([]byte, error), error) {
 			return nil, mapErr.Throwf("%v", err)
 		}
 
+		if record.LostSamples != 0 {
+			lost.Add(record.LostSamples)
+		}
+
 		return record.RawSample, nil
 	}, nil
 }
 
 // closeMapReaders closes all the readers in the provided slice.
 func closeMapReaders(readers []any) error {
+	var errs []error
 	for _, reader := range readers {
 		switch mr := reader.(type) {
 		case *perf.Reader:
 			err := mr.Close()
 			if err != nil {
-				return mapErr.Throwf("%v", err)
+				errs = append(errs, mapErr.Throwf("%v", err))
 			}
 		case *ringbuf.Reader:
 			err := mr.Close()
 			if err != nil {
-				return mapErr.Throwf("%v", err)
+				errs = append(errs, mapErr.Throwf("%v", err))
 			}
 		default:
-			return mapErr.Throwf(ErrUnsupportedMapReader, mr)
+			errs = append(errs, mapErr.Throwf(ErrUnsupportedMapReader, mr))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // GetMapType returns the type of the eBPF map.
@@ -15,11 +15,23 @@ type EbpfModule interface {
 	GetModule() (*Module, error)
 }
 
+// ProgramSource returns the eBPF programs discovered since its previous call, e.g. uprobes
+// on the executables of the processes started in the meantime.
+type ProgramSource func() ([]*ProgramInfo, error)
+
+// ProgramPruner returns the eBPF programs of a ProgramSource obsolete since its previous call,
+// e.g. uprobes on the files no longer mapped by any process, to be detached and forgotten.
+type ProgramPruner func() []*ProgramInfo
+
 // Module represents a module containing eBPF programs and maps.
 type Module struct {
-	name     string         // Name of the module.
-	programs []*ProgramInfo // Slice of eBPF program information.
-	ebpfMap  *MapInfo       // Information about the eBPF map.
+	name     string          // Name of the module.
+	programs []*ProgramInfo  // Slice of eBPF program information.
+	sources  []ProgramSource // Sources of the programs discovered at runtime.
+	pruners  []ProgramPruner // Pruners of the programs discovered at runtime.
+	pause    PauseFunc       // Pauses the events of a probe, nil if not supported.
+	stats    StatsFunc       // Reads the kernel counters of the module, nil if there are none.
+	ebpfMap  *MapInfo        // Information about the eBPF map.
 }
 
 // NewModule creates a new eBPF module with the given name.
@@ -36,6 +48,28 @@ func (m *Module) AddProgram(prog *ProgramInfo) {
 	m.programs = append(m.programs, prog)
 }
 
+// AddProgramSource appends a source of eBPF programs discovered at runtime. The source is
+// called by Prepare and on every Handler.Refresh.
+func (m *Module) AddProgramSource(src ProgramSource) {
+	m.sources = append(m.sources, src)
+}
+
+// AddProgramPruner appends a pruner of the eBPF programs discovered at runtime. The pruner is
+// called on every Handler.Refresh, after the sources.
+func (m *Module) AddProgramPruner(p ProgramPruner) {
+	m.pruners = append(m.pruners, p)
+}
+
+// Pauser sets the function pausing and resuming the events of the probes of the module.
+func (m *Module) Pauser(f PauseFunc) {
+	m.pause = f
+}
+
+// Stats sets the function reading the counters the eBPF programs of the module keep in the kernel.
+func (m *Module) Stats(f StatsFunc) {
+	m.stats = f
+}
+
 // Map sets the eBPF map for the module..
 func (m *Module) Map(mp *MapInfo) {
 	m.ebpfMap = mp
@@ -45,21 +79,21 @@ func (m *Module) Map(mp *MapInfo) {
 func (m *Module) Prepare() (*Handler, error) {
 	// Create a new handler with the provided module name.
 	handler := NewHandler(m.name)
+	handler.pause = m.pause
+	handler.stats = m.stats
 
 	// Attach programs to the kernel hook points
 	for _, prog := range m.programs {
-		hook := prog.hook
-
-		if !prog.shouldAttach {
-			continue
-		}
-
-		pL, err := hook.AttachProbe(prog.name)
-		if err != nil {
+		if err := handler.addProgram(prog); err != nil {
 			return nil, moduleErr.Throwf("%v", err)
 		}
+	}
 
-		handler.AddProbeLink(pL)
+	// Attach the programs discovered so far
+	handler.sources = m.sources
+	handler.pruners = m.pruners
+	if err := handler.Refresh(); err != nil {
+		return nil, moduleErr.Throwf("%v", err)
 	}
 
 	// Create map reader to receive data from the kernel
@@ -72,6 +106,9 @@ func (m *Module) Prepare() (*Handler, error) {
 		handler.AddMapReaders(mrs)
 	}
 
+	// Record the programs attached, they are checked by Handler.Ready
+	handler.setPrepared()
+
 	return handler, nil
 }
 
@@ -85,6 +122,11 @@ func (m *Module) GetPrograms() []*ProgramInfo {
 	return m.programs
 }
 
+// GetProgramSources returns the slice of program sources in the module.
+func (m *Module) GetProgramSources() []ProgramSource {
+	return m.sources
+}
+
 // GetMap returns the ebpfMap of the Module.
 func (m *Module) GetMap() *MapInfo {
 	return m.ebpfMap
@@ -108,6 +108,35 @@ func TestModule_AddProgram(t *testing.T) {
 	}
 }
 
+// TestModule_AddProgramSource tests the AddProgramSource function
+func TestModule_AddProgramSource(t *testing.T) {
+	src := func() ([]*ProgramInfo, error) { return nil, nil }
+
+	tests := []struct {
+		name    string
+		sources []ProgramSource
+		want    int
+	}{
+		{name: "first source", sources: nil, want: 1},
+		{name: "additional source", sources: []ProgramSource{src}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Module{
+				name:    "test",
+				sources: tt.sources,
+			}
+
+			m.AddProgramSource(src)
+
+			if len(m.GetProgramSources()) != tt.want {
+				t.Errorf("Module.AddProgramSource() = %v, want %v", len(m.GetProgramSources()), tt.want)
+			}
+		})
+	}
+}
+
 // TestModule_Map tests the Map function
 func TestModule_Map(t *testing.T) {
 	type fields struct {
@@ -10,6 +10,7 @@ type ProgramInfo struct {
 	name         *ebpf.Program // Pointer to the eBPF program.
 	hook         *HookInfo     // Pointer to the associated hook information.
 	shouldAttach bool          // Indicates whether the program should be attached.
+	probe        string        // Name of the probe the program belongs to, empty if none.
 }
 
 // NewProgram creates a new ProgramInfo instance.
@@ -35,6 +36,14 @@ func (pi *ProgramInfo) Disable() *ProgramInfo {
 	return pi
 }
 
+// Probe sets the name of the probe the program belongs to and returns the updated ProgramInfo.
+// The programs of a probe are attached, detached, paused and resumed together by the Handler.
+func (pi *ProgramInfo) Probe(n string) *ProgramInfo {
+	pi.probe = n
+
+	return pi
+}
+
 // GetHook returns the HookInfo associated with the ProgramInfo.
 func (pi *ProgramInfo) GetHook() *HookInfo {
 	return pi.hook
@@ -49,3 +58,8 @@ func (pi *ProgramInfo) GetName() *ebpf.Program {
 func (pi *ProgramInfo) GetShouldAttach() bool {
 	return pi.shouldAttach
 }
+
+// GetProbe returns the name of the probe the program belongs to.
+func (pi *ProgramInfo) GetProbe() string {
+	return pi.probe
+}
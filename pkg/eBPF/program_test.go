@@ -173,6 +173,48 @@ func TestProgramInfo_Disable(t *testing.T) {
 	}
 }
 
+// TestProgramInfo_Probe tests the Probe function.
+func TestProgramInfo_Probe(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe string
+		want  *ProgramInfo
+	}{
+		{
+			name:  "named probe",
+			probe: "read",
+			want: &ProgramInfo{
+				name:         &ebpf.Program{},
+				hook:         &HookInfo{},
+				shouldAttach: true,
+				probe:        "read",
+			},
+		},
+		{
+			name:  "empty probe",
+			probe: "",
+			want: &ProgramInfo{
+				name:         &ebpf.Program{},
+				hook:         &HookInfo{},
+				shouldAttach: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pi := NewProgram(&ebpf.Program{}, &HookInfo{})
+			if got := pi.Probe(tt.probe); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProgramInfo.Probe() = %v, want %v", got, tt.want)
+			}
+
+			if got := pi.GetProbe(); got != tt.probe {
+				t.Errorf("ProgramInfo.GetProbe() = %v, want %v", got, tt.probe)
+			}
+		})
+	}
+}
+
 // TestProgramInfo_GetHook tests the GetHook function
 func TestProgramInfo_GetHook(t *testing.T) {
 	type fields struct {
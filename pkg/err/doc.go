@@ -10,6 +10,6 @@
 //
 // There are three main methods associated with the Err struct:
 // - New: creates a new instance of Err with the specified caller and an empty message.
-// - Throwf: formats an error message according to a format specifier.
-// - Throw: updates the message of the Err with the passed message.
+// - Throwf: returns a new Err with an error message formatted according to a format specifier.
+// - Throw: returns a new Err with the passed message.
 package err
@@ -21,18 +21,21 @@ func New(caller string) *Err {
 }
 
 // Throwf formats an error message according to a format specifier and returns the error.
-// It updates the message of the Err with the formatted message.
+// It returns a new Err with the caller of e and the formatted message, leaving e unchanged
+// so that an Err shared by a package can be thrown from concurrent goroutines.
 func (e *Err) Throwf(format string, a ...any) *Err {
-	e.message = fmt.Sprintf(format, a...)
-
-	return e
+	return &Err{
+		caller:  e.caller,
+		message: fmt.Sprintf(format, a...),
+	}
 }
 
-// Throw updates the error message with the passed message and returns the error.
+// Throw returns a new Err with the caller of e and the passed message.
 func (e *Err) Throw(message string) *Err {
-	e.message = fmt.Sprint(message)
-
-	return e
+	return &Err{
+		caller:  e.caller,
+		message: fmt.Sprint(message),
+	}
 }
 
 // Error returns a string that represents the Err.
@@ -113,4 +113,55 @@ const (
 
 	TDE_SYSCALL_CONNECT_E TarianEventsE = 32 // TDE_SYSCALL_CONNECT_E represents the start of a connect syscall
 	TDE_SYSCALL_CONNECT_R TarianEventsE = 33 // TDE_SYSCALL_CONNECT_R represents the return of a connect syscall
+
+	TDE_SYSCALL_CLONE           TarianEventsE = 34 // TDE_SYSCALL_CLONE represents the entry arguments and the return value of a clone syscall
+	TDE_SYSCALL_CLOSE           TarianEventsE = 35 // TDE_SYSCALL_CLOSE represents the entry arguments and the return value of a close syscall
+	TDE_SYSCALL_READ            TarianEventsE = 36 // TDE_SYSCALL_READ represents the entry arguments and the return value of a read syscall
+	TDE_SYSCALL_WRITE           TarianEventsE = 37 // TDE_SYSCALL_WRITE represents the entry arguments and the return value of a write syscall
+	TDE_SYSCALL_OPEN            TarianEventsE = 38 // TDE_SYSCALL_OPEN represents the entry arguments and the return value of a open syscall
+	TDE_SYSCALL_READV           TarianEventsE = 39 // TDE_SYSCALL_READV represents the entry arguments and the return value of a readv syscall
+	TDE_SYSCALL_WRITEV          TarianEventsE = 40 // TDE_SYSCALL_WRITEV represents the entry arguments and the return value of a writev syscall
+	TDE_SYSCALL_OPENAT          TarianEventsE = 41 // TDE_SYSCALL_OPENAT represents the entry arguments and the return value of a openat syscall
+	TDE_SYSCALL_OPENAT2         TarianEventsE = 42 // TDE_SYSCALL_OPENAT2 represents the entry arguments and the return value of a openat2 syscall
+	TDE_SYSCALL_LISTEN          TarianEventsE = 43 // TDE_SYSCALL_LISTEN represents the entry arguments and the return value of a listen syscall
+	TDE_SYSCALL_SOCKET          TarianEventsE = 44 // TDE_SYSCALL_SOCKET represents the entry arguments and the return value of a socket syscall
+	TDE_SYSCALL_ACCEPT          TarianEventsE = 45 // TDE_SYSCALL_ACCEPT represents the entry arguments and the return value of a accept syscall
+	TDE_SYSCALL_BIND            TarianEventsE = 46 // TDE_SYSCALL_BIND represents the entry arguments and the return value of a bind syscall
+	TDE_SYSCALL_CONNECT         TarianEventsE = 47 // TDE_SYSCALL_CONNECT represents the entry arguments and the return value of a connect syscall
+	TDE_LSM_BPRM_CHECK_SECURITY TarianEventsE = 48 // TDE_LSM_BPRM_CHECK_SECURITY represents a LSM policy match on the execution of a file
+	TDE_LSM_FILE_OPEN           TarianEventsE = 49 // TDE_LSM_FILE_OPEN represents a LSM policy match on the opening of a file
+	TDE_LSM_SOCKET_CONNECT      TarianEventsE = 50 // TDE_LSM_SOCKET_CONNECT represents a LSM policy match on the connection of a socket
+	TDE_SSL_WRITE               TarianEventsE = 51 // TDE_SSL_WRITE represents the plaintext written to a OpenSSL tls connection
+	TDE_SSL_READ                TarianEventsE = 52 // TDE_SSL_READ represents the plaintext read from a OpenSSL tls connection
+	TDE_GO_TLS_WRITE            TarianEventsE = 53 // TDE_GO_TLS_WRITE represents the plaintext written to a Go crypto/tls connection
+	TDE_GO_TLS_READ             TarianEventsE = 54 // TDE_GO_TLS_READ represents the plaintext read from a Go crypto/tls connection
+	TDE_READLINE                TarianEventsE = 55 // TDE_READLINE represents the line read by an interactive shell
+	TDE_SCHED_PROCESS_FORK      TarianEventsE = 56 // TDE_SCHED_PROCESS_FORK represents the creation of a process or a thread by its parent
+	TDE_SCHED_PROCESS_EXEC      TarianEventsE = 57 // TDE_SCHED_PROCESS_EXEC represents the successful execution of a program by a process
+	TDE_SCHED_PROCESS_EXIT      TarianEventsE = 58 // TDE_SCHED_PROCESS_EXIT represents the exit of a process or a thread
+
+	TDE_SYSCALL_DUP_E TarianEventsE = 59 // TDE_SYSCALL_DUP_E represents the start of a dup syscall
+	TDE_SYSCALL_DUP_R TarianEventsE = 60 // TDE_SYSCALL_DUP_R represents the return of a dup syscall
+
+	TDE_SYSCALL_DUP2_E TarianEventsE = 61 // TDE_SYSCALL_DUP2_E represents the start of a dup2 syscall
+	TDE_SYSCALL_DUP2_R TarianEventsE = 62 // TDE_SYSCALL_DUP2_R represents the return of a dup2 syscall
+
+	TDE_SYSCALL_DUP3_E TarianEventsE = 63 // TDE_SYSCALL_DUP3_E represents the start of a dup3 syscall
+	TDE_SYSCALL_DUP3_R TarianEventsE = 64 // TDE_SYSCALL_DUP3_R represents the return of a dup3 syscall
+
+	TDE_SYSCALL_FCNTL_E TarianEventsE = 65 // TDE_SYSCALL_FCNTL_E represents the start of a fcntl syscall
+	TDE_SYSCALL_FCNTL_R TarianEventsE = 66 // TDE_SYSCALL_FCNTL_R represents the return of a fcntl syscall
+
+	TDE_SYSCALL_ACCEPT4_E TarianEventsE = 67 // TDE_SYSCALL_ACCEPT4_E represents the start of an accept4 syscall
+	TDE_SYSCALL_ACCEPT4_R TarianEventsE = 68 // TDE_SYSCALL_ACCEPT4_R represents the return of an accept4 syscall
+
+	TDE_SYSCALL_DUP     TarianEventsE = 69 // TDE_SYSCALL_DUP represents the entry arguments and the return value of a dup syscall
+	TDE_SYSCALL_DUP2    TarianEventsE = 70 // TDE_SYSCALL_DUP2 represents the entry arguments and the return value of a dup2 syscall
+	TDE_SYSCALL_DUP3    TarianEventsE = 71 // TDE_SYSCALL_DUP3 represents the entry arguments and the return value of a dup3 syscall
+	TDE_SYSCALL_FCNTL   TarianEventsE = 72 // TDE_SYSCALL_FCNTL represents the entry arguments and the return value of a fcntl syscall
+	TDE_SYSCALL_ACCEPT4 TarianEventsE = 73 // TDE_SYSCALL_ACCEPT4 represents the entry arguments and the return value of a accept4 syscall
+
+	// the events synthesized in userspace are numbered from MAX_TARIAN_EVENTS, beyond those of the eBPF programs
+	TDE_PROCESS_SNAPSHOT TarianEventsE = 128 // TDE_PROCESS_SNAPSHOT represents a process running when the detector started
+	TDE_FLOW             TarianEventsE = 129 // TDE_FLOW represents a connection of a process, from its connect, accept or listen to its close
 )
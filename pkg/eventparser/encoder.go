@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package eventparser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/intelops/tarian-detector/pkg/err"
+)
+
+var encoderErr = err.New("eventparser.encoder")
+
+// Encode returns the event id with the metadata md and the values of its parameters in the layout
+// the eBPF programs write the events, so that the events synthesized in userspace are read and
+// parsed like those of the kernel. The event and the number of parameters of md are set from the
+// event. Every value is of the Go type of its parameter: uint8 to int64 for the integers, string
+// for TDT_STR and TDT_STR_ARR, and []byte for TDT_BYTE_ARR.
+func (te TarianEventMap) Encode(id TarianEventsE, md TarianMetaData, values ...any) ([]byte, error) {
+	event, ok := te[id]
+	if !ok {
+		return nil, encoderErr.Throwf("missing event from 'var Events TarianEventMap' for key: %v", id)
+	}
+
+	if len(values) != len(event.params) {
+		return nil, encoderErr.Throwf("event %s expects %d values, received %d", event.name, len(event.params), len(values))
+	}
+
+	md.MetaData.Event = int32(id)
+	md.MetaData.Nparams = uint8(len(values))
+	md.MetaData.Syscall = int32(event.syscallId)
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, md); err != nil {
+		return nil, encoderErr.Throwf("%v", err)
+	}
+
+	for i, p := range event.params {
+		if err := encodeParam(&buf, p, values[i]); err != nil {
+			return nil, encoderErr.Throwf("event %s, parameter %s: %v", event.name, p.name, err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeParam writes the value v of the parameter p to buf.
+func encodeParam(buf *bytes.Buffer, p Param, v any) error {
+	var ok bool
+	switch p.paramType {
+	case TDT_STR, TDT_STR_ARR:
+		var s string
+		if s, ok = v.(string); ok {
+			// NUL terminated, like the strings read by bpf_probe_read_str
+			return encodeArray(buf, append([]byte(s), 0))
+		}
+	case TDT_BYTE_ARR:
+		var b []byte
+		if b, ok = v.([]byte); ok {
+			return encodeArray(buf, b)
+		}
+	case TDT_U8:
+		_, ok = v.(uint8)
+	case TDT_U16:
+		_, ok = v.(uint16)
+	case TDT_U32:
+		_, ok = v.(uint32)
+	case TDT_U64:
+		_, ok = v.(uint64)
+	case TDT_S8:
+		_, ok = v.(int8)
+	case TDT_S16:
+		_, ok = v.(int16)
+	case TDT_S32:
+		_, ok = v.(int32)
+	case TDT_S64:
+		_, ok = v.(int64)
+	default:
+		return fmt.Errorf("unsupported parameter type %v", p.paramType)
+	}
+
+	if !ok {
+		return fmt.Errorf("unexpected value type %T for parameter type %v", v, p.paramType)
+	}
+
+	return binary.Write(buf, binary.LittleEndian, v)
+}
+
+// encodeArray writes the length of b followed by b to buf.
+func encodeArray(buf *bytes.Buffer, b []byte) error {
+	if len(b) > math.MaxUint16 {
+		return fmt.Errorf("array of %d bytes exceeds the maximum of %d", len(b), math.MaxUint16)
+	}
+
+	if err := binary.Write(buf, binary.LittleEndian, uint16(len(b))); err != nil {
+		return err
+	}
+
+	buf.Write(b)
+
+	return nil
+}
+
+// MetaDataFromMap returns the metadata of a record parsed by ParseByteArray, the inverse of toMap, so
+// that the events synthesized from the records of the eBPF programs carry the metadata of their process.
+// The event and the number of parameters are left to Encode.
+func MetaDataFromMap(record map[string]any) TarianMetaData {
+	var md TarianMetaData
+
+	md.MetaData.Ts, _ = record["timestamp"].(uint64)
+	md.MetaData.Syscall, _ = record["syscallId"].(int32)
+	md.MetaData.Processor, _ = record["processor"].(uint16)
+
+	// task fields
+	task := &md.MetaData.Task
+	task.StartTime, _ = record["threadStartTime"].(uint64)
+	task.HostPid, _ = record["hostProcessId"].(uint32)
+	task.HostTgid, _ = record["hostThreadId"].(uint32)
+	task.HostPpid, _ = record["hostParentProcessId"].(uint32)
+	task.Pid, _ = record["processId"].(uint32)
+	task.Tgid, _ = record["threadId"].(uint32)
+	task.Ppid, _ = record["parentProcessId"].(uint32)
+	task.Uid, _ = record["userId"].(uint32)
+	task.Gid, _ = record["groupId"].(uint32)
+	task.CgroupId, _ = record["cgroupId"].(uint64)
+	task.MountNsId, _ = record["mountNamespace"].(uint64)
+	task.PidNsId, _ = record["pidNamespace"].(uint64)
+	task.ExecId, _ = record["execId"].(uint64)
+	task.ParentExecId, _ = record["parentExecId"].(uint64)
+	copyString(task.Comm[:], record["processName"])
+	copyString(task.Cwd[:], record["directory"])
+
+	// SystemInfo fields
+	copyString(md.SystemInfo.Sysname[:], record["sysname"])
+	copyString(md.SystemInfo.Nodename[:], record["nodename"])
+	copyString(md.SystemInfo.Release[:], record["release"])
+	copyString(md.SystemInfo.Version[:], record["version"])
+	copyString(md.SystemInfo.Machine[:], record["machine"])
+	copyString(md.SystemInfo.Domainname[:], record["domainname"])
+
+	return md
+}
+
+// copyString copies the string value to dst, truncated to keep its NUL terminator.
+func copyString(dst []uint8, value any) {
+	s, _ := value.(string)
+	copy(dst[:len(dst)-1], s)
+}
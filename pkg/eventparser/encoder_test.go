@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package eventparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestTarianEventMap_Encode tests the Encode function, the events encoded being parsed by ParseByteArray.
+func TestTarianEventMap_Encode(t *testing.T) {
+	var md TarianMetaData
+	md.MetaData.Ts = 42
+	md.MetaData.Task.HostPid = 10
+	md.MetaData.Task.ExecId = 7
+	copy(md.MetaData.Task.Comm[:], "nginx")
+
+	tests := []struct {
+		name    string
+		id      TarianEventsE
+		values  []any
+		want    []Arg
+		wantErr bool
+	}{
+		{
+			name:   "synthetic event",
+			id:     TDE_PROCESS_SNAPSHOT,
+			values: []any{"/usr/sbin/nginx", "nginx -g daemon off;", "0=/dev/null"},
+			want: []Arg{
+				{Name: "exe", Value: "/usr/sbin/nginx", TarianType: uint32(TDT_STR), LinuxType: "const char *"},
+				{Name: "cmdline", Value: "nginx -g daemon off;", TarianType: uint32(TDT_STR), LinuxType: "const char *"},
+				{Name: "fds", Value: "0=/dev/null", TarianType: uint32(TDT_STR), LinuxType: "const char *"},
+			},
+		},
+		{
+			name:   "integers",
+			id:     TDE_SCHED_PROCESS_EXIT,
+			values: []any{int32(1), uint16(9), uint8(0), uint8(1)},
+			want: []Arg{
+				{Name: "exit_code", Value: "1", TarianType: uint32(TDT_S32), LinuxType: "int"},
+				{Name: "signal", Value: "SIGKILL", TarianType: uint32(TDT_U16), LinuxType: "int"},
+				{Name: "core_dumped", Value: "0", TarianType: uint32(TDT_U8), LinuxType: "bool"},
+				{Name: "group_dead", Value: "1", TarianType: uint32(TDT_U8), LinuxType: "bool"},
+			},
+		},
+		{
+			name:    "missing event",
+			id:      TarianEventsE(1000),
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			id:      TDE_PROCESS_SNAPSHOT,
+			values:  []any{"/usr/sbin/nginx", "nginx"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value type",
+			id:      TDE_SCHED_PROCESS_EXIT,
+			values:  []any{1, uint16(9), uint8(0), uint8(1)},
+			wantErr: true,
+		},
+		{
+			name:    "string too long",
+			id:      TDE_PROCESS_SNAPSHOT,
+			values:  []any{strings.Repeat("a", 1<<16), "", ""},
+			wantErr: true,
+		},
+	}
+
+	LoadTarianEvents()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Events.Encode(tt.id, md, tt.values...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TarianEventMap.Encode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			record, err := ParseByteArray(data)
+			if err != nil {
+				t.Fatalf("ParseByteArray() error = %v", err)
+			}
+
+			if record["eventId"] != Events[tt.id].GetName() || record["execId"] != uint64(7) || record["processName"] != "nginx" {
+				t.Errorf("ParseByteArray() = %v, want the metadata of the encoded event", record)
+			}
+
+			if got := record["context"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseByteArray() context = %v, want %v", got, tt.want)
+			}
+
+			if _, synthetic := record["synthetic"]; synthetic != Events[tt.id].synthetic {
+				t.Errorf("ParseByteArray() synthetic = %v, want %v", synthetic, Events[tt.id].synthetic)
+			}
+		})
+	}
+}
+
+// TestMetaDataFromMap tests the MetaDataFromMap function
+func TestMetaDataFromMap(t *testing.T) {
+	var md TarianMetaData
+	md.MetaData.Ts = 42
+	md.MetaData.Syscall = 42
+	md.MetaData.Processor = 3
+	md.MetaData.Task.HostPid = 10
+	md.MetaData.Task.HostTgid = 11
+	md.MetaData.Task.Uid = 1000
+	md.MetaData.Task.CgroupId = 9
+	md.MetaData.Task.ExecId = 7
+	copy(md.MetaData.Task.Comm[:], "nginx")
+	copy(md.MetaData.Task.Cwd[:], "/srv")
+	copy(md.SystemInfo.Nodename[:], "node-1")
+
+	if got := MetaDataFromMap(toMap(md)); !reflect.DeepEqual(got, md) {
+		t.Errorf("MetaDataFromMap() = %+v, want %+v", got, md)
+	}
+
+	var long TarianMetaData
+	copy(long.MetaData.Task.Comm[:15], strings.Repeat("a", 15))
+	if got := MetaDataFromMap(map[string]any{"processName": strings.Repeat("a", 20)}); !reflect.DeepEqual(got, long) {
+		t.Errorf("MetaDataFromMap() = %+v, want %+v", got.MetaData.Task.Comm, long.MetaData.Task.Comm)
+	}
+}
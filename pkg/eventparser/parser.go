@@ -64,6 +64,9 @@ func ParseByteArray(data []byte) (map[string]any, error) {
 
 	record := toMap(metaData)
 	record["eventId"] = event.name
+	if event.synthetic {
+		record["synthetic"] = true
+	}
 
 	bs := NewByteStream(data[lenMetaData:], metaData.MetaData.Nparams)
 	ps, err := bs.parseParams(event)
@@ -340,6 +343,41 @@ func (bs *ByteStream) parseSocketAddress() (any, error) {
 	}
 }
 
+// Offsets of the TarianMetaData fields read from the raw events without parsing them.
+const (
+	processorOffset = 17 // offset of MetaData.Processor
+	hostPidOffset   = 27 // offset of MetaData.Task.HostPid
+)
+
+// GetEventId reads the id of the event from the data, without parsing the event.
+func GetEventId(data []byte) (TarianEventsE, error) {
+	id, err := getEventId(data)
+
+	return TarianEventsE(id), err
+}
+
+// GetProcessor reads the number of the processor the event was captured on from the data,
+// without parsing the event.
+func GetProcessor(data []byte) (uint16, error) {
+	cpu, err := utils.Uint16(data, processorOffset)
+	if err != nil {
+		return 0, parserErr.Throwf("failed to read processor from data: %v", err)
+	}
+
+	return cpu, nil
+}
+
+// GetHostPid reads the host ID of the thread the event was captured on from the data,
+// without parsing the event.
+func GetHostPid(data []byte) (uint32, error) {
+	pid, err := utils.Uint32(data, hostPidOffset)
+	if err != nil {
+		return 0, parserErr.Throwf("failed to read hostProcessId from data: %v", err)
+	}
+
+	return pid, nil
+}
+
 // getEventId reads the eventId from the data and returns it as an int.
 func getEventId(data []byte) (int, error) {
 	id, err := utils.Int32(data, 0)
@@ -4,6 +4,8 @@
 package eventparser
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"reflect"
 	"testing"
@@ -850,3 +852,56 @@ func TestByteStream_parseSocketAddress(t *testing.T) {
 		})
 	}
 }
+
+// TestGetProcessor tests the GetProcessor and GetHostPid functions. It checks that they read
+// the fields at their offset in the TarianMetaData written by the kernel.
+func TestGetProcessor(t *testing.T) {
+	var md TarianMetaData
+	md.MetaData.Event = int32(TDE_SYSCALL_CLOSE_E)
+	md.MetaData.Processor = 7
+	md.MetaData.Task.HostPid = 4242
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, md); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantCpu uint16
+		wantPid uint32
+		wantErr bool
+	}{
+		{
+			name:    "metadata",
+			data:    buf.Bytes(),
+			wantCpu: 7,
+			wantPid: 4242,
+		},
+		{
+			name:    "truncated data",
+			data:    buf.Bytes()[:hostPidOffset],
+			wantCpu: 7,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu, err := GetProcessor(tt.data)
+			if err == nil && cpu != tt.wantCpu {
+				t.Errorf("GetProcessor() = %v, want %v", cpu, tt.wantCpu)
+			}
+
+			pid, err := GetHostPid(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetHostPid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if pid != tt.wantPid {
+				t.Errorf("GetHostPid() = %v, want %v", pid, tt.wantPid)
+			}
+		})
+	}
+}
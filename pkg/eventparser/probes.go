@@ -4,7 +4,9 @@
 package eventparser
 
 import (
+	"encoding/binary"
 	"fmt"
+	"runtime"
 
 	"github.com/intelops/tarian-detector/pkg/err"
 )
@@ -33,6 +35,12 @@ type TarianEvent struct {
 	syscallId int     // syscall ID
 	eventSize uint32  // size of the event
 	params    []Param // parameters of the event
+	synthetic bool    // synthetic reports whether the event is synthesized in userspace
+}
+
+// GetName returns the name of the event.
+func (te TarianEvent) GetName() string {
+	return te.name
 }
 
 // TarianEventMap is a map type that maps TarianEventsE to TarianEvent
@@ -53,28 +61,46 @@ func NewTarianEvent(id int, name string, size uint32, params ...Param) TarianEve
 	}
 }
 
+// NewSyntheticEvent creates a new TarianEvent synthesized in userspace, such as the snapshot of a process
+// running when the detector started, with the given name and params. Its records are marked synthetic.
+func NewSyntheticEvent(name string, params ...Param) TarianEvent {
+	return TarianEvent{
+		name:      name,
+		syscallId: -1,
+		params:    params,
+		synthetic: true,
+	}
+}
+
 // LoadTarianEvents loads the Tarian events into 'Events' variable by generating them using GenerateTarianEvents function
 func LoadTarianEvents() {
 	Events = GenerateTarianEvents()
 }
 
-// GenerateTarianEvents creates and returns a TarianEventMap
+// GenerateTarianEvents creates and returns a TarianEventMap with the syscall numbers of the running architecture
 func GenerateTarianEvents() TarianEventMap {
+	return GenerateTarianEventsFor(runtime.GOARCH)
+}
+
+// GenerateTarianEventsFor creates and returns a TarianEventMap with the syscall numbers of the given architecture.
+// Syscalls that do not exist on the architecture are assigned the id -1.
+func GenerateTarianEventsFor(arch string) TarianEventMap {
+	st, _ := GetSyscallTable(arch)
 	events := make(TarianEventMap)
 
-	execve_e := NewTarianEvent(59, "sys_execve_entry", 8957,
+	execve_e := NewTarianEvent(st.Id("execve"), "sys_execve_entry", 8957,
 		Param{name: "filename", paramType: TDT_STR, linuxType: "const char *"},
 		Param{name: "argv", paramType: TDT_STR_ARR, linuxType: "const char **"},
 		Param{name: "envp", paramType: TDT_STR_ARR, linuxType: "const char **"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_EXECVE_E, execve_e)
 
-	execve_r := NewTarianEvent(59, "sys_execve_exit", 765,
+	execve_r := NewTarianEvent(st.Id("execve"), "sys_execve_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_EXECVE_R, execve_r)
 
-	execveat_e := NewTarianEvent(322, "sys_execveat_entry", 8965,
+	execveat_e := NewTarianEvent(st.Id("execveat"), "sys_execveat_entry", 8965,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int", function: parseExecveatDird},
 		Param{name: "filename", paramType: TDT_STR, linuxType: "const char *"},
 		Param{name: "argv", paramType: TDT_STR_ARR, linuxType: "char const **"},
@@ -83,12 +109,12 @@ func GenerateTarianEvents() TarianEventMap {
 	)
 	events.AddTarianEvent(TDE_SYSCALL_EXECVEAT_E, execveat_e)
 
-	execveat_r := NewTarianEvent(322, "sys_execveat_exit", 765,
+	execveat_r := NewTarianEvent(st.Id("execveat"), "sys_execveat_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_EXECVEAT_R, execveat_r)
 
-	clone_e := NewTarianEvent(56, "sys_clone_entry", 793,
+	clone_e := NewTarianEvent(st.Id("clone"), "sys_clone_entry", 793,
 		Param{name: "clone_flags", paramType: TDT_U64, linuxType: "unsigned long", function: parseCloneFlags},
 		Param{name: "newsp", paramType: TDT_S64, linuxType: "unsigned long"},
 		Param{name: "parent_tid", paramType: TDT_S32, linuxType: "int *"},
@@ -97,82 +123,82 @@ func GenerateTarianEvents() TarianEventMap {
 	)
 	events.AddTarianEvent(TDE_SYSCALL_CLONE_E, clone_e)
 
-	clone_r := NewTarianEvent(56, "sys_clone_exit", 765,
+	clone_r := NewTarianEvent(st.Id("clone"), "sys_clone_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_CLONE_R, clone_r)
 
-	close_e := NewTarianEvent(3, "sys_close_entry", 765,
+	close_e := NewTarianEvent(st.Id("close"), "sys_close_entry", 765,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_CLOSE_E, close_e)
 
-	close_r := NewTarianEvent(3, "sys_close_exit", 765,
+	close_r := NewTarianEvent(st.Id("close"), "sys_close_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_CLOSE_R, close_r)
 
-	read_e := NewTarianEvent(0, "sys_read_entry", 4867,
+	read_e := NewTarianEvent(st.Id("read"), "sys_read_entry", 4867,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "buf", paramType: TDT_BYTE_ARR, linuxType: "char *"},
 		Param{name: "count", paramType: TDT_U32, linuxType: "size_t"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_READ_E, read_e)
 
-	read_r := NewTarianEvent(0, "sys_read_exit", 769,
+	read_r := NewTarianEvent(st.Id("read"), "sys_read_exit", 769,
 		Param{name: "return", paramType: TDT_S64, linuxType: "ssize_t"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_READ_R, read_r)
 
-	write_e := NewTarianEvent(1, "sys_write_entry", 4867,
+	write_e := NewTarianEvent(st.Id("write"), "sys_write_entry", 4867,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "buf", paramType: TDT_BYTE_ARR, linuxType: "const char *"},
 		Param{name: "count", paramType: TDT_U32, linuxType: "size_t"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_WRITE_E, write_e)
 
-	write_r := NewTarianEvent(1, "sys_write_exit", 769,
+	write_r := NewTarianEvent(st.Id("write"), "sys_write_exit", 769,
 		Param{name: "return", paramType: TDT_S64, linuxType: "ssize_t"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_WRITE_R, write_r)
 
-	open_e := NewTarianEvent(2, "sys_open_entry", 4867,
+	open_e := NewTarianEvent(st.Id("open"), "sys_open_entry", 4867,
 		Param{name: "filename", paramType: TDT_STR, linuxType: "const char *"},
 		Param{name: "flags", paramType: TDT_S32, linuxType: "int", function: parseOpenFlags},
 		Param{name: "mode", paramType: TDT_U32, linuxType: "umode_t", function: parseOpenMode},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_OPEN_E, open_e)
 
-	open_r := NewTarianEvent(2, "sys_open_exit", 765,
+	open_r := NewTarianEvent(st.Id("open"), "sys_open_exit", 765,
 		Param{name: "return", paramType: TDT_U32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_OPEN_R, open_r)
 
-	readv_e := NewTarianEvent(19, "sys_readv_entry", 4867,
+	readv_e := NewTarianEvent(st.Id("readv"), "sys_readv_entry", 4867,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "vec", paramType: TDT_BYTE_ARR, linuxType: "const struct iovec *"},
 		Param{name: "vlen", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_READV_E, readv_e)
 
-	readv_r := NewTarianEvent(19, "sys_readv_exit", 769,
+	readv_r := NewTarianEvent(st.Id("readv"), "sys_readv_exit", 769,
 		Param{name: "return", paramType: TDT_S64, linuxType: "ssize_t"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_READV_R, readv_r)
 
-	writev_e := NewTarianEvent(20, "sys_writev_entry", 4867,
+	writev_e := NewTarianEvent(st.Id("writev"), "sys_writev_entry", 4867,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "vec", paramType: TDT_BYTE_ARR, linuxType: "const struct iovec *"},
 		Param{name: "vlen", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_WRITEV_E, writev_e)
 
-	writev_r := NewTarianEvent(20, "sys_writev_exit", 769,
+	writev_r := NewTarianEvent(st.Id("writev"), "sys_writev_exit", 769,
 		Param{name: "return", paramType: TDT_S64, linuxType: "ssize_t"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_WRITEV_R, writev_r)
 
-	openat_e := NewTarianEvent(257, "sys_openat_entry", 4871,
+	openat_e := NewTarianEvent(st.Id("openat"), "sys_openat_entry", 4871,
 		Param{name: "dfd", paramType: TDT_S32, linuxType: "int", function: parseExecveatDird},
 		Param{name: "filename", paramType: TDT_STR, linuxType: "const char *"},
 		Param{name: "flags", paramType: TDT_S32, linuxType: "int", function: parseOpenFlags},
@@ -180,12 +206,12 @@ func GenerateTarianEvents() TarianEventMap {
 	)
 	events.AddTarianEvent(TDE_SYSCALL_OPENAT_E, openat_e)
 
-	openat_r := NewTarianEvent(257, "sys_openat_exit", 765,
+	openat_r := NewTarianEvent(st.Id("openat"), "sys_openat_exit", 765,
 		Param{name: "return", paramType: TDT_U32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_OPENAT_R, openat_r)
 
-	openat2_e := NewTarianEvent(437, "sys_openat2_entry", 4891,
+	openat2_e := NewTarianEvent(st.Id("openat2"), "sys_openat2_entry", 4891,
 		Param{name: "dfd", paramType: TDT_S32, linuxType: "int", function: parseExecveatDird},
 		Param{name: "filename", paramType: TDT_STR, linuxType: "const char *"},
 		Param{name: "flags", paramType: TDT_S64, linuxType: "unsigned long", function: parseOpenat2Flags},
@@ -195,73 +221,262 @@ func GenerateTarianEvents() TarianEventMap {
 	)
 	events.AddTarianEvent(TDE_SYSCALL_OPENAT2_E, openat2_e)
 
-	openat2_r := NewTarianEvent(437, "sys_openat2_exit", 769,
+	openat2_r := NewTarianEvent(st.Id("openat2"), "sys_openat2_exit", 769,
 		Param{name: "return", paramType: TDT_S64, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_OPENAT2_R, openat2_r)
 
-	listen_e := NewTarianEvent(50, "sys_listen_entry", 769,
+	listen_e := NewTarianEvent(st.Id("listen"), "sys_listen_entry", 769,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "backlog", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_LISTEN_E, listen_e)
 
-	listen_r := NewTarianEvent(50, "sys_listen_exit", 765,
+	listen_r := NewTarianEvent(st.Id("listen"), "sys_listen_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_LISTEN_R, listen_r)
 
-	socket_e := NewTarianEvent(41, "sys_socket_entry", 773,
+	socket_e := NewTarianEvent(st.Id("socket"), "sys_socket_entry", 773,
 		Param{name: "family", paramType: TDT_S32, linuxType: "int", function: parseSocketFamily},
 		Param{name: "type", paramType: TDT_S32, linuxType: "int", function: parseSocketType},
 		Param{name: "protocol", paramType: TDT_S32, linuxType: "int", function: parseSocketProtocol},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_SOCKET_E, socket_e)
 
-	socket_r := NewTarianEvent(41, "sys_socket_exit", 765,
+	socket_r := NewTarianEvent(st.Id("socket"), "sys_socket_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_SOCKET_R, socket_r)
 
-	accept_e := NewTarianEvent(43, "sys_accept_entry", 880,
+	accept_e := NewTarianEvent(st.Id("accept"), "sys_accept_entry", 880,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "upeer_sockaddr", paramType: TDT_SOCKADDR, linuxType: "struct sockaddr *"},
 		Param{name: "upper_addrlen", paramType: TDT_S32, linuxType: "int *"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_ACCEPT_E, accept_e)
 
-	accept_r := NewTarianEvent(43, "sys_accept_exit", 765,
+	accept_r := NewTarianEvent(st.Id("accept"), "sys_accept_exit", 784,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
+		Param{name: "peer", paramType: TDT_SOCKADDR, linuxType: "struct sock *", function: parseSockPeer},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_ACCEPT_R, accept_r)
 
-	bind_e := NewTarianEvent(49, "sys_bind_entry", 880,
+	bind_e := NewTarianEvent(st.Id("bind"), "sys_bind_entry", 880,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "umyaddr", paramType: TDT_SOCKADDR, linuxType: "struct sockaddr *"},
 		Param{name: "addrlen", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_BIND_E, bind_e)
 
-	bind_r := NewTarianEvent(49, "sys_bind_exit", 765,
+	bind_r := NewTarianEvent(st.Id("bind"), "sys_bind_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_BIND_R, bind_r)
 
-	connect_e := NewTarianEvent(42, "sys_connect_entry", 880,
+	connect_e := NewTarianEvent(st.Id("connect"), "sys_connect_entry", 880,
 		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
 		Param{name: "uservaddr", paramType: TDT_SOCKADDR, linuxType: "struct sockaddr *"},
 		Param{name: "addrlen", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_CONNECT_E, connect_e)
 
-	connect_r := NewTarianEvent(42, "sys_connect_exit", 765,
+	connect_r := NewTarianEvent(st.Id("connect"), "sys_connect_exit", 765,
 		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
 	)
 	events.AddTarianEvent(TDE_SYSCALL_CONNECT_R, connect_r)
 
+	dup_e := NewTarianEvent(st.Id("dup"), "sys_dup_entry", 765,
+		Param{name: "fildes", paramType: TDT_S32, linuxType: "unsigned int"},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_DUP_E, dup_e)
+
+	dup_r := NewTarianEvent(st.Id("dup"), "sys_dup_exit", 765,
+		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_DUP_R, dup_r)
+
+	dup2_e := NewTarianEvent(st.Id("dup2"), "sys_dup2_entry", 769,
+		Param{name: "oldfd", paramType: TDT_S32, linuxType: "unsigned int"},
+		Param{name: "newfd", paramType: TDT_S32, linuxType: "unsigned int"},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_DUP2_E, dup2_e)
+
+	dup2_r := NewTarianEvent(st.Id("dup2"), "sys_dup2_exit", 765,
+		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_DUP2_R, dup2_r)
+
+	dup3_e := NewTarianEvent(st.Id("dup3"), "sys_dup3_entry", 773,
+		Param{name: "oldfd", paramType: TDT_S32, linuxType: "unsigned int"},
+		Param{name: "newfd", paramType: TDT_S32, linuxType: "unsigned int"},
+		Param{name: "flags", paramType: TDT_S32, linuxType: "int", function: parseDup3Flags},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_DUP3_E, dup3_e)
+
+	dup3_r := NewTarianEvent(st.Id("dup3"), "sys_dup3_exit", 765,
+		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_DUP3_R, dup3_r)
+
+	fcntl_e := NewTarianEvent(st.Id("fcntl"), "sys_fcntl_entry", 777,
+		Param{name: "fd", paramType: TDT_S32, linuxType: "unsigned int"},
+		Param{name: "cmd", paramType: TDT_S32, linuxType: "unsigned int", function: parseFcntlCmd},
+		Param{name: "arg", paramType: TDT_U64, linuxType: "unsigned long"},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_FCNTL_E, fcntl_e)
+
+	fcntl_r := NewTarianEvent(st.Id("fcntl"), "sys_fcntl_exit", 765,
+		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_FCNTL_R, fcntl_r)
+
+	accept4_e := NewTarianEvent(st.Id("accept4"), "sys_accept4_entry", 884,
+		Param{name: "fd", paramType: TDT_S32, linuxType: "int"},
+		Param{name: "upeer_sockaddr", paramType: TDT_SOCKADDR, linuxType: "struct sockaddr *"},
+		Param{name: "upper_addrlen", paramType: TDT_S32, linuxType: "int *"},
+		Param{name: "flags", paramType: TDT_S32, linuxType: "int", function: parseAccept4Flags},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_ACCEPT4_E, accept4_e)
+
+	accept4_r := NewTarianEvent(st.Id("accept4"), "sys_accept4_exit", 784,
+		Param{name: "return", paramType: TDT_S32, linuxType: "int"},
+		Param{name: "peer", paramType: TDT_SOCKADDR, linuxType: "struct sock *", function: parseSockPeer},
+	)
+	events.AddTarianEvent(TDE_SYSCALL_ACCEPT4_R, accept4_r)
+
+	// entry arguments and return value in one event, written by the fexit backend
+	combined := []struct {
+		idx, entry, exit TarianEventsE
+		name             string
+	}{
+		{TDE_SYSCALL_CLONE, TDE_SYSCALL_CLONE_E, TDE_SYSCALL_CLONE_R, "sys_clone"},
+		{TDE_SYSCALL_CLOSE, TDE_SYSCALL_CLOSE_E, TDE_SYSCALL_CLOSE_R, "sys_close"},
+		{TDE_SYSCALL_READ, TDE_SYSCALL_READ_E, TDE_SYSCALL_READ_R, "sys_read"},
+		{TDE_SYSCALL_WRITE, TDE_SYSCALL_WRITE_E, TDE_SYSCALL_WRITE_R, "sys_write"},
+		{TDE_SYSCALL_OPEN, TDE_SYSCALL_OPEN_E, TDE_SYSCALL_OPEN_R, "sys_open"},
+		{TDE_SYSCALL_READV, TDE_SYSCALL_READV_E, TDE_SYSCALL_READV_R, "sys_readv"},
+		{TDE_SYSCALL_WRITEV, TDE_SYSCALL_WRITEV_E, TDE_SYSCALL_WRITEV_R, "sys_writev"},
+		{TDE_SYSCALL_OPENAT, TDE_SYSCALL_OPENAT_E, TDE_SYSCALL_OPENAT_R, "sys_openat"},
+		{TDE_SYSCALL_OPENAT2, TDE_SYSCALL_OPENAT2_E, TDE_SYSCALL_OPENAT2_R, "sys_openat2"},
+		{TDE_SYSCALL_LISTEN, TDE_SYSCALL_LISTEN_E, TDE_SYSCALL_LISTEN_R, "sys_listen"},
+		{TDE_SYSCALL_SOCKET, TDE_SYSCALL_SOCKET_E, TDE_SYSCALL_SOCKET_R, "sys_socket"},
+		{TDE_SYSCALL_ACCEPT, TDE_SYSCALL_ACCEPT_E, TDE_SYSCALL_ACCEPT_R, "sys_accept"},
+		{TDE_SYSCALL_BIND, TDE_SYSCALL_BIND_E, TDE_SYSCALL_BIND_R, "sys_bind"},
+		{TDE_SYSCALL_CONNECT, TDE_SYSCALL_CONNECT_E, TDE_SYSCALL_CONNECT_R, "sys_connect"},
+		{TDE_SYSCALL_DUP, TDE_SYSCALL_DUP_E, TDE_SYSCALL_DUP_R, "sys_dup"},
+		{TDE_SYSCALL_DUP2, TDE_SYSCALL_DUP2_E, TDE_SYSCALL_DUP2_R, "sys_dup2"},
+		{TDE_SYSCALL_DUP3, TDE_SYSCALL_DUP3_E, TDE_SYSCALL_DUP3_R, "sys_dup3"},
+		{TDE_SYSCALL_FCNTL, TDE_SYSCALL_FCNTL_E, TDE_SYSCALL_FCNTL_R, "sys_fcntl"},
+		{TDE_SYSCALL_ACCEPT4, TDE_SYSCALL_ACCEPT4_E, TDE_SYSCALL_ACCEPT4_R, "sys_accept4"},
+	}
+
+	for _, c := range combined {
+		events.AddTarianEvent(c.idx, combineTarianEvents(c.name, events[c.entry], events[c.exit]))
+	}
+
+	lsm_bprm_check_security := NewTarianEvent(-1, "lsm_bprm_check_security", 4864,
+		Param{name: "policy_id", paramType: TDT_U32, linuxType: "u32"},
+		Param{name: "action", paramType: TDT_U8, linuxType: "u8", function: parseLsmAction},
+		Param{name: "filename", paramType: TDT_STR, linuxType: "const char *"},
+	)
+	events.AddTarianEvent(TDE_LSM_BPRM_CHECK_SECURITY, lsm_bprm_check_security)
+
+	lsm_file_open := NewTarianEvent(-1, "lsm_file_open", 4876,
+		Param{name: "policy_id", paramType: TDT_U32, linuxType: "u32"},
+		Param{name: "action", paramType: TDT_U8, linuxType: "u8", function: parseLsmAction},
+		Param{name: "dev", paramType: TDT_U32, linuxType: "dev_t"},
+		Param{name: "ino", paramType: TDT_U64, linuxType: "unsigned long"},
+		Param{name: "name", paramType: TDT_STR, linuxType: "const unsigned char *"},
+	)
+	events.AddTarianEvent(TDE_LSM_FILE_OPEN, lsm_file_open)
+
+	lsm_socket_connect := NewTarianEvent(-1, "lsm_socket_connect", 877,
+		Param{name: "policy_id", paramType: TDT_U32, linuxType: "u32"},
+		Param{name: "action", paramType: TDT_U8, linuxType: "u8", function: parseLsmAction},
+		Param{name: "address", paramType: TDT_SOCKADDR, linuxType: "struct sockaddr *"},
+	)
+	events.AddTarianEvent(TDE_LSM_SOCKET_CONNECT, lsm_socket_connect)
+
+	// plaintext of the tls connections, truncated to the first 4096 bytes
+	for _, e := range []struct {
+		id   TarianEventsE
+		name string
+	}{
+		{TDE_SSL_WRITE, "ssl_write"},
+		{TDE_SSL_READ, "ssl_read"},
+		{TDE_GO_TLS_WRITE, "go_tls_write"},
+		{TDE_GO_TLS_READ, "go_tls_read"},
+	} {
+		events.AddTarianEvent(e.id, NewTarianEvent(-1, e.name, 4863,
+			Param{name: "data", paramType: TDT_BYTE_ARR, linuxType: "char *"},
+			Param{name: "len", paramType: TDT_S32, linuxType: "int"},
+		))
+	}
+
+	readline := NewTarianEvent(-1, "readline", 4859,
+		Param{name: "line", paramType: TDT_STR, linuxType: "char *"},
+	)
+	events.AddTarianEvent(TDE_READLINE, readline)
+
+	sched_process_fork := NewTarianEvent(-1, "sched_process_fork", 777,
+		Param{name: "child_pid", paramType: TDT_U32, linuxType: "pid_t"},
+		Param{name: "child_tid", paramType: TDT_U32, linuxType: "pid_t"},
+		Param{name: "child_exec_id", paramType: TDT_U64, linuxType: "u64"},
+	)
+	events.AddTarianEvent(TDE_SCHED_PROCESS_FORK, sched_process_fork)
+
+	sched_process_exec := NewTarianEvent(-1, "sched_process_exec", 4863,
+		Param{name: "filename", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "old_pid", paramType: TDT_S32, linuxType: "pid_t"},
+	)
+	events.AddTarianEvent(TDE_SCHED_PROCESS_EXEC, sched_process_exec)
+
+	sched_process_exit := NewTarianEvent(-1, "sched_process_exit", 769,
+		Param{name: "exit_code", paramType: TDT_S32, linuxType: "int"},
+		Param{name: "signal", paramType: TDT_U16, linuxType: "int", function: parseExitSignal},
+		Param{name: "core_dumped", paramType: TDT_U8, linuxType: "bool"},
+		Param{name: "group_dead", paramType: TDT_U8, linuxType: "bool"},
+	)
+	events.AddTarianEvent(TDE_SCHED_PROCESS_EXIT, sched_process_exit)
+
+	process_snapshot := NewSyntheticEvent("process_snapshot",
+		Param{name: "exe", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "cmdline", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "fds", paramType: TDT_STR, linuxType: "const char *"},
+	)
+	events.AddTarianEvent(TDE_PROCESS_SNAPSHOT, process_snapshot)
+
+	flow := NewSyntheticEvent("flow",
+		Param{name: "sockfd", paramType: TDT_S32, linuxType: "int"},
+		Param{name: "direction", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "family", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "type", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "protocol", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "local", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "remote", paramType: TDT_STR, linuxType: "const char *"},
+		Param{name: "start", paramType: TDT_U64, linuxType: "u64"},
+		Param{name: "end", paramType: TDT_U64, linuxType: "u64"},
+		Param{name: "bytes_sent", paramType: TDT_U64, linuxType: "u64"},
+		Param{name: "bytes_received", paramType: TDT_U64, linuxType: "u64"},
+	)
+	events.AddTarianEvent(TDE_FLOW, flow)
+
 	return events
 }
 
+// combineTarianEvents creates a TarianEvent carrying the parameters of the entry event followed by the parameters of the exit event.
+func combineTarianEvents(name string, entry TarianEvent, exit TarianEvent) TarianEvent {
+	params := make([]Param, 0, len(entry.params)+len(exit.params))
+	params = append(params, entry.params...)
+	params = append(params, exit.params...)
+
+	// both events carry the meta data
+	size := entry.eventSize + exit.eventSize - uint32(binary.Size(TarianMetaData{}))
+
+	return NewTarianEvent(entry.syscallId, name, size, params...)
+}
+
 // processValue processes the value and returns the argument and an error, if any.
 func (p *Param) processValue(val interface{}) (Arg, error) {
 	arg := Arg{}
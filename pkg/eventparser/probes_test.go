@@ -19,8 +19,8 @@ func TestLoadTarianEvents(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			LoadTarianEvents()
 
-			if len(Events) != 32 {
-				t.Errorf("LoadTarianEvents() = %v, want %v", len(Events), 32)
+			if len(Events) != 74 {
+				t.Errorf("LoadTarianEvents() = %v, want %v", len(Events), 74)
 			}
 		})
 	}
@@ -81,3 +81,33 @@ func TestParam_processValue(t *testing.T) {
 		})
 	}
 }
+
+// TestCombineTarianEvents tests the combineTarianEvents function.
+func TestCombineTarianEvents(t *testing.T) {
+	events := GenerateTarianEvents()
+
+	got := events[TDE_SYSCALL_CLOSE]
+	if got.name != "sys_close" {
+		t.Errorf("combineTarianEvents() name = %v, want %v", got.name, "sys_close")
+	}
+
+	// fd followed by the return value
+	wantParams := []string{"fd", "return"}
+	if len(got.params) != len(wantParams) {
+		t.Fatalf("combineTarianEvents() params = %v, want %v", len(got.params), len(wantParams))
+	}
+
+	for i, p := range got.params {
+		if p.name != wantParams[i] {
+			t.Errorf("combineTarianEvents() param %d = %v, want %v", i, p.name, wantParams[i])
+		}
+	}
+
+	if got.eventSize != 769 {
+		t.Errorf("combineTarianEvents() eventSize = %v, want %v", got.eventSize, 769)
+	}
+
+	if got.syscallId != events[TDE_SYSCALL_CLOSE_E].syscallId {
+		t.Errorf("combineTarianEvents() syscallId = %v, want %v", got.syscallId, events[TDE_SYSCALL_CLOSE_E].syscallId)
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package eventparser
+
+// SyscallTable maps syscall names to their numbers on an architecture.
+type SyscallTable map[string]int
+
+// syscallTables holds the numbers of the syscalls traced by the detector for each
+// supported architecture, keyed by GOARCH. arm64 uses the generic syscall table
+// (asm-generic/unistd.h) which has no open and dup2 syscalls.
+var syscallTables = map[string]SyscallTable{
+	"amd64": {
+		"read":     0,
+		"write":    1,
+		"open":     2,
+		"close":    3,
+		"readv":    19,
+		"writev":   20,
+		"dup":      32,
+		"dup2":     33,
+		"socket":   41,
+		"connect":  42,
+		"accept":   43,
+		"bind":     49,
+		"listen":   50,
+		"clone":    56,
+		"execve":   59,
+		"fcntl":    72,
+		"openat":   257,
+		"accept4":  288,
+		"dup3":     292,
+		"execveat": 322,
+		"openat2":  437,
+	},
+	"arm64": {
+		"dup":      23,
+		"dup3":     24,
+		"fcntl":    25,
+		"openat":   56,
+		"close":    57,
+		"read":     63,
+		"write":    64,
+		"readv":    65,
+		"writev":   66,
+		"socket":   198,
+		"bind":     200,
+		"listen":   201,
+		"accept":   202,
+		"connect":  203,
+		"clone":    220,
+		"execve":   221,
+		"accept4":  242,
+		"execveat": 281,
+		"openat2":  437,
+	},
+}
+
+// GetSyscallTable returns the syscall table of the given architecture and whether
+// the architecture is supported.
+func GetSyscallTable(arch string) (SyscallTable, bool) {
+	st, ok := syscallTables[arch]
+
+	return st, ok
+}
+
+// Id returns the number of the named syscall, or -1 if the syscall does not exist
+// on the architecture.
+func (st SyscallTable) Id(name string) int {
+	id, ok := st[name]
+	if !ok {
+		return -1
+	}
+
+	return id
+}
+
+// Has reports whether the named syscall exists on the architecture.
+func (st SyscallTable) Has(name string) bool {
+	_, ok := st[name]
+
+	return ok
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package eventparser
+
+import "testing"
+
+// TestSyscallTable_Id tests the Id function of the SyscallTable for the supported architectures.
+func TestSyscallTable_Id(t *testing.T) {
+	tests := []struct {
+		name    string
+		arch    string
+		syscall string
+		want    int
+	}{
+		{name: "amd64 execve", arch: "amd64", syscall: "execve", want: 59},
+		{name: "arm64 execve", arch: "arm64", syscall: "execve", want: 221},
+		{name: "amd64 open", arch: "amd64", syscall: "open", want: 2},
+		{name: "arm64 open", arch: "arm64", syscall: "open", want: -1},
+		{name: "arm64 connect", arch: "arm64", syscall: "connect", want: 203},
+		{name: "unsupported architecture", arch: "mips", syscall: "execve", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, _ := GetSyscallTable(tt.arch)
+			if got := st.Id(tt.syscall); got != tt.want {
+				t.Errorf("SyscallTable.Id() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGenerateTarianEventsFor tests that the generated events carry the syscall ids of the given architecture.
+func TestGenerateTarianEventsFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		arch  string
+		event TarianEventsE
+		want  int
+	}{
+		{name: "amd64 execve entry", arch: "amd64", event: TDE_SYSCALL_EXECVE_E, want: 59},
+		{name: "arm64 execve entry", arch: "arm64", event: TDE_SYSCALL_EXECVE_E, want: 221},
+		{name: "arm64 openat exit", arch: "arm64", event: TDE_SYSCALL_OPENAT_R, want: 56},
+		{name: "arm64 open entry", arch: "arm64", event: TDE_SYSCALL_OPEN_E, want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := GenerateTarianEventsFor(tt.arch)
+			if got := events[tt.event].syscallId; got != tt.want {
+				t.Errorf("GenerateTarianEventsFor() syscallId = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -210,6 +210,16 @@ func parseSignal(sig uint16) string {
 	return name
 }
 
+// parseExitSignal takes the signal terminating a process, 0 if it exited, and returns its name.
+func parseExitSignal(sig any) (string, error) {
+	s, ok := sig.(uint16)
+	if !ok {
+		return fmt.Sprintf("%v", sig), transformErr.Throwf("parseExitSignal: parse value error expected %T received %T", s, sig)
+	}
+
+	return parseSignal(s), nil
+}
+
 // parseOpenMode takes an open mode value (mode) and returns its octal representation.
 func parseOpenMode(mode any) (string, error) {
 	m, ok := mode.(uint32)
@@ -294,6 +304,55 @@ func parseOpenFlags(flags any) (string, error) {
 	return strings.Join(fs, "|"), nil
 }
 
+// parseDup3Flags takes the flags of a dup3 syscall and returns O_CLOEXEC if it is set, 0 otherwise.
+func parseDup3Flags(flags any) (string, error) {
+	f, ok := flags.(int32)
+	if !ok {
+		return fmt.Sprintf("%v", flags), transformErr.Throwf("parseDup3Flags: parse value error expected %T received %T", f, flags)
+	}
+
+	if f&O_CLOEXEC == O_CLOEXEC {
+		return "O_CLOEXEC", nil
+	}
+
+	return "0", nil
+}
+
+// fcntlCmds represents the commands of the fcntl syscall.
+var fcntlCmds = map[int32]string{
+	0:    "F_DUPFD",         // Duplicate the file descriptor.
+	1:    "F_GETFD",         // Get the file descriptor flags.
+	2:    "F_SETFD",         // Set the file descriptor flags.
+	3:    "F_GETFL",         // Get the file status flags.
+	4:    "F_SETFL",         // Set the file status flags.
+	5:    "F_GETLK",         // Get a record lock.
+	6:    "F_SETLK",         // Set a record lock.
+	7:    "F_SETLKW",        // Set a record lock, waiting for it.
+	8:    "F_SETOWN",        // Set the process receiving the SIGIO signals.
+	9:    "F_GETOWN",        // Get the process receiving the SIGIO signals.
+	1024: "F_SETLEASE",      // Set a lease.
+	1025: "F_GETLEASE",      // Get the lease.
+	1026: "F_NOTIFY",        // Notify of the changes of a directory.
+	1030: "F_DUPFD_CLOEXEC", // Duplicate the file descriptor, closed upon exec.
+	1031: "F_SETPIPE_SZ",    // Set the capacity of a pipe.
+	1032: "F_GETPIPE_SZ",    // Get the capacity of a pipe.
+}
+
+// parseFcntlCmd takes a fcntl command value and returns its corresponding name.
+// If the command value does not match any known command, it returns the value as a string.
+func parseFcntlCmd(cmd any) (string, error) {
+	c, ok := cmd.(int32)
+	if !ok {
+		return fmt.Sprintf("%v", cmd), transformErr.Throwf("parseFcntlCmd: parse value error expected %T received %T", c, cmd)
+	}
+
+	if name, ok := fcntlCmds[c]; ok {
+		return name, nil
+	}
+
+	return fmt.Sprintf("%v", c), nil
+}
+
 // parseOpenat2Flags takes an openat2 flags value (flags) and returns a string representation
 // of the corresponding flags based on the openFlags definitions.
 func parseOpenat2Flags(flags any) (string, error) {
@@ -471,6 +530,40 @@ func parseSocketType(typ any) (string, error) {
 	return strings.Join(ts, "|"), nil
 }
 
+// parseAccept4Flags takes the flags of an accept4 syscall and returns the SOCK_CLOEXEC and
+// SOCK_NONBLOCK flags set, 0 if none.
+func parseAccept4Flags(flags any) (string, error) {
+	f, ok := flags.(int32)
+	if !ok {
+		return fmt.Sprintf("%v", flags), transformErr.Throwf("parseAccept4Flags: parse value error expected %T received %T", f, flags)
+	}
+
+	var fs []string
+	if f&SOCK_CLOEXEC == SOCK_CLOEXEC {
+		fs = append(fs, "SOCK_CLOEXEC")
+	}
+
+	if f&SOCK_NONBLOCK == SOCK_NONBLOCK {
+		fs = append(fs, "SOCK_NONBLOCK")
+	}
+
+	if len(fs) == 0 {
+		return "0", nil
+	}
+
+	return strings.Join(fs, "|"), nil
+}
+
+// parseSockPeer takes the address of the peer of a socket and returns it, empty if the
+// socket is not an AF_INET or AF_INET6 socket.
+func parseSockPeer(peer any) (string, error) {
+	if peer == nil {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%v", peer), nil
+}
+
 // socketProtocols is a map that associates IP protocol numbers with their corresponding names.
 var socketProtocols = map[int32]string{
 	0:   "IPPROTO_IP",      // Internet Protocol (IP).
@@ -514,3 +607,23 @@ func parseSocketProtocol(protocol any) (string, error) {
 
 	return fmt.Sprintf("%v", p), nil
 }
+
+// lsmActions represents the outcomes of a LSM policy match.
+var lsmActions = map[uint8]string{
+	0: "detected", // The operation was reported only.
+	1: "denied",   // The operation was denied with EPERM.
+}
+
+// parseLsmAction takes the action of a LSM policy match and returns its name.
+func parseLsmAction(action any) (string, error) {
+	a, ok := action.(uint8)
+	if !ok {
+		return fmt.Sprintf("%v", action), transformErr.Throwf("parseLsmAction: parse value error expected %T received %T", a, action)
+	}
+
+	if name, ok := lsmActions[a]; ok {
+		return name, nil
+	}
+
+	return fmt.Sprintf("%v", a), nil
+}
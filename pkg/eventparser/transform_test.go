@@ -543,3 +543,251 @@ func Test_parseSocketProtocol(t *testing.T) {
 		})
 	}
 }
+
+// Test_parseLsmAction tests the parseLsmAction function.
+func Test_parseLsmAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid value type",
+			action:  1,
+			want:    "1",
+			wantErr: true,
+		},
+		{
+			name:   "detected",
+			action: uint8(0),
+			want:   "detected",
+		},
+		{
+			name:   "denied",
+			action: uint8(1),
+			want:   "denied",
+		},
+		{
+			name:   "unknown",
+			action: uint8(7),
+			want:   "7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLsmAction(tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseLsmAction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseLsmAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_parseExitSignal tests the parseExitSignal function.
+func Test_parseExitSignal(t *testing.T) {
+	tests := []struct {
+		name    string
+		sig     any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid value type",
+			sig:     9,
+			want:    "9",
+			wantErr: true,
+		},
+		{
+			name: "exited",
+			sig:  uint16(0),
+			want: "0",
+		},
+		{
+			name: "killed",
+			sig:  uint16(9),
+			want: "SIGKILL",
+		},
+		{
+			name: "unknown",
+			sig:  uint16(40),
+			want: "40",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExitSignal(tt.sig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseExitSignal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseExitSignal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_parseDup3Flags tests the parseDup3Flags function.
+func Test_parseDup3Flags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid value type",
+			flags:   1,
+			want:    "1",
+			wantErr: true,
+		},
+		{
+			name:  "no flags",
+			flags: int32(0),
+			want:  "0",
+		},
+		{
+			name:  "close-on-exec",
+			flags: int32(O_CLOEXEC),
+			want:  "O_CLOEXEC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDup3Flags(tt.flags)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDup3Flags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseDup3Flags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_parseFcntlCmd tests the parseFcntlCmd function.
+func Test_parseFcntlCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid value type",
+			cmd:     uint32(0),
+			want:    "0",
+			wantErr: true,
+		},
+		{
+			name: "duplicate",
+			cmd:  int32(0),
+			want: "F_DUPFD",
+		},
+		{
+			name: "duplicate close-on-exec",
+			cmd:  int32(1030),
+			want: "F_DUPFD_CLOEXEC",
+		},
+		{
+			name: "unknown",
+			cmd:  int32(2048),
+			want: "2048",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFcntlCmd(tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseFcntlCmd() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseFcntlCmd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_parseAccept4Flags tests the parseAccept4Flags function.
+func Test_parseAccept4Flags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid value type",
+			flags:   1,
+			want:    "1",
+			wantErr: true,
+		},
+		{
+			name:  "no flags",
+			flags: int32(0),
+			want:  "0",
+		},
+		{
+			name:  "close-on-exec and non-blocking",
+			flags: int32(SOCK_CLOEXEC | SOCK_NONBLOCK),
+			want:  "SOCK_CLOEXEC|SOCK_NONBLOCK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAccept4Flags(tt.flags)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseAccept4Flags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseAccept4Flags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_parseSockPeer tests the parseSockPeer function.
+func Test_parseSockPeer(t *testing.T) {
+	tests := []struct {
+		name string
+		peer any
+		want string
+	}{
+		{
+			name: "unknown family",
+			peer: nil,
+			want: "",
+		},
+		{
+			name: "inet",
+			peer: "{Family:AF_INET Sa_addr:10.0.0.2 Sa_port:443}",
+			want: "{Family:AF_INET Sa_addr:10.0.0.2 Sa_port:443}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSockPeer(tt.peer)
+			if err != nil {
+				t.Errorf("parseSockPeer() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseSockPeer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
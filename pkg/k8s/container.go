@@ -44,6 +44,24 @@ func ProcsContainerID(pid uint32) (string, error) {
 	return containerID, nil
 }
 
+// ContainerPath returns the path of the file at path in the mount namespace of the
+// process pid, resolved through the root of the process in HostProcDir. It makes the
+// executables and libraries of containers reachable, e.g. to attach uprobes.
+func ContainerPath(pid uint32, path string) string {
+	return filepath.Join(HostProcDir, fmt.Sprint(pid), "root", path)
+}
+
+// ProcsExecutable returns the path of the executable of a given process ID (pid),
+// resolved with ContainerPath.
+func ProcsExecutable(pid uint32) (string, error) {
+	exe, err := os.Readlink(filepath.Join(HostProcDir, fmt.Sprint(pid), "exe"))
+	if err != nil {
+		return "", containerErr.Throwf("%v", err)
+	}
+
+	return ContainerPath(pid, exe), nil
+}
+
 // FindDockerIDFromCgroup extracts the Docker container ID from a cgroup string.
 // It does this by splitting the cgroup string into paths, and checking each path
 // for the presence of a Docker container ID. If a valid ID is found, it is returned.
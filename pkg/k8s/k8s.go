@@ -120,6 +120,11 @@ func (watcher *PodWatcher) Start() {
 	watcher.informerFactory.WaitForCacheSync(wait.NeverStop)
 }
 
+// HasSynced reports whether the cache of the pods is synced with the Kubernetes API server.
+func (watcher *PodWatcher) HasSynced() bool {
+	return watcher.podInformer.HasSynced()
+}
+
 // FindPod finds a pod by its container ID.
 func (watcher *PodWatcher) FindPod(containerID string) (*corev1.Pod, error) {
 	indexedContainerID := containerID
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+// Package process provides an in-memory table of the processes seen in the events, built from the
+// execId and parentExecId the eBPF programs stamp on every event. It tracks the binary and the
+// arguments of the processes, from their execve and execveat events, and their lineage, so that the
+// ancestry of the process of an event can be attached to it. The processes running before the
+// detector are read from procfs into a Snapshot, which seeds the table and returns a synthetic
+// process_snapshot event for every process. An FdTable tracks the file descriptors the processes
+// open, so that the fd of an event can be resolved to the path of a file or the addresses of a socket,
+// and records the connections into Flows, returning a synthetic flow event for every connection closed.
+package process
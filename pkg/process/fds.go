@@ -0,0 +1,575 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"container/list"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/intelops/tarian-detector/pkg/err"
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+var fdsErr = err.New("process.fds")
+
+// einprogress is the return value of a connect of a non-blocking socket, established later.
+const einprogress = -115
+
+// fdCloexec is the close-on-exec flag of the file descriptor flags of fcntl F_SETFD.
+const fdCloexec = 1
+
+// Descriptor is what an open file descriptor refers to, attached to the events by FdTable.Enrich.
+type Descriptor struct {
+	Type       string `json:"type"`                 // Type is file or socket.
+	Path       string `json:"path,omitempty"`       // Path is the path of the file, or the target of the fd in procfs, e.g. socket:[1234], for a fd of a snapshot.
+	Family     string `json:"family,omitempty"`     // Family is the address family of the socket, e.g. AF_INET.
+	SocketType string `json:"socketType,omitempty"` // SocketType is the type of the socket, e.g. SOCK_STREAM.
+	Protocol   string `json:"protocol,omitempty"`   // Protocol is the protocol of the socket, e.g. IPPROTO_TCP.
+	Local      string `json:"local,omitempty"`      // Local is the address the socket is bound to.
+	Remote     string `json:"remote,omitempty"`     // Remote is the address the socket is connected to.
+}
+
+// fdSet is the open file descriptors of a process.
+type fdSet struct {
+	hostPid uint32                // hostPid is the ID of the process on the host.
+	fds     map[int32]*Descriptor // fds are the descriptors, by file descriptor.
+	flows   map[int32]*Flow       // flows are the flows of the sockets, by file descriptor.
+	cloexec map[int32]bool        // cloexec are the file descriptors closed when the process executes a program.
+}
+
+// syscallArgs are the arguments of the entry event of a syscall in progress.
+type syscallArgs struct {
+	syscall string
+	args    []eventparser.Arg
+}
+
+// syscallExit is the return value and the arguments of the exit event of a syscall, read before its
+// entry event.
+type syscallExit struct {
+	syscall string
+	ret     int64
+	ts      uint64
+	args    []eventparser.Arg
+}
+
+// FdTable is a table of the file descriptors the processes opened, indexed by their host process ID,
+// the threads of a process sharing its file descriptors. It is built from the open, openat, openat2,
+// socket, accept and accept4 events, whose return value is the new file descriptor, the dup, dup2, dup3
+// and fcntl F_DUPFD events, duplicating a file descriptor, the bind and connect events, giving the
+// addresses of the sockets, and the close events. The close-on-exec file descriptors are removed when
+// the process executes a program. The file descriptors returned by the syscalls not traced, e.g. pipe or
+// socketpair, are not known. It holds a bounded number of processes, the least recently seen are
+// evicted first. It is safe for concurrent use.
+type FdTable struct {
+	size      int                      // size is the maximum number of processes.
+	flows     *Flows                   // flows receives the flows ended, nil to not record them.
+	processes map[uint32]*list.Element // processes are the elements of lru, by host process ID.
+	pending   map[uint32]syscallArgs   // pending are the syscalls in progress, by host thread ID.
+	orphans   map[uint32]syscallExit   // orphans are the exit events read before their entry event, by host thread ID.
+	lru       *list.List               // lru holds the fdSet of the processes, from the most to the least recently seen.
+	mu        sync.Mutex               // mu guards the processes.
+}
+
+// NewFdTable creates a new FdTable holding the file descriptors of up to size processes, DefaultSize
+// if size is not positive.
+func NewFdTable(size int) *FdTable {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	return &FdTable{
+		size:      size,
+		processes: make(map[uint32]*list.Element),
+		pending:   make(map[uint32]syscallArgs),
+		orphans:   make(map[uint32]syscallExit),
+		lru:       list.New(),
+	}
+}
+
+// RecordFlows records the connections of the processes into flows: the sockets connected, accepted or
+// listened on, with the bytes the read, readv, write and writev syscalls of the process transferred,
+// until their close or the exit of the process. The flows of the sockets open before the detector
+// started, inherited by a forked child, duplicated or of the processes evicted are not recorded.
+func (f *FdTable) RecordFlows(flows *Flows) *FdTable {
+	f.flows = flows
+	return f
+}
+
+// Enrich updates the table from the event and attaches what its fd argument refers to, if it is
+// known, in the descriptor field; an exit event gets the descriptor of its entry event. An entry event
+// is applied once its exit event tells the syscall succeeded, with the return value and the arguments
+// of both, and an exit event read first is held until its entry event. A forked child inherits the file
+// descriptors of its parent, an exited process loses them, and a process executing a program loses its
+// close-on-exec ones. The synthetic events are left as they are.
+func (f *FdTable) Enrich(event map[string]any) error {
+	hostPid, ok := event["hostProcessId"].(uint32)
+	if !ok {
+		return fdsErr.Throw("missing hostProcessId")
+	}
+
+	// the synthetic events are of the snapshot, seeding the table, or of the flows the table recorded
+	if synthetic, _ := event["synthetic"].(bool); synthetic {
+		return nil
+	}
+
+	hostTid, _ := event["hostThreadId"].(uint32)
+	name, _ := event["eventId"].(string)
+	args, _ := event["context"].([]eventparser.Arg)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fds := f.touch(hostPid)
+
+	switch name {
+	case "sched_process_fork":
+		f.fork(fds, args)
+		return nil
+	case "sched_process_exec":
+		f.exec(fds, event)
+		return nil
+	case "sched_process_exit":
+		delete(f.pending, hostTid)
+		delete(f.orphans, hostTid)
+		if argValue(args, "group_dead") == "1" {
+			for fd := range fds.flows {
+				f.endFlow(fds, fd, event)
+			}
+
+			f.lru.Remove(f.processes[hostPid])
+			delete(f.processes, hostPid)
+		}
+
+		return nil
+	}
+
+	syscall, isEntry := strings.CutSuffix(name, "_entry")
+	syscall, isExit := strings.CutSuffix(syscall, "_exit")
+
+	ts, _ := event["timestamp"].(uint64)
+	ret, hasRet := parseReturn(argValue(args, "return"))
+	if isEntry {
+		hasRet = false
+		if exit, ok := f.orphans[hostTid]; ok && exit.syscall == syscall && exit.ts >= ts {
+			delete(f.orphans, hostTid)
+			ret, hasRet = exit.ret, true
+			args = joinArgs(args, exit.args)
+		} else {
+			f.pending[hostTid] = syscallArgs{syscall: syscall, args: args}
+		}
+	} else if isExit {
+		call, ok := f.pending[hostTid]
+		if !ok || call.syscall != syscall {
+			// the entry event may be read after it
+			if hasRet {
+				f.orphans[hostTid] = syscallExit{syscall: syscall, ret: ret, ts: ts, args: args}
+			}
+
+			return nil
+		}
+
+		delete(f.pending, hostTid)
+		args = joinArgs(call.args, args)
+	}
+
+	if fd, ok := parseFd(argValue(args, "fd")); ok {
+		if d, ok := fds.fds[fd]; ok {
+			event["descriptor"] = *d
+		}
+	}
+
+	if hasRet {
+		f.apply(fds, syscall, args, ret, event)
+	}
+
+	return nil
+}
+
+// touch returns the file descriptors of the process, added to the table if it is not known, and marks
+// it as the most recently seen. The caller holds f.mu.
+func (f *FdTable) touch(hostPid uint32) *fdSet {
+	if e, ok := f.processes[hostPid]; ok {
+		f.lru.MoveToFront(e)
+		return e.Value.(*fdSet)
+	}
+
+	return f.add(hostPid)
+}
+
+// add adds a process without file descriptors to the table, evicting the least recently seen process
+// if the table is full. The caller holds f.mu.
+func (f *FdTable) add(hostPid uint32) *fdSet {
+	if f.lru.Len() >= f.size {
+		e := f.lru.Back()
+		f.lru.Remove(e)
+		delete(f.processes, e.Value.(*fdSet).hostPid)
+	}
+
+	fds := &fdSet{
+		hostPid: hostPid,
+		fds:     make(map[int32]*Descriptor),
+		flows:   make(map[int32]*Flow),
+		cloexec: make(map[int32]bool),
+	}
+	f.processes[hostPid] = f.lru.PushFront(fds)
+
+	return fds
+}
+
+// fork copies the file descriptors of the parent to its forked child, a process reusing the host
+// process ID of an exited one. The threads share the file descriptors of their process. The caller
+// holds f.mu.
+func (f *FdTable) fork(parent *fdSet, args []eventparser.Arg) {
+	childPid, err := strconv.ParseUint(argValue(args, "child_pid"), 10, 32)
+	if err != nil || uint32(childPid) == parent.hostPid {
+		return
+	}
+
+	if e, ok := f.processes[uint32(childPid)]; ok {
+		f.lru.Remove(e)
+		delete(f.processes, uint32(childPid))
+	}
+
+	child := f.add(uint32(childPid))
+	for fd, d := range parent.fds {
+		copied := *d
+		child.fds[fd] = &copied
+	}
+
+	for fd := range parent.cloexec {
+		child.cloexec[fd] = true
+	}
+}
+
+// exec removes the close-on-exec file descriptors of the process executing a program, ending their
+// flows with the event. The caller holds f.mu.
+func (f *FdTable) exec(fds *fdSet, event map[string]any) {
+	for fd := range fds.cloexec {
+		f.endFlow(fds, fd, event)
+		delete(fds.fds, fd)
+		delete(fds.cloexec, fd)
+	}
+}
+
+// dup makes the file descriptor newfd refer to the descriptor of oldfd, the same open file, closing
+// what newfd referred to. newfd is forgotten if oldfd is not known. The caller holds f.mu.
+func (f *FdTable) dup(fds *fdSet, oldfd, newfd int32, cloexec bool, event map[string]any) {
+	f.endFlow(fds, newfd, event)
+	delete(fds.cloexec, newfd)
+
+	d, ok := fds.fds[oldfd]
+	if !ok {
+		delete(fds.fds, newfd)
+		return
+	}
+
+	fds.fds[newfd] = d
+	if cloexec {
+		fds.cloexec[newfd] = true
+	}
+
+	event["descriptor"] = *d
+}
+
+// apply updates the file descriptors of the process from the arguments of the syscall and its return
+// value ret, and attaches the descriptor of a new file descriptor to the event. The caller holds f.mu.
+func (f *FdTable) apply(fds *fdSet, syscall string, args []eventparser.Arg, ret int64, event map[string]any) {
+	fd, hasFd := parseFd(argValue(args, "fd"))
+	ts, _ := event["timestamp"].(uint64)
+
+	var d *Descriptor
+	var cloexec bool
+	switch syscall {
+	case "sys_open", "sys_openat", "sys_openat2":
+		if ret < 0 {
+			return
+		}
+
+		directory, _ := event["directory"].(string)
+		d = &Descriptor{Type: "file", Path: f.resolve(fds, argValue(args, "dfd"), argValue(args, "filename"), directory)}
+		cloexec = hasFlag(argValue(args, "flags"), "O_CLOEXEC")
+	case "sys_socket":
+		if ret < 0 {
+			return
+		}
+
+		cloexec = hasFlag(argValue(args, "type"), "SOCK_CLOEXEC")
+		d = &Descriptor{
+			Type:       "socket",
+			Family:     argValue(args, "family"),
+			SocketType: argValue(args, "type"),
+			Protocol:   argValue(args, "protocol"),
+		}
+	case "sys_accept", "sys_accept4":
+		if ret < 0 || !hasFd {
+			return
+		}
+
+		// the accepted socket is of the listening socket, on its address; the peer address,
+		// written by the syscall after the entry event read it, is read from the socket on exit
+		d = &Descriptor{Type: "socket", Remote: argValue(args, "peer")}
+		if listening, ok := fds.fds[fd]; ok {
+			d.Family, d.SocketType, d.Protocol, d.Local = listening.Family, listening.SocketType, listening.Protocol, listening.Local
+		}
+
+		f.endFlow(fds, int32(ret), event)
+		f.startFlow(fds, int32(ret), Inbound, ts)
+		cloexec = hasFlag(argValue(args, "flags"), "SOCK_CLOEXEC")
+	case "sys_dup", "sys_dup2", "sys_dup3":
+		oldfd, ok := parseFd(argValue(args, "oldfd"))
+		if syscall == "sys_dup" {
+			oldfd, ok = parseFd(argValue(args, "fildes"))
+		}
+
+		// dup2 of a file descriptor to itself does nothing
+		if ret < 0 || (ok && int64(oldfd) == ret) {
+			return
+		}
+
+		if !ok {
+			oldfd = -1
+		}
+
+		f.dup(fds, oldfd, int32(ret), hasFlag(argValue(args, "flags"), "O_CLOEXEC"), event)
+		return
+	case "sys_fcntl":
+		if !hasFd || ret < 0 {
+			return
+		}
+
+		switch cmd := argValue(args, "cmd"); cmd {
+		case "F_DUPFD", "F_DUPFD_CLOEXEC":
+			f.dup(fds, fd, int32(ret), cmd == "F_DUPFD_CLOEXEC", event)
+		case "F_SETFD":
+			if _, ok := fds.fds[fd]; !ok {
+				return
+			}
+
+			if arg, _ := strconv.ParseUint(argValue(args, "arg"), 10, 64); arg&fdCloexec != 0 {
+				fds.cloexec[fd] = true
+			} else {
+				delete(fds.cloexec, fd)
+			}
+		}
+
+		return
+	case "sys_execve", "sys_execveat":
+		if ret == 0 {
+			f.exec(fds, event)
+		}
+
+		return
+	case "sys_bind":
+		if listening, ok := fds.fds[fd]; ok && hasFd && ret == 0 {
+			listening.Local = argValue(args, "umyaddr")
+			event["descriptor"] = *listening
+		}
+
+		return
+	case "sys_connect":
+		if socket, ok := fds.fds[fd]; ok && hasFd && (ret == 0 || ret == einprogress) {
+			socket.Remote = argValue(args, "uservaddr")
+			event["descriptor"] = *socket
+
+			// a datagram socket connected again starts a new flow
+			f.endFlow(fds, fd, event)
+			f.startFlow(fds, fd, Outbound, ts)
+		}
+
+		return
+	case "sys_listen":
+		if _, ok := fds.fds[fd]; ok && hasFd && ret == 0 {
+			f.startFlow(fds, fd, Listening, ts)
+		}
+
+		return
+	case "sys_read", "sys_readv":
+		if flow, ok := fds.flows[fd]; ok && hasFd && ret > 0 {
+			flow.BytesReceived += uint64(ret)
+		}
+
+		return
+	case "sys_write", "sys_writev":
+		if flow, ok := fds.flows[fd]; ok && hasFd && ret > 0 {
+			flow.BytesSent += uint64(ret)
+		}
+
+		return
+	case "sys_close":
+		// the file descriptor is released even if the close failed, unless it was not open
+		if hasFd {
+			f.endFlow(fds, fd, event)
+			delete(fds.fds, fd)
+			delete(fds.cloexec, fd)
+		}
+
+		return
+	default:
+		return
+	}
+
+	// a file descriptor reused, its close was not seen
+	if syscall != "sys_accept" && syscall != "sys_accept4" {
+		f.endFlow(fds, int32(ret), event)
+	}
+
+	fds.fds[int32(ret)] = d
+	if cloexec {
+		fds.cloexec[int32(ret)] = true
+	} else {
+		delete(fds.cloexec, int32(ret))
+	}
+
+	event["descriptor"] = *d
+}
+
+// startFlow starts the flow of the socket fd at the timestamp ts, if the flows are recorded. The caller
+// holds f.mu.
+func (f *FdTable) startFlow(fds *fdSet, fd int32, direction string, ts uint64) {
+	if f.flows == nil {
+		return
+	}
+
+	fds.flows[fd] = &Flow{Fd: fd, Direction: direction, Start: ts}
+}
+
+// endFlow ends the flow of the socket fd, if any, with the event closing the socket or exiting the
+// process, and sends it to the flows. The caller holds f.mu.
+func (f *FdTable) endFlow(fds *fdSet, fd int32, event map[string]any) {
+	flow, ok := fds.flows[fd]
+	if !ok {
+		return
+	}
+
+	delete(fds.flows, fd)
+
+	flow.End, _ = event["timestamp"].(uint64)
+	if socket, ok := fds.fds[fd]; ok {
+		flow.Socket = *socket
+	}
+
+	flow.Meta = eventparser.MetaDataFromMap(event)
+	f.flows.send(*flow)
+}
+
+// resolve returns the path of the file opened relative to the directory dfd, the current working
+// directory directory for AT_FDCWD. A relative path is returned as is if the directory is not known.
+// The caller holds f.mu.
+func (f *FdTable) resolve(fds *fdSet, dfd, filename, directory string) string {
+	if path.IsAbs(filename) {
+		return path.Clean(filename)
+	}
+
+	dir := directory
+	if fd, ok := parseFd(dfd); ok {
+		dir = ""
+		if d, ok := fds.fds[fd]; ok && d.Type == "file" {
+			dir = d.Path
+		}
+	}
+
+	if !path.IsAbs(dir) {
+		return filename
+	}
+
+	return path.Join(dir, filename)
+}
+
+// Seed adds the open file descriptors of the processes of a snapshot, running before the events are
+// read. Their targets in procfs are the paths of the files, or e.g. socket:[1234] for the sockets.
+func (f *FdTable) Seed(procs []Proc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, proc := range procs {
+		fds := f.touch(proc.Meta.MetaData.Task.HostPid)
+		for fd, target := range proc.Fds {
+			if _, ok := fds.fds[fd]; ok {
+				continue
+			}
+
+			d := &Descriptor{Type: "file", Path: target}
+			if strings.HasPrefix(target, "socket:") {
+				d.Type = "socket"
+			}
+
+			fds.fds[fd] = d
+		}
+	}
+}
+
+// Get returns the descriptor of the file descriptor fd of the process, false if it is not known.
+func (f *FdTable) Get(hostPid uint32, fd int32) (Descriptor, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	e, ok := f.processes[hostPid]
+	if !ok {
+		return Descriptor{}, false
+	}
+
+	d, ok := e.Value.(*fdSet).fds[fd]
+	if !ok {
+		return Descriptor{}, false
+	}
+
+	return *d, true
+}
+
+// Len returns the number of processes in the table.
+func (f *FdTable) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.lru.Len()
+}
+
+// parseFd returns the file descriptor of the argument value, false if it is not one, e.g. AT_FDCWD.
+func parseFd(value string) (int32, bool) {
+	fd, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || fd < 0 {
+		return 0, false
+	}
+
+	return int32(fd), true
+}
+
+// joinArgs returns the arguments of the entry event of a syscall followed by those of its exit event,
+// like the events of the fexit backend.
+func joinArgs(entry, exit []eventparser.Arg) []eventparser.Arg {
+	args := make([]eventparser.Arg, 0, len(entry)+len(exit))
+	args = append(args, entry...)
+
+	return append(args, exit...)
+}
+
+// hasFlag reports whether the flags of the argument value, e.g. O_RDONLY|O_CLOEXEC, include flag.
+func hasFlag(value, flag string) bool {
+	for _, f := range strings.Split(value, "|") {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseReturn returns the return value of the argument value. The return values of some syscalls are
+// written as unsigned, the errors are then read back as negative values.
+func parseReturn(value string) (int64, bool) {
+	ret, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if ret > math.MaxInt32 && ret <= math.MaxUint32 {
+		ret = int64(int32(uint32(ret)))
+	}
+
+	return ret, true
+}
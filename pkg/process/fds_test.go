@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// fdEvent returns an event of the thread tid of the process pid, running in /srv.
+func fdEvent(name string, pid, tid uint32, args ...eventparser.Arg) map[string]any {
+	return map[string]any{
+		"eventId":       name,
+		"hostProcessId": pid,
+		"hostThreadId":  tid,
+		"directory":     "/srv",
+		"context":       args,
+	}
+}
+
+func arg(name, value string) eventparser.Arg { return eventparser.Arg{Name: name, Value: value} }
+
+const (
+	serverAddr = "{Family:AF_INET Sa_addr:0.0.0.0 Sa_port:8080}"
+	peerAddr   = "{Family:AF_INET Sa_addr:10.0.0.2 Sa_port:443}"
+)
+
+// TestFdTable_Enrich tests the Enrich function
+func TestFdTable_Enrich(t *testing.T) {
+	tcp := Descriptor{Type: "socket", Family: "AF_INET", SocketType: "SOCK_STREAM", Protocol: "IPPROTO_TCP"}
+	socket := fdEvent("sys_socket", 10, 10, arg("family", "AF_INET"), arg("type", "SOCK_STREAM"), arg("protocol", "IPPROTO_TCP"), ret("3"))
+
+	withLocal := func(d Descriptor, local string) Descriptor { d.Local = local; return d }
+	withRemote := func(d Descriptor, remote string) Descriptor { d.Remote = remote; return d }
+
+	tests := []struct {
+		name   string
+		events []map[string]any
+		want   any // descriptor of the last event, nil if it has none
+	}{
+		{
+			name:   "unknown fd",
+			events: []map[string]any{fdEvent("sys_write_entry", 10, 10, arg("fd", "7"))},
+			want:   nil,
+		},
+		{
+			name: "write to an opened file",
+			events: []map[string]any{
+				fdEvent("sys_openat_entry", 10, 10, arg("dfd", "AT_FDCWD"), filename("/var/log/app.log")),
+				fdEvent("sys_openat_exit", 10, 10, ret("7")),
+				fdEvent("sys_write_entry", 10, 10, arg("fd", "7")),
+			},
+			want: Descriptor{Type: "file", Path: "/var/log/app.log"},
+		},
+		{
+			name: "exit event of a write",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("7")),
+				fdEvent("sys_write_entry", 10, 11, arg("fd", "7")),
+				fdEvent("sys_write_exit", 10, 11, ret("12")),
+			},
+			want: Descriptor{Type: "file", Path: "/etc/hosts"},
+		},
+		{
+			name: "failed open",
+			events: []map[string]any{
+				fdEvent("sys_open_entry", 10, 10, filename("/etc/shadow")),
+				fdEvent("sys_open_exit", 10, 10, ret("4294967283")),
+				fdEvent("sys_read", 10, 10, arg("fd", "4294967283")),
+			},
+			want: nil,
+		},
+		{
+			name: "relative paths",
+			events: []map[string]any{
+				fdEvent("sys_openat", 10, 10, arg("dfd", "AT_FDCWD"), filename("conf"), ret("3")),
+				fdEvent("sys_openat2", 10, 10, arg("dfd", "3"), filename("../app.yaml"), ret("4")),
+				fdEvent("sys_read", 10, 10, arg("fd", "4"), ret("100")),
+			},
+			want: Descriptor{Type: "file", Path: "/srv/app.yaml"},
+		},
+		{
+			name: "exit of another syscall",
+			events: []map[string]any{
+				fdEvent("sys_openat_entry", 10, 10, filename("/etc/hosts")),
+				fdEvent("sys_socket_exit", 10, 10, ret("7")),
+				fdEvent("sys_read_entry", 10, 10, arg("fd", "7")),
+			},
+			want: nil,
+		},
+		{
+			name: "exit event before its entry",
+			events: []map[string]any{
+				at(200, fdEvent("sys_openat_exit", 10, 10, ret("7"))),
+				at(100, fdEvent("sys_openat_entry", 10, 10, arg("dfd", "AT_FDCWD"), filename("/var/log/app.log"))),
+				at(300, fdEvent("sys_write_entry", 10, 10, arg("fd", "7"))),
+			},
+			want: Descriptor{Type: "file", Path: "/var/log/app.log"},
+		},
+		{
+			name: "exit event of a previous syscall",
+			events: []map[string]any{
+				at(100, fdEvent("sys_openat_exit", 10, 10, ret("7"))),
+				at(200, fdEvent("sys_openat_entry", 10, 10, filename("/etc/hosts"))),
+				at(300, fdEvent("sys_read_entry", 10, 10, arg("fd", "7"))),
+			},
+			want: nil,
+		},
+		{
+			name: "closed fd",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("7")),
+				fdEvent("sys_close_entry", 10, 10, arg("fd", "7")),
+				fdEvent("sys_close_exit", 10, 10, ret("0")),
+				fdEvent("sys_read_entry", 10, 10, arg("fd", "7")),
+			},
+			want: nil,
+		},
+		{
+			name: "close of an opened file",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("7")),
+				fdEvent("sys_close", 10, 10, arg("fd", "7"), ret("0")),
+			},
+			want: Descriptor{Type: "file", Path: "/etc/hosts"},
+		},
+		{
+			name: "connected socket",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_connect", 10, 10, arg("fd", "3"), arg("uservaddr", peerAddr), ret("-115")),
+				fdEvent("sys_writev_entry", 10, 10, arg("fd", "3")),
+			},
+			want: withRemote(tcp, peerAddr),
+		},
+		{
+			name: "failed connect",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_connect", 10, 10, arg("fd", "3"), arg("uservaddr", peerAddr), ret("-111")),
+				fdEvent("sys_writev_entry", 10, 10, arg("fd", "3")),
+			},
+			want: tcp,
+		},
+		{
+			name: "accepted socket",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_bind", 10, 10, arg("fd", "3"), arg("umyaddr", serverAddr), ret("0")),
+				fdEvent("sys_accept_entry", 10, 12, arg("fd", "3")),
+				fdEvent("sys_accept_exit", 10, 12, ret("5"), arg("peer", peerAddr)),
+				fdEvent("sys_readv", 10, 10, arg("fd", "5"), ret("10")),
+			},
+			want: withRemote(withLocal(tcp, serverAddr), peerAddr),
+		},
+		{
+			name: "accept exit event before its entry",
+			events: []map[string]any{
+				socket,
+				at(200, fdEvent("sys_accept_exit", 10, 12, ret("5"), arg("peer", peerAddr))),
+				at(100, fdEvent("sys_accept_entry", 10, 12, arg("fd", "3"))),
+				at(300, fdEvent("sys_readv", 10, 10, arg("fd", "5"), ret("10"))),
+			},
+			want: withRemote(tcp, peerAddr),
+		},
+		{
+			name: "accept4",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_bind", 10, 10, arg("fd", "3"), arg("umyaddr", serverAddr), ret("0")),
+				fdEvent("sys_accept4", 10, 12, arg("fd", "3"), arg("flags", "SOCK_CLOEXEC"), ret("5"), arg("peer", peerAddr)),
+				fdEvent("sys_read", 10, 10, arg("fd", "5"), ret("10")),
+			},
+			want: withRemote(withLocal(tcp, serverAddr), peerAddr),
+		},
+		{
+			name: "dup2 to the standard output",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/var/log/app.log"), ret("7")),
+				fdEvent("sys_dup2_entry", 10, 10, arg("oldfd", "7"), arg("newfd", "1")),
+				fdEvent("sys_dup2_exit", 10, 10, ret("1")),
+				fdEvent("sys_write", 10, 10, arg("fd", "1"), ret("12")),
+			},
+			want: Descriptor{Type: "file", Path: "/var/log/app.log"},
+		},
+		{
+			name: "dup of a closed fd",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_dup", 10, 10, arg("fildes", "3"), ret("4")),
+				fdEvent("sys_close", 10, 10, arg("fd", "3"), ret("0")),
+				fdEvent("sys_write", 10, 10, arg("fd", "4"), ret("12")),
+			},
+			want: tcp,
+		},
+		{
+			name: "dup3 of an unknown fd",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("1")),
+				fdEvent("sys_dup3", 10, 10, arg("oldfd", "9"), arg("newfd", "1"), arg("flags", "0"), ret("1")),
+				fdEvent("sys_write", 10, 10, arg("fd", "1"), ret("12")),
+			},
+			want: nil,
+		},
+		{
+			name: "fcntl F_DUPFD",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("3")),
+				fdEvent("sys_fcntl", 10, 10, arg("fd", "3"), arg("cmd", "F_DUPFD"), arg("arg", "10"), ret("10")),
+				fdEvent("sys_read", 10, 10, arg("fd", "10"), ret("12")),
+			},
+			want: Descriptor{Type: "file", Path: "/etc/hosts"},
+		},
+		{
+			name: "close-on-exec fd at exec",
+			events: []map[string]any{
+				fdEvent("sys_openat", 10, 10, arg("dfd", "AT_FDCWD"), filename("/etc/hosts"), arg("flags", "O_RDONLY|O_CLOEXEC"), ret("3")),
+				fdEvent("sched_process_exec", 10, 10, filename("/bin/sh")),
+				fdEvent("sys_read", 10, 10, arg("fd", "3"), ret("12")),
+			},
+			want: nil,
+		},
+		{
+			name: "fd kept at exec",
+			events: []map[string]any{
+				fdEvent("sys_openat", 10, 10, arg("dfd", "AT_FDCWD"), filename("/etc/hosts"), arg("flags", "O_RDONLY|O_CLOEXEC"), ret("3")),
+				fdEvent("sys_fcntl", 10, 10, arg("fd", "3"), arg("cmd", "F_SETFD"), arg("arg", "0"), ret("0")),
+				fdEvent("sys_execve", 10, 10, filename("/bin/sh"), ret("0")),
+				fdEvent("sys_read", 10, 10, arg("fd", "3"), ret("12")),
+			},
+			want: Descriptor{Type: "file", Path: "/etc/hosts"},
+		},
+		{
+			name: "close-on-exec fd of F_DUPFD_CLOEXEC",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_fcntl", 10, 10, arg("fd", "3"), arg("cmd", "F_DUPFD_CLOEXEC"), arg("arg", "0"), ret("4")),
+				fdEvent("sys_execveat", 10, 10, filename("/bin/sh"), ret("0")),
+				fdEvent("sys_write", 10, 10, arg("fd", "4"), ret("12")),
+			},
+			want: nil,
+		},
+		{
+			name: "inherited by a forked child",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("7")),
+				fdEvent("sched_process_fork", 10, 10, fork("20", "2")...),
+				fdEvent("sys_read_entry", 20, 20, arg("fd", "7")),
+			},
+			want: Descriptor{Type: "file", Path: "/etc/hosts"},
+		},
+		{
+			name: "exited process",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("7")),
+				fdEvent("sched_process_exit", 10, 10, arg("group_dead", "1")),
+				fdEvent("sys_read_entry", 10, 10, arg("fd", "7")),
+			},
+			want: nil,
+		},
+		{
+			name: "exited thread",
+			events: []map[string]any{
+				fdEvent("sys_open", 10, 10, filename("/etc/hosts"), ret("7")),
+				fdEvent("sched_process_exit", 10, 11, arg("group_dead", "0")),
+				fdEvent("sys_read_entry", 10, 10, arg("fd", "7")),
+			},
+			want: Descriptor{Type: "file", Path: "/etc/hosts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := NewFdTable(0)
+
+			var last map[string]any
+			for _, e := range tt.events {
+				if err := table.Enrich(e); err != nil {
+					t.Fatalf("FdTable.Enrich() error = %v", err)
+				}
+
+				last = e
+			}
+
+			if got := last["descriptor"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FdTable.Enrich() descriptor = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFdTable_Seed tests the Seed function
+func TestFdTable_Seed(t *testing.T) {
+	table := NewFdTable(1)
+	if err := table.Enrich(map[string]any{}); err == nil {
+		t.Errorf("FdTable.Enrich() error = %v, wantErr %v", err, true)
+	}
+
+	proc := seed(1, 0, 10, "/usr/sbin/nginx", "nginx")
+	proc.Fds = map[int32]string{0: "/dev/null", 6: "socket:[1234]"}
+	table.Seed([]Proc{proc})
+
+	if got, ok := table.Get(10, 0); !ok || got != (Descriptor{Type: "file", Path: "/dev/null"}) {
+		t.Errorf("FdTable.Get() = %+v, %v", got, ok)
+	}
+
+	if got, ok := table.Get(10, 6); !ok || got != (Descriptor{Type: "socket", Path: "socket:[1234]"}) {
+		t.Errorf("FdTable.Get() = %+v, %v", got, ok)
+	}
+
+	// the least recently seen process is evicted
+	if err := table.Enrich(fdEvent("sys_read_entry", 20, 20, arg("fd", "0"))); err != nil {
+		t.Fatalf("FdTable.Enrich() error = %v", err)
+	}
+
+	if _, ok := table.Get(10, 0); ok || table.Len() != 1 {
+		t.Errorf("FdTable.Get() ok = %v, FdTable.Len() = %v, want %v, %v", ok, table.Len(), false, 1)
+	}
+}
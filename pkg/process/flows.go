@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+	"golang.org/x/sys/unix"
+)
+
+// Directions of the flows.
+const (
+	Outbound  = "outbound" // Outbound is a connection the process made, with connect.
+	Inbound   = "inbound"  // Inbound is a connection the process accepted, with accept.
+	Listening = "listen"   // Listening is a socket the process listened on, with listen.
+)
+
+// DefaultFlowQueueSize is the number of flows a Flows holds until they are read by default.
+const DefaultFlowQueueSize = 4096
+
+// Flow is a connection of a process, from its connect, accept or listen to the close of its socket or
+// the exit of the process.
+type Flow struct {
+	Fd            int32                      // Fd is the file descriptor of the socket.
+	Direction     string                     // Direction is Outbound, Inbound or Listening.
+	Socket        Descriptor                 // Socket is the socket, with its addresses.
+	Start         uint64                     // Start is the kernel timestamp of the connect, accept or listen.
+	End           uint64                     // End is the kernel timestamp of the close or the exit.
+	BytesSent     uint64                     // BytesSent is the number of bytes written to the socket.
+	BytesReceived uint64                     // BytesReceived is the number of bytes read from the socket.
+	Meta          eventparser.TarianMetaData // Meta is the metadata of the event ending the flow.
+}
+
+// Flows is the queue of the flows ended, filled by the FdTable recording them. It returns a synthetic
+// flow event for every flow, with the metadata of the process at its end, timestamped when it is
+// read. The flows are dropped when the queue is full, so that the enrichment of the events is never
+// blocked. It is a detector.EventDetector.
+type Flows struct {
+	flows   chan Flow     // flows are the flows ended and not yet read.
+	closed  chan struct{} // closed is closed by Close.
+	once    sync.Once     // once closes closed.
+	dropped atomic.Uint64 // dropped is the number of flows dropped.
+}
+
+// NewFlows creates a new Flows holding up to size flows until they are read, DefaultFlowQueueSize
+// if size is not positive.
+func NewFlows(size int) *Flows {
+	if size <= 0 {
+		size = DefaultFlowQueueSize
+	}
+
+	return &Flows{flows: make(chan Flow, size), closed: make(chan struct{})}
+}
+
+// send queues the flow, or drops it if the queue is full.
+func (f *Flows) send(flow Flow) {
+	select {
+	case f.flows <- flow:
+	default:
+		f.dropped.Add(1)
+	}
+}
+
+// GetDropCount returns the number of flows dropped as the queue was full.
+func (f *Flows) GetDropCount() uint64 {
+	return f.dropped.Load()
+}
+
+// Count returns 0, the flows have no eBPF program.
+func (f *Flows) Count() int {
+	return 0
+}
+
+// Close unblocks the reader of the flows.
+func (f *Flows) Close() error {
+	f.once.Do(func() { close(f.closed) })
+
+	return nil
+}
+
+// ReadAsInterface returns the reader of the flow events, blocking until a flow ends or the flows are
+// closed. The events carry the file descriptor, the direction, the family, type and protocol of the
+// socket, its local and remote addresses, the start and end timestamps and the bytes sent and received.
+func (f *Flows) ReadAsInterface() ([]func() ([]byte, error), error) {
+	events := eventparser.GenerateTarianEvents()
+
+	read := func() ([]byte, error) {
+		select {
+		case flow := <-f.flows:
+			s := flow.Socket
+
+			// stamped with its emission, the end is in the payload, so that the event is not late
+			// for the reorder window
+			var now unix.Timespec
+			if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &now); err != nil {
+				return nil, err
+			}
+
+			flow.Meta.MetaData.Ts = uint64(now.Nano())
+
+			return events.Encode(eventparser.TDE_FLOW, flow.Meta, flow.Fd, flow.Direction, s.Family, s.SocketType, s.Protocol,
+				truncate(s.Local), truncate(s.Remote), flow.Start, flow.End, flow.BytesSent, flow.BytesReceived)
+		case <-f.closed:
+			return nil, nil
+		}
+	}
+
+	return []func() ([]byte, error){read}, nil
+}
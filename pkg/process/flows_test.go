@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// at returns the event at the kernel timestamp ts.
+func at(ts uint64, e map[string]any) map[string]any {
+	e["timestamp"] = ts
+	return e
+}
+
+// TestFdTable_RecordFlows tests the RecordFlows function
+func TestFdTable_RecordFlows(t *testing.T) {
+	socket := fdEvent("sys_socket", 10, 10, arg("family", "AF_INET"), arg("type", "SOCK_STREAM"), arg("protocol", "IPPROTO_TCP"), ret("3"))
+	tcp := Descriptor{Type: "socket", Family: "AF_INET", SocketType: "SOCK_STREAM", Protocol: "IPPROTO_TCP"}
+	listening := tcp
+	listening.Local = serverAddr
+	connected := tcp
+	connected.Remote = peerAddr
+	accepted := listening
+	accepted.Remote = peerAddr
+
+	tests := []struct {
+		name   string
+		events []map[string]any
+		want   []Flow
+	}{
+		{
+			name: "outbound connection",
+			events: []map[string]any{
+				socket,
+				at(100, fdEvent("sys_connect", 10, 10, arg("fd", "3"), arg("uservaddr", peerAddr), ret("0"))),
+				fdEvent("sys_write_entry", 10, 11, arg("fd", "3")),
+				fdEvent("sys_write_exit", 10, 11, ret("120")),
+				fdEvent("sys_read", 10, 10, arg("fd", "3"), ret("4096")),
+				fdEvent("sys_readv", 10, 10, arg("fd", "3"), ret("1000")),
+				fdEvent("sys_read", 10, 10, arg("fd", "3"), ret("-11")),
+				at(500, fdEvent("sys_close", 10, 10, arg("fd", "3"), ret("0"))),
+			},
+			want: []Flow{{Fd: 3, Direction: Outbound, Socket: connected, Start: 100, End: 500, BytesSent: 120, BytesReceived: 5096}},
+		},
+		{
+			name: "failed connect",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_connect", 10, 10, arg("fd", "3"), arg("uservaddr", peerAddr), ret("-111")),
+				fdEvent("sys_close", 10, 10, arg("fd", "3"), ret("0")),
+			},
+			want: nil,
+		},
+		{
+			name: "listening socket and inbound connection",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_bind", 10, 10, arg("fd", "3"), arg("umyaddr", serverAddr), ret("0")),
+				at(100, fdEvent("sys_listen", 10, 10, arg("fd", "3"), ret("0"))),
+				at(200, fdEvent("sys_accept", 10, 10, arg("fd", "3"), ret("5"), arg("peer", peerAddr))),
+				fdEvent("sys_writev", 10, 10, arg("fd", "5"), ret("64")),
+				at(300, fdEvent("sys_close", 10, 10, arg("fd", "5"), ret("0"))),
+				at(400, fdEvent("sched_process_exit", 10, 10, arg("group_dead", "1"))),
+			},
+			want: []Flow{
+				{Fd: 5, Direction: Inbound, Socket: accepted, Start: 200, End: 300, BytesSent: 64},
+				{Fd: 3, Direction: Listening, Socket: listening, Start: 100, End: 400},
+			},
+		},
+		{
+			name: "exited thread",
+			events: []map[string]any{
+				socket,
+				fdEvent("sys_connect", 10, 10, arg("fd", "3"), arg("uservaddr", peerAddr), ret("-115")),
+				fdEvent("sched_process_exit", 10, 11, arg("group_dead", "0")),
+			},
+			want: nil,
+		},
+		{
+			name: "reused file descriptor",
+			events: []map[string]any{
+				socket,
+				at(100, fdEvent("sys_connect", 10, 10, arg("fd", "3"), arg("uservaddr", peerAddr), ret("0"))),
+				at(200, fdEvent("sys_openat", 10, 10, filename("/etc/hosts"), ret("3"))),
+			},
+			want: []Flow{{Fd: 3, Direction: Outbound, Socket: connected, Start: 100, End: 200}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flows := NewFlows(0)
+			table := NewFdTable(0).RecordFlows(flows)
+
+			for _, e := range tt.events {
+				if err := table.Enrich(e); err != nil {
+					t.Fatalf("FdTable.Enrich() error = %v", err)
+				}
+			}
+
+			var got []Flow
+			for len(flows.flows) > 0 {
+				flow := <-flows.flows
+				if flow.Meta.MetaData.Task.HostPid != 10 {
+					t.Errorf("Flow.Meta hostPid = %v, want %v", flow.Meta.MetaData.Task.HostPid, 10)
+				}
+
+				flow.Meta = eventparser.TarianMetaData{}
+				got = append(got, flow)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FdTable.Enrich() flows = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFlows_ReadAsInterface tests the ReadAsInterface, GetDropCount and Close functions
+func TestFlows_ReadAsInterface(t *testing.T) {
+	flows := NewFlows(1)
+
+	var meta eventparser.TarianMetaData
+	meta.MetaData.Task.HostPid = 10
+	meta.MetaData.Task.ExecId = 7
+	meta.MetaData.Ts = 500
+
+	flows.send(Flow{
+		Fd:            3,
+		Direction:     Outbound,
+		Socket:        Descriptor{Type: "socket", Family: "AF_INET", SocketType: "SOCK_STREAM", Protocol: "IPPROTO_TCP", Remote: peerAddr},
+		Start:         100,
+		End:           500,
+		BytesSent:     120,
+		BytesReceived: 5096,
+		Meta:          meta,
+	})
+	flows.send(Flow{Fd: 4})
+
+	if flows.GetDropCount() != 1 {
+		t.Errorf("Flows.GetDropCount() = %v, want %v", flows.GetDropCount(), 1)
+	}
+
+	readers, err := flows.ReadAsInterface()
+	if err != nil || len(readers) != 1 {
+		t.Fatalf("Flows.ReadAsInterface() = %d readers, error %v", len(readers), err)
+	}
+
+	data, err := readers[0]()
+	if err != nil {
+		t.Fatalf("reader() error = %v", err)
+	}
+
+	eventparser.LoadTarianEvents()
+	record, err := eventparser.ParseByteArray(data)
+	if err != nil {
+		t.Fatalf("ParseByteArray() error = %v", err)
+	}
+
+	if record["eventId"] != "flow" || record["synthetic"] != true || record["execId"] != uint64(7) {
+		t.Errorf("ParseByteArray() = %v, want the synthetic flow event of the process", record)
+	}
+
+	if ts, _ := record["timestamp"].(uint64); ts <= 500 {
+		t.Errorf("ParseByteArray() timestamp = %v, want the time the flow was read", ts)
+	}
+
+	var got []string
+	for _, arg := range record["context"].([]eventparser.Arg) {
+		got = append(got, arg.Value)
+	}
+
+	want := []string{"3", Outbound, "AF_INET", "SOCK_STREAM", "IPPROTO_TCP", "", peerAddr, "100", "500", "120", "5096"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseByteArray() context = %q, want %q", got, want)
+	}
+
+	if err := flows.Close(); err != nil {
+		t.Errorf("Flows.Close() error = %v", err)
+	}
+
+	if data, err := readers[0](); data != nil || err != nil {
+		t.Errorf("reader() = %v, %v, want nil, nil", data, err)
+	}
+}
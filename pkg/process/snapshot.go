@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/intelops/tarian-detector/pkg/err"
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+	"golang.org/x/sys/unix"
+)
+
+var snapshotErr = err.New("process.snapshot")
+
+const (
+	// clockTicks is the number of clock ticks per second, USER_HZ, of the start times of /proc/<pid>/stat.
+	clockTicks = 100
+
+	// maxStringSize bounds the strings of the snapshot events, like MAX_STRING_SIZE bounds those
+	// written by the eBPF programs.
+	maxStringSize = 4096
+)
+
+// Proc is a process read from procfs.
+type Proc struct {
+	Meta    eventparser.TarianMetaData // Meta is the metadata of the process, as the eBPF programs fill it.
+	Exe     string                     // Exe is the path of the executable, empty for the kernel threads.
+	Cmdline string                     // Cmdline are the arguments of the process, separated by spaces.
+	Fds     map[int32]string           // Fds are the targets of the open file descriptors, e.g. /etc/hosts or socket:[1234].
+}
+
+// Snapshot is the processes running when it was read. It returns a synthetic process_snapshot
+// event for every process and is otherwise idle until closed. It is a detector.EventDetector.
+type Snapshot struct {
+	procs  []Proc        // procs are the processes, by host process ID.
+	closed chan struct{} // closed is closed by Close.
+	once   sync.Once     // once closes closed.
+}
+
+// ReadSnapshot reads the processes running from procDir, e.g. /host/proc, the procfs of the host PID
+// namespace. The processes exiting meanwhile are skipped. The start times of procfs are in clock
+// ticks, and count the time the system was suspended: the exec IDs are those the eBPF programs
+// compute from the start time rounded down to the tick, which only approximate the exec IDs of
+// the events of the processes.
+func ReadSnapshot(procDir string) (*Snapshot, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, snapshotErr.Throwf("%v", err)
+	}
+
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return nil, snapshotErr.Throwf("%v", err)
+	}
+
+	var now unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &now); err != nil {
+		return nil, snapshotErr.Throwf("%v", err)
+	}
+
+	var procs []Proc
+	for _, entry := range entries {
+		pid, err := strconv.ParseUint(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		p, err := readProc(filepath.Join(procDir, entry.Name()), procDir, uint32(pid))
+		if err != nil {
+			continue
+		}
+
+		p.Meta.MetaData.Ts = uint64(now.Nano())
+		p.Meta.SystemInfo.Sysname = uts.Sysname
+		p.Meta.SystemInfo.Nodename = uts.Nodename
+		p.Meta.SystemInfo.Release = uts.Release
+		p.Meta.SystemInfo.Version = uts.Version
+		p.Meta.SystemInfo.Machine = uts.Machine
+		p.Meta.SystemInfo.Domainname = uts.Domainname
+
+		procs = append(procs, p)
+	}
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].Meta.MetaData.Task.HostPid < procs[j].Meta.MetaData.Task.HostPid })
+
+	// the parent exec ID is the exec ID of the parent, computed from its start time like the eBPF
+	// programs do, so that the processes of the snapshot are linked to each other
+	parents := make(map[uint32]*eventparser.TarianMetaData, len(procs))
+	for i := range procs {
+		parents[procs[i].Meta.MetaData.Task.HostPid] = &procs[i].Meta
+	}
+
+	for i := range procs {
+		task := &procs[i].Meta.MetaData.Task
+		if parent, ok := parents[task.HostPpid]; ok {
+			task.Ppid = parent.MetaData.Task.Pid
+			task.ParentExecId = parent.MetaData.Task.ExecId
+		}
+	}
+
+	return &Snapshot{procs: procs, closed: make(chan struct{})}, nil
+}
+
+// readProc reads the process pid from its directory dir of procDir.
+func readProc(dir, procDir string, pid uint32) (Proc, error) {
+	p := Proc{Fds: make(map[int32]string)}
+	task := &p.Meta.MetaData.Task
+
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return p, err
+	}
+
+	// pid (comm) state ppid ... starttime, the comm may contain spaces and parentheses
+	open, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return p, fmt.Errorf("malformed stat of process %d", pid)
+	}
+
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 20 {
+		return p, fmt.Errorf("malformed stat of process %d", pid)
+	}
+
+	ppid, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return p, err
+	}
+
+	start, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return p, err
+	}
+
+	task.StartTime = start * (1e9 / clockTicks)
+	task.HostPid = pid
+	task.HostTgid = pid
+	task.HostPpid = uint32(ppid)
+	task.Pid = pid
+	task.Tgid = pid
+	task.ExecId = uint64(pid)<<32 | task.StartTime
+	copy(task.Comm[:len(task.Comm)-1], stat[open+1:end])
+
+	if status, err := os.ReadFile(filepath.Join(dir, "status")); err == nil {
+		readStatus(&p, string(status))
+	}
+
+	task.MountNsId = readNamespace(filepath.Join(dir, "ns", "mnt"))
+	task.PidNsId = readNamespace(filepath.Join(dir, "ns", "pid"))
+	task.CgroupId = readCgroupId(dir, procDir)
+
+	if cwd, err := os.Readlink(filepath.Join(dir, "cwd")); err == nil {
+		copy(task.Cwd[:len(task.Cwd)-1], cwd)
+	}
+
+	p.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		p.Cmdline = strings.ReplaceAll(strings.TrimRight(string(cmdline), "\x00"), "\x00", " ")
+	}
+
+	fds, _ := os.ReadDir(filepath.Join(dir, "fd"))
+	for _, fd := range fds {
+		n, err := strconv.ParseInt(fd.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		if target, err := os.Readlink(filepath.Join(dir, "fd", fd.Name())); err == nil {
+			p.Fds[int32(n)] = target
+		}
+	}
+
+	return p, nil
+}
+
+// readStatus reads the user, the group and the IDs in the PID namespace of the process from its status.
+func readStatus(p *Proc, status string) {
+	task := &p.Meta.MetaData.Task
+	for _, line := range strings.Split(status, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		values := strings.Fields(value)
+		if len(values) == 0 {
+			continue
+		}
+
+		// the real user and group IDs come first, the IDs in the PID namespace of the process last
+		first, _ := strconv.ParseUint(values[0], 10, 32)
+		last, _ := strconv.ParseUint(values[len(values)-1], 10, 32)
+
+		switch key {
+		case "Uid":
+			task.Uid = uint32(first)
+		case "Gid":
+			task.Gid = uint32(first)
+		case "NSpid":
+			task.Pid = uint32(last)
+		case "NStgid":
+			task.Tgid = uint32(last)
+		}
+	}
+}
+
+// readNamespace returns the inode number of the namespace link, e.g. mnt:[4026531841], 0 if it can not be read.
+func readNamespace(link string) uint64 {
+	target, err := os.Readlink(link)
+	if err != nil {
+		return 0
+	}
+
+	_, inode, _ := strings.Cut(target, "[")
+	id, _ := strconv.ParseUint(strings.TrimSuffix(inode, "]"), 10, 64)
+
+	return id
+}
+
+// readCgroupId returns the ID of the cgroup v2 of the process, the inode number of its directory
+// in the cgroup file system of the host, reached through the root of the init process of procDir.
+// It is 0 on the hosts using cgroup v1.
+func readCgroupId(dir, procDir string) uint64 {
+	cgroups, err := os.ReadFile(filepath.Join(dir, "cgroup"))
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(string(cgroups), "\n") {
+		path, ok := strings.CutPrefix(line, "0::")
+		if !ok {
+			continue
+		}
+
+		var st unix.Stat_t
+		if err := unix.Stat(filepath.Join(procDir, "1", "root", "sys", "fs", "cgroup", path), &st); err != nil {
+			return 0
+		}
+
+		return st.Ino
+	}
+
+	return 0
+}
+
+// Processes returns the processes of the snapshot, by host process ID.
+func (s *Snapshot) Processes() []Proc {
+	return s.procs
+}
+
+// Count returns 0, the snapshot has no eBPF program.
+func (s *Snapshot) Count() int {
+	return 0
+}
+
+// Close unblocks the reader of the snapshot.
+func (s *Snapshot) Close() error {
+	s.once.Do(func() { close(s.closed) })
+
+	return nil
+}
+
+// ReadAsInterface returns the reader of the process_snapshot events of the processes, then blocking
+// until the snapshot is closed. The events carry the metadata of the process, its executable, its
+// arguments and its open file descriptors, as fd=target separated by spaces.
+func (s *Snapshot) ReadAsInterface() ([]func() ([]byte, error), error) {
+	events := eventparser.GenerateTarianEvents()
+
+	next := 0
+	read := func() ([]byte, error) {
+		if next >= len(s.procs) {
+			<-s.closed
+			return nil, nil
+		}
+
+		p := s.procs[next]
+		next++
+
+		return events.Encode(eventparser.TDE_PROCESS_SNAPSHOT, p.Meta, truncate(p.Exe), truncate(p.Cmdline), truncate(formatFds(p.Fds)))
+	}
+
+	return []func() ([]byte, error){read}, nil
+}
+
+// formatFds returns the file descriptors as fd=target separated by spaces, by file descriptor.
+func formatFds(fds map[int32]string) string {
+	keys := make([]int32, 0, len(fds))
+	for fd := range fds {
+		keys = append(keys, fd)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var b strings.Builder
+	for i, fd := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		fmt.Fprintf(&b, "%d=%s", fd, fds[fd])
+	}
+
+	return b.String()
+}
+
+// truncate bounds s to maxStringSize with its NUL terminator.
+func truncate(s string) string {
+	if len(s) < maxStringSize {
+		return s
+	}
+
+	return s[:maxStringSize-1]
+}
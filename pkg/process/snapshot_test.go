@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// procFile is a file, or a symbolic link if link is set, of a fake procfs.
+type procFile struct {
+	path    string
+	content string
+	link    bool
+}
+
+// fakeProc creates the files of a fake procfs in a temporary directory and returns it.
+func fakeProc(t *testing.T, files ...procFile) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(dir, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		var err error
+		if f.link {
+			err = os.Symlink(f.content, path)
+		} else {
+			err = os.WriteFile(path, []byte(f.content), 0o644)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// stat returns the content of /proc/<pid>/stat of a process, started start clock ticks after the boot.
+func stat(pid, comm, ppid, start string) string {
+	return pid + " (" + comm + ") S " + ppid + " 1 1 0 -1 4194560 100 0 0 0 0 0 0 0 20 0 1 0 " + start + " 1000 100\n"
+}
+
+// TestReadSnapshot tests the ReadSnapshot function
+func TestReadSnapshot(t *testing.T) {
+	dir := fakeProc(t,
+		procFile{path: "1/stat", content: stat("1", "systemd", "0", "2")},
+		procFile{path: "1/status", content: "Name:\tsystemd\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\nNSpid:\t1\n"},
+		procFile{path: "1/cmdline", content: "/sbin/init\x00splash\x00"},
+		procFile{path: "1/exe", content: "/usr/lib/systemd/systemd", link: true},
+		procFile{path: "1/cwd", content: "/", link: true},
+		procFile{path: "1/ns/mnt", content: "mnt:[4026531841]", link: true},
+		procFile{path: "1/ns/pid", content: "pid:[4026531836]", link: true},
+		procFile{path: "42/stat", content: stat("42", "my (app)", "1", "1500")},
+		procFile{path: "42/status", content: "Name:\tapp\nUid:\t1000\t1000\t1000\t1000\nGid:\t100\t100\t100\t100\nNStgid:\t42\t7\nNSpid:\t42\t7\n"},
+		procFile{path: "42/cmdline", content: "app\x00--port\x008080\x00"},
+		procFile{path: "42/exe", content: "/app/bin/app", link: true},
+		procFile{path: "42/fd/0", content: "/dev/null", link: true},
+		procFile{path: "42/fd/3", content: "socket:[1234]", link: true},
+		procFile{path: "99/stat", content: "99 malformed"},
+		procFile{path: "self", content: "42", link: true},
+	)
+
+	s, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+
+	procs := s.Processes()
+	if len(procs) != 2 {
+		t.Fatalf("ReadSnapshot() = %d processes, want %d", len(procs), 2)
+	}
+
+	init, app := procs[0], procs[1]
+	if init.Exe != "/usr/lib/systemd/systemd" || init.Cmdline != "/sbin/init splash" || len(init.Fds) != 0 {
+		t.Errorf("ReadSnapshot() init = %q %q %v", init.Exe, init.Cmdline, init.Fds)
+	}
+
+	task := init.Meta.MetaData.Task
+	if task.StartTime != uint64(20*time.Millisecond) || task.ExecId != 1<<32|uint64(20*time.Millisecond) || task.MountNsId != 4026531841 || task.PidNsId != 4026531836 {
+		t.Errorf("ReadSnapshot() init task = %+v", task)
+	}
+
+	if app.Exe != "/app/bin/app" || app.Cmdline != "app --port 8080" || !reflect.DeepEqual(app.Fds, map[int32]string{0: "/dev/null", 3: "socket:[1234]"}) {
+		t.Errorf("ReadSnapshot() app = %q %q %v", app.Exe, app.Cmdline, app.Fds)
+	}
+
+	task = app.Meta.MetaData.Task
+	if task.HostPid != 42 || task.Pid != 7 || task.Tgid != 7 || task.HostPpid != 1 || task.Ppid != 1 || task.Uid != 1000 || task.Gid != 100 {
+		t.Errorf("ReadSnapshot() app task = %+v", task)
+	}
+
+	if task.ParentExecId != init.Meta.MetaData.Task.ExecId {
+		t.Errorf("ReadSnapshot() app parentExecId = %v, want %v", task.ParentExecId, init.Meta.MetaData.Task.ExecId)
+	}
+
+	if comm := string(task.Comm[:8]); comm != "my (app)" {
+		t.Errorf("ReadSnapshot() app comm = %q, want %q", comm, "my (app)")
+	}
+
+	if _, err := ReadSnapshot(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("ReadSnapshot() error = %v, wantErr %v", err, true)
+	}
+}
+
+// TestSnapshot_ReadAsInterface tests the ReadAsInterface and Close functions
+func TestSnapshot_ReadAsInterface(t *testing.T) {
+	var proc Proc
+	proc.Meta.MetaData.Task.HostPid = 42
+	proc.Meta.MetaData.Task.ExecId = 7
+	proc.Exe = "/app/bin/app"
+	proc.Cmdline = "app --port 8080"
+	proc.Fds = map[int32]string{3: "socket:[1234]", 0: "/dev/null"}
+
+	s := &Snapshot{procs: []Proc{proc}, closed: make(chan struct{})}
+	readers, err := s.ReadAsInterface()
+	if err != nil || len(readers) != 1 {
+		t.Fatalf("Snapshot.ReadAsInterface() = %d readers, error %v", len(readers), err)
+	}
+
+	data, err := readers[0]()
+	if err != nil {
+		t.Fatalf("reader() error = %v", err)
+	}
+
+	eventparser.LoadTarianEvents()
+	record, err := eventparser.ParseByteArray(data)
+	if err != nil {
+		t.Fatalf("ParseByteArray() error = %v", err)
+	}
+
+	if record["eventId"] != "process_snapshot" || record["synthetic"] != true || record["execId"] != uint64(7) {
+		t.Errorf("ParseByteArray() = %v, want the synthetic process_snapshot event of the process", record)
+	}
+
+	var got []string
+	for _, arg := range record["context"].([]eventparser.Arg) {
+		got = append(got, arg.Value)
+	}
+
+	if want := []string{"/app/bin/app", "app --port 8080", "0=/dev/null 3=socket:[1234]"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseByteArray() context = %q, want %q", got, want)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		if data, err := readers[0](); data != nil || err != nil {
+			t.Errorf("reader() = %v, %v, want nil, nil", data, err)
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("reader() returned before Close")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Snapshot.Close() error = %v", err)
+	}
+
+	<-done
+}
@@ -0,0 +1,407 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/intelops/tarian-detector/pkg/err"
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+var treeErr = err.New("process.tree")
+
+const (
+	// DefaultSize is the number of processes a Tree holds by default.
+	DefaultSize = 32768
+
+	// maxAncestors is the number of ancestors attached to an event, so that a cycle of exec IDs
+	// can not loop forever.
+	maxAncestors = 64
+)
+
+// Process is a process of the tree.
+type Process struct {
+	ExecId       uint64 // ExecId is the ID of the process, from its host process ID and start time.
+	ParentExecId uint64 // ParentExecId is the ID of the parent of the process.
+	HostPid      uint32 // HostPid is the ID of the process on the host.
+	Pid          uint32 // Pid is the ID of the process in its PID namespace.
+	Uid          uint32 // Uid is the ID of the user running the process.
+	Binary       string // Binary is the file executed by the process, its name until an execve is seen.
+	Args         string // Args are the arguments of the process, separated by spaces.
+
+	exec     *Process // exec is the execve in progress, applied once it succeeded.
+	children int      // children is the number of children of the process in the tree.
+	counted  bool     // counted is set if the process is counted in the children of its parent.
+	exited   bool     // exited is set once the process exited, kept until its last child is removed.
+}
+
+// Ancestor is an ancestor of the process of an event, attached to the event by Tree.Enrich.
+type Ancestor struct {
+	Binary string `json:"binary"` // Binary is the file executed by the ancestor.
+	Args   string `json:"args"`   // Args are the arguments of the ancestor, separated by spaces.
+	Pid    uint32 `json:"pid"`    // Pid is the ID of the ancestor on the host.
+	Uid    uint32 `json:"uid"`    // Uid is the ID of the user running the ancestor.
+}
+
+// Tree is a table of the processes seen in the events, indexed by their exec ID. An exited process is
+// kept until its last child is removed, so that orphaned children keep their ancestry. It holds a
+// bounded number of processes, the least recently seen are evicted first. It is safe for concurrent use.
+type Tree struct {
+	size      int                      // size is the maximum number of processes.
+	processes map[uint64]*list.Element // processes are the elements of lru, by exec ID.
+	seeds     map[uint32]*list.Element // seeds are the elements of lru of the processes of a snapshot, by host process ID.
+	lru       *list.List               // lru holds the processes, from the most to the least recently seen.
+	mu        sync.Mutex               // mu guards the processes.
+}
+
+// NewTree creates a new Tree holding up to size processes, DefaultSize if size is not positive.
+func NewTree(size int) *Tree {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	return &Tree{
+		size:      size,
+		processes: make(map[uint64]*list.Element),
+		seeds:     make(map[uint32]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// Enrich updates the tree from the event and attaches the ancestry of its process, from its parent
+// to the oldest ancestor known, in the ancestry field. A process seen for the first time inherits
+// the binary and the arguments of its parent, as a forked child, until its execve or execveat
+// succeeds. The sched_process_fork, sched_process_exec and sched_process_exit events add the
+// children, update the binary and remove the exited processes once they have no children left, so
+// that the tree follows the processes running and their ancestors. The synthetic events other than
+// process_snapshot, e.g. the flows, get no ancestry.
+func (t *Tree) Enrich(event map[string]any) error {
+	execId, ok := event["execId"].(uint64)
+	if !ok {
+		return treeErr.Throw("missing execId")
+	}
+
+	// the synthetic events other than those of the snapshot are of processes that may have exited
+	name, _ := event["eventId"].(string)
+	if synthetic, _ := event["synthetic"].(bool); synthetic && name != "process_snapshot" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.touch(execId, event)
+	t.exec(p, event)
+
+	event["ancestry"] = t.ancestry(p)
+
+	t.sched(p, event)
+
+	return nil
+}
+
+// touch returns the process of the event, added to the tree if it is not known, and marks it as the
+// most recently seen. The caller holds t.mu.
+func (t *Tree) touch(execId uint64, event map[string]any) *Process {
+	if e, ok := t.processes[execId]; ok {
+		t.lru.MoveToFront(e)
+
+		// a child added by its fork event, its ID in its PID namespace is only known from its events
+		p := e.Value.(*Process)
+		if p.Pid == 0 {
+			p.Pid, _ = event["processId"].(uint32)
+		}
+
+		return p
+	}
+
+	p := &Process{ExecId: execId}
+	p.ParentExecId, _ = event["parentExecId"].(uint64)
+	p.HostPid, _ = event["hostProcessId"].(uint32)
+	p.Pid, _ = event["processId"].(uint32)
+	p.Uid, _ = event["userId"].(uint32)
+	p.Binary, _ = event["processName"].(string)
+
+	parent, ok := t.processes[p.ParentExecId]
+	if !ok {
+		// forked by a process running before the detector, known by its host process ID only
+		hostPpid, _ := event["hostParentProcessId"].(uint32)
+		if parent, ok = t.seeds[hostPpid]; ok {
+			p.ParentExecId = parent.Value.(*Process).ExecId
+		}
+	}
+
+	if ok {
+		p.Binary = parent.Value.(*Process).Binary
+		p.Args = parent.Value.(*Process).Args
+	}
+
+	// a process running before the detector, or one of its threads
+	if seed, ok := t.seeds[p.HostPid]; ok {
+		p.Binary = seed.Value.(*Process).Binary
+		p.Args = seed.Value.(*Process).Args
+	}
+
+	t.add(p)
+
+	return p
+}
+
+// add adds the process to the tree, evicting the least recently seen process if the tree is full.
+// The caller holds t.mu.
+func (t *Tree) add(p *Process) {
+	if t.lru.Len() >= t.size {
+		t.remove(t.lru.Back())
+	}
+
+	t.processes[p.ExecId] = t.lru.PushFront(p)
+	if parent, ok := t.processes[p.ParentExecId]; ok && p.ParentExecId != p.ExecId {
+		parent.Value.(*Process).children++
+		p.counted = true
+	}
+}
+
+// remove removes the process of the element e from the tree, and its exited ancestors left without
+// children. The caller holds t.mu.
+func (t *Tree) remove(e *list.Element) {
+	for e != nil {
+		p := e.Value.(*Process)
+
+		t.lru.Remove(e)
+		delete(t.processes, p.ExecId)
+		if t.seeds[p.HostPid] == e {
+			delete(t.seeds, p.HostPid)
+		}
+
+		e = nil
+		if parent, ok := t.processes[p.ParentExecId]; ok && p.counted {
+			pp := parent.Value.(*Process)
+			if pp.children > 0 {
+				pp.children--
+			}
+
+			if pp.exited && pp.children == 0 {
+				e = parent
+			}
+		}
+	}
+}
+
+// exit marks the process exited and removes it from the tree, unless it has children in the tree. The
+// caller holds t.mu.
+func (t *Tree) exit(p *Process) {
+	p.exited = true
+	if e, ok := t.processes[p.ExecId]; ok && e.Value == p && p.children == 0 {
+		t.remove(e)
+	}
+}
+
+// Seed adds the processes of a snapshot, running before the events are read. Their exec IDs only
+// approximate those of their events, so an unknown process, or the child of an unknown process,
+// is matched to the processes of the snapshot by its host process ID or that of its parent.
+func (t *Tree) Seed(procs []Proc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, proc := range procs {
+		task := proc.Meta.MetaData.Task
+		if _, ok := t.processes[task.ExecId]; ok {
+			continue
+		}
+
+		p := &Process{
+			ExecId:       task.ExecId,
+			ParentExecId: task.ParentExecId,
+			HostPid:      task.HostPid,
+			Pid:          task.Pid,
+			Uid:          task.Uid,
+			Binary:       proc.Exe,
+			Args:         proc.Cmdline,
+		}
+
+		// the kernel threads have no executable
+		if len(p.Binary) == 0 {
+			p.Binary = strings.TrimRight(string(task.Comm[:]), "\x00")
+		}
+
+		t.add(p)
+		t.seeds[p.HostPid] = t.processes[p.ExecId]
+	}
+}
+
+// exec updates the binary and the arguments of the process from its execve and execveat events. The
+// entry events are applied once their exit event tells the syscall succeeded, the events carrying
+// both the arguments and the return value, of the fexit backend or merged by the detector, at once.
+// The caller holds t.mu.
+func (t *Tree) exec(p *Process, event map[string]any) {
+	name, _ := event["eventId"].(string)
+	syscall, _ := strings.CutSuffix(name, "_entry")
+	syscall, _ = strings.CutSuffix(syscall, "_exit")
+	if syscall != "sys_execve" && syscall != "sys_execveat" {
+		return
+	}
+
+	args, _ := event["context"].([]eventparser.Arg)
+
+	var (
+		filename, argv, ret string
+		hasFilename, hasRet bool
+	)
+
+	for _, arg := range args {
+		switch arg.Name {
+		case "filename":
+			filename, hasFilename = arg.Value, true
+		case "argv":
+			argv = strings.ReplaceAll(arg.Value, "\x00", "")
+		case "return":
+			ret, hasRet = arg.Value, true
+		}
+	}
+
+	if hasFilename {
+		p.exec = &Process{Binary: filename, Args: argv}
+	}
+
+	if !hasRet {
+		return
+	}
+
+	if ret == "0" && p.exec != nil {
+		p.Binary, p.Args = p.exec.Binary, p.exec.Args
+		p.Uid, _ = event["userId"].(uint32)
+	}
+
+	p.exec = nil
+}
+
+// sched updates the tree from the lifecycle events of the process. A forked child is added with the
+// binary, the arguments and the user of its parent, a new thread with the parent of its process. An
+// executed program replaces the binary, and the arguments with those of the execve in progress, if
+// any. An exited process is removed, with its snapshot once its last thread exited, as soon as it has
+// no children in the tree. The synthetic process_snapshot events seed the tree like Seed. The caller
+// holds t.mu.
+func (t *Tree) sched(p *Process, event map[string]any) {
+	name, _ := event["eventId"].(string)
+	args, _ := event["context"].([]eventparser.Arg)
+
+	switch name {
+	case "sched_process_fork":
+		childExecId, err := strconv.ParseUint(argValue(args, "child_exec_id"), 10, 64)
+		if err != nil {
+			return
+		}
+
+		if _, ok := t.processes[childExecId]; ok {
+			return
+		}
+
+		child := &Process{ExecId: childExecId, ParentExecId: p.ExecId, Uid: p.Uid, Binary: p.Binary, Args: p.Args}
+		if childPid, err := strconv.ParseUint(argValue(args, "child_pid"), 10, 32); err == nil {
+			child.HostPid = uint32(childPid)
+		}
+
+		// the parent of a thread is the parent of its process
+		if child.HostPid == p.HostPid {
+			child.ParentExecId = p.ParentExecId
+		}
+
+		t.add(child)
+	case "sched_process_exec":
+		filename := argValue(args, "filename")
+		if p.Binary == filename {
+			return
+		}
+
+		p.Binary, p.Args = filename, ""
+		if p.exec != nil && p.exec.Binary == filename {
+			p.Args = p.exec.Args
+		}
+
+		p.Uid, _ = event["userId"].(uint32)
+	case "sched_process_exit":
+		t.exit(p)
+
+		// the process ran before the detector
+		if seed, ok := t.seeds[p.HostPid]; ok && argValue(args, "group_dead") == "1" {
+			t.exit(seed.Value.(*Process))
+		}
+	case "process_snapshot":
+		if exe := argValue(args, "exe"); len(exe) != 0 {
+			p.Binary, p.Args = exe, argValue(args, "cmdline")
+		}
+
+		if _, ok := t.seeds[p.HostPid]; !ok {
+			t.seeds[p.HostPid] = t.processes[p.ExecId]
+		}
+	}
+}
+
+// argValue returns the value of the argument named name, empty if there is none.
+func argValue(args []eventparser.Arg, name string) string {
+	for _, arg := range args {
+		if arg.Name == name {
+			return arg.Value
+		}
+	}
+
+	return ""
+}
+
+// ancestry returns the ancestors of the process known to the tree, from its parent. The caller holds t.mu.
+func (t *Tree) ancestry(p *Process) []Ancestor {
+	ancestors := []Ancestor{}
+	for len(ancestors) < maxAncestors {
+		e, ok := t.processes[p.ParentExecId]
+		if !ok {
+			break
+		}
+
+		p = e.Value.(*Process)
+		ancestors = append(ancestors, Ancestor{Binary: p.Binary, Args: p.Args, Pid: p.HostPid, Uid: p.Uid})
+	}
+
+	return ancestors
+}
+
+// Get returns a copy of the process with the exec ID, false if it is not known.
+func (t *Tree) Get(execId uint64) (Process, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.processes[execId]
+	if !ok {
+		return Process{}, false
+	}
+
+	p := *e.Value.(*Process)
+	p.exec, p.children, p.counted, p.exited = nil, 0, false, false
+
+	return p, true
+}
+
+// Ancestry returns the ancestors of the process with the exec ID, from its parent.
+func (t *Tree) Ancestry(execId uint64) []Ancestor {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.processes[execId]
+	if !ok {
+		return []Ancestor{}
+	}
+
+	return t.ancestry(e.Value.(*Process))
+}
+
+// Len returns the number of processes in the tree.
+func (t *Tree) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.lru.Len()
+}
@@ -0,0 +1,403 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package process
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// event returns an event of the process with the exec ID, forked by the parent exec ID.
+func event(name string, execId, parentExecId uint64, pid uint32, args ...eventparser.Arg) map[string]any {
+	return map[string]any{
+		"eventId":       name,
+		"execId":        execId,
+		"parentExecId":  parentExecId,
+		"hostProcessId": pid,
+		"processId":     pid,
+		"userId":        uint32(1000),
+		"processName":   "comm",
+		"context":       args,
+	}
+}
+
+func filename(f string) eventparser.Arg { return eventparser.Arg{Name: "filename", Value: f} }
+func argv(a string) eventparser.Arg     { return eventparser.Arg{Name: "argv", Value: a} }
+func ret(r string) eventparser.Arg      { return eventparser.Arg{Name: "return", Value: r} }
+
+// fork returns the arguments of the sched_process_fork event of the child.
+func fork(childPid, childExecId string) []eventparser.Arg {
+	return []eventparser.Arg{{Name: "child_pid", Value: childPid}, {Name: "child_exec_id", Value: childExecId}}
+}
+
+// TestTree_Enrich tests the Enrich function
+func TestTree_Enrich(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		events []map[string]any
+		want   []Ancestor // ancestry of the last event
+	}{
+		{
+			name:   "unknown parent",
+			events: []map[string]any{event("sys_read_entry", 2, 1, 20)},
+			want:   []Ancestor{},
+		},
+		{
+			name: "shell spawned by a web server",
+			events: []map[string]any{
+				event("sys_execve_entry", 1, 0, 10, filename("/usr/sbin/nginx"), argv("nginx\x00 -g\x00 daemon off;\x00")),
+				event("sys_execve_exit", 1, 0, 10, ret("0")),
+				event("sys_clone_exit", 2, 1, 20, ret("0")),
+				event("sys_execve", 2, 1, 20, filename("/bin/sh"), argv("sh -c id"), ret("0")),
+				event("sys_clone_exit", 3, 2, 30, ret("0")),
+				event("sys_openat_entry", 3, 2, 30),
+			},
+			want: []Ancestor{
+				{Binary: "/bin/sh", Args: "sh -c id", Pid: 20, Uid: 1000},
+				{Binary: "/usr/sbin/nginx", Args: "nginx -g daemon off;", Pid: 10, Uid: 1000},
+			},
+		},
+		{
+			name: "forked child inherits the binary",
+			events: []map[string]any{
+				event("sys_execve", 1, 0, 10, filename("/usr/sbin/nginx"), argv("nginx"), ret("0")),
+				event("sys_clone_exit", 2, 1, 20, ret("0")),
+				event("sys_clone_exit", 3, 2, 30, ret("0")),
+			},
+			want: []Ancestor{
+				{Binary: "/usr/sbin/nginx", Args: "nginx", Pid: 20, Uid: 1000},
+				{Binary: "/usr/sbin/nginx", Args: "nginx", Pid: 10, Uid: 1000},
+			},
+		},
+		{
+			name: "failed execve",
+			events: []map[string]any{
+				event("sys_execve", 1, 0, 10, filename("/bin/bash"), argv("bash"), ret("0")),
+				event("sys_execve_entry", 1, 0, 10, filename("/missing"), argv("missing")),
+				event("sys_execve_exit", 1, 0, 10, ret("-2")),
+				event("sys_read_entry", 2, 1, 20),
+			},
+			want: []Ancestor{{Binary: "/bin/bash", Args: "bash", Pid: 10, Uid: 1000}},
+		},
+		{
+			name: "sched lifecycle",
+			events: []map[string]any{
+				event("sys_execve", 1, 0, 10, filename("/usr/sbin/nginx"), argv("nginx"), ret("0")),
+				event("sched_process_fork", 1, 0, 10, fork("20", "2")...),
+				event("sched_process_exec", 2, 1, 20, filename("/bin/sh")),
+				event("sched_process_fork", 2, 1, 20, fork("30", "3")...),
+				event("sys_read_entry", 3, 2, 30),
+			},
+			want: []Ancestor{
+				{Binary: "/bin/sh", Pid: 20, Uid: 1000},
+				{Binary: "/usr/sbin/nginx", Args: "nginx", Pid: 10, Uid: 1000},
+			},
+		},
+		{
+			name: "thread of the process",
+			events: []map[string]any{
+				event("sys_execve", 1, 0, 10, filename("/usr/sbin/nginx"), argv("nginx"), ret("0")),
+				event("sched_process_fork", 2, 1, 20, fork("20", "3")...),
+				event("sys_read_entry", 3, 1, 20),
+			},
+			want: []Ancestor{{Binary: "/usr/sbin/nginx", Args: "nginx", Pid: 10, Uid: 1000}},
+		},
+		{
+			name: "exec of the execve in progress",
+			events: []map[string]any{
+				event("sys_execve_entry", 1, 0, 10, filename("/bin/sh"), argv("sh -c id")),
+				event("sched_process_exec", 1, 0, 10, filename("/bin/sh")),
+				event("sys_read_entry", 2, 1, 20),
+			},
+			want: []Ancestor{{Binary: "/bin/sh", Args: "sh -c id", Pid: 10, Uid: 1000}},
+		},
+		{
+			name: "exited parent",
+			events: []map[string]any{
+				event("sys_execve", 1, 0, 10, filename("/bin/bash"), argv("bash"), ret("0")),
+				event("sched_process_fork", 1, 0, 10, fork("20", "2")...),
+				event("sched_process_exit", 1, 0, 10),
+				event("sys_read_entry", 2, 1, 20),
+			},
+			want: []Ancestor{{Binary: "/bin/bash", Args: "bash", Pid: 10, Uid: 1000}},
+		},
+		{
+			name: "exited parent of an exited child",
+			events: []map[string]any{
+				event("sys_execve", 1, 0, 10, filename("/bin/bash"), argv("bash"), ret("0")),
+				event("sched_process_fork", 1, 0, 10, fork("20", "2")...),
+				event("sched_process_fork", 2, 1, 20, fork("30", "3")...),
+				event("sched_process_exit", 1, 0, 10),
+				event("sched_process_exit", 2, 1, 20),
+				event("sys_read_entry", 3, 2, 30),
+			},
+			want: []Ancestor{
+				{Binary: "/bin/bash", Args: "bash", Pid: 20, Uid: 1000},
+				{Binary: "/bin/bash", Args: "bash", Pid: 10, Uid: 1000},
+			},
+		},
+		{
+			name: "evicted parent",
+			size: 1,
+			events: []map[string]any{
+				event("sys_execve", 1, 0, 10, filename("/bin/bash"), argv("bash"), ret("0")),
+				event("sys_read_entry", 2, 1, 20),
+			},
+			want: []Ancestor{},
+		},
+		{
+			name: "cycle of exec IDs",
+			events: []map[string]any{
+				event("sys_read_entry", 1, 2, 10),
+				event("sys_read_entry", 2, 1, 20),
+			},
+			want: func() []Ancestor {
+				ancestors := make([]Ancestor, maxAncestors)
+				for i := range ancestors {
+					ancestors[i] = Ancestor{Binary: "comm", Pid: 10, Uid: 1000}
+					if i%2 == 1 {
+						ancestors[i].Pid = 20
+					}
+				}
+
+				return ancestors
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree(tt.size)
+
+			var last map[string]any
+			for _, e := range tt.events {
+				if err := tree.Enrich(e); err != nil {
+					t.Fatalf("Tree.Enrich() error = %v", err)
+				}
+
+				last = e
+			}
+
+			if got := last["ancestry"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tree.Enrich() ancestry = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTree_Get tests the Get, Ancestry and Len functions
+func TestTree_Get(t *testing.T) {
+	tree := NewTree(0)
+	for _, e := range []map[string]any{
+		event("sys_execve", 1, 0, 10, filename("/bin/bash"), argv("bash"), ret("0")),
+		event("sys_clone_exit", 2, 1, 20, ret("0")),
+	} {
+		if err := tree.Enrich(e); err != nil {
+			t.Fatalf("Tree.Enrich() error = %v", err)
+		}
+	}
+
+	if err := tree.Enrich(map[string]any{}); err == nil {
+		t.Errorf("Tree.Enrich() error = %v, wantErr %v", err, true)
+	}
+
+	want := Process{ExecId: 2, ParentExecId: 1, HostPid: 20, Pid: 20, Uid: 1000, Binary: "/bin/bash", Args: "bash"}
+	if got, ok := tree.Get(2); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("Tree.Get() = %+v, %v, want %+v, %v", got, ok, want, true)
+	}
+
+	if _, ok := tree.Get(3); ok {
+		t.Errorf("Tree.Get() ok = %v, want %v", ok, false)
+	}
+
+	if got := tree.Ancestry(2); len(got) != 1 || got[0].Pid != 10 {
+		t.Errorf("Tree.Ancestry() = %+v, want the process 10", got)
+	}
+
+	if tree.Len() != 2 {
+		t.Errorf("Tree.Len() = %v, want %v", tree.Len(), 2)
+	}
+
+	if err := tree.Enrich(event("sched_process_exit", 2, 1, 20)); err != nil {
+		t.Fatalf("Tree.Enrich() error = %v", err)
+	}
+
+	if _, ok := tree.Get(2); ok || tree.Len() != 1 {
+		t.Errorf("Tree.Get() ok = %v, Tree.Len() = %v, want %v, %v", ok, tree.Len(), false, 1)
+	}
+}
+
+// TestTree_Len tests the Len function
+func TestTree_Len(t *testing.T) {
+	synthetic := func(e map[string]any) map[string]any {
+		e["synthetic"] = true
+		return e
+	}
+
+	tests := []struct {
+		name   string
+		events []map[string]any
+		want   int
+	}{
+		{
+			name: "exited parent of a running child",
+			events: []map[string]any{
+				event("sched_process_fork", 1, 0, 10, fork("20", "2")...),
+				event("sched_process_exit", 1, 0, 10),
+			},
+			want: 2,
+		},
+		{
+			name: "exited parent removed with its last child",
+			events: []map[string]any{
+				event("sched_process_fork", 1, 0, 10, fork("20", "2")...),
+				event("sched_process_fork", 1, 0, 10, fork("30", "3")...),
+				event("sched_process_exit", 1, 0, 10),
+				event("sched_process_exit", 2, 1, 20),
+				event("sched_process_exit", 3, 1, 30),
+			},
+			want: 0,
+		},
+		{
+			name: "exited ancestors removed with their last descendant",
+			events: []map[string]any{
+				event("sched_process_fork", 1, 0, 10, fork("20", "2")...),
+				event("sched_process_fork", 2, 1, 20, fork("30", "3")...),
+				event("sched_process_exit", 1, 0, 10),
+				event("sched_process_exit", 2, 1, 20),
+				event("sched_process_exit", 3, 2, 30),
+			},
+			want: 0,
+		},
+		{
+			name: "flow of an exited process",
+			events: []map[string]any{
+				event("sched_process_exit", 1, 0, 10),
+				synthetic(event("flow", 1, 0, 10)),
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree(0)
+			for _, e := range tt.events {
+				if err := tree.Enrich(e); err != nil {
+					t.Fatalf("Tree.Enrich() error = %v", err)
+				}
+			}
+
+			if got := tree.Len(); got != tt.want {
+				t.Errorf("Tree.Len() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// seed returns a process of a snapshot.
+func seed(execId, parentExecId uint64, pid uint32, exe, cmdline string) Proc {
+	var p Proc
+	p.Meta.MetaData.Task.ExecId = execId
+	p.Meta.MetaData.Task.ParentExecId = parentExecId
+	p.Meta.MetaData.Task.HostPid = pid
+	p.Meta.MetaData.Task.Pid = pid
+	copy(p.Meta.MetaData.Task.Comm[:], "kthreadd")
+	p.Exe, p.Cmdline = exe, cmdline
+
+	return p
+}
+
+// TestTree_Seed tests the Seed function
+func TestTree_Seed(t *testing.T) {
+	// the exec IDs of the events differ from those of the snapshot, read from procfs
+	withParent := func(e map[string]any, ppid uint32) map[string]any {
+		e["hostParentProcessId"] = ppid
+		return e
+	}
+
+	tests := []struct {
+		name   string
+		events []map[string]any
+		want   []Ancestor // ancestry of the last event
+	}{
+		{
+			name:   "process running before the detector",
+			events: []map[string]any{withParent(event("sys_read_entry", 301, 201, 30), 20)},
+			want: []Ancestor{
+				{Binary: "/bin/sh", Args: "sh -c worker", Pid: 20},
+				{Binary: "/usr/sbin/nginx", Args: "nginx", Pid: 10},
+			},
+		},
+		{
+			name: "child of a process running before the detector",
+			events: []map[string]any{
+				withParent(event("sys_clone_exit", 401, 301, 40, ret("0")), 30),
+			},
+			want: []Ancestor{
+				{Binary: "/app/worker", Args: "worker", Pid: 30},
+				{Binary: "/bin/sh", Args: "sh -c worker", Pid: 20},
+				{Binary: "/usr/sbin/nginx", Args: "nginx", Pid: 10},
+			},
+		},
+		{
+			name: "exited process running before the detector",
+			events: []map[string]any{
+				withParent(event("sched_process_exit", 301, 201, 30, eventparser.Arg{Name: "group_dead", Value: "1"}), 20),
+				withParent(event("sys_clone_exit", 401, 301, 40, ret("0")), 30),
+			},
+			want: []Ancestor{},
+		},
+		{
+			name: "synthetic event",
+			events: []map[string]any{
+				event("process_snapshot", 3, 2, 30, eventparser.Arg{Name: "exe", Value: "/app/worker"}),
+			},
+			want: []Ancestor{
+				{Binary: "/bin/sh", Args: "sh -c worker", Pid: 20},
+				{Binary: "/usr/sbin/nginx", Args: "nginx", Pid: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree(0)
+			tree.Seed([]Proc{
+				seed(1, 0, 10, "/usr/sbin/nginx", "nginx"),
+				seed(2, 1, 20, "/bin/sh", "sh -c worker"),
+				seed(3, 2, 30, "/app/worker", "worker"),
+				seed(4, 0, 2, "", ""),
+			})
+
+			var last map[string]any
+			for _, e := range tt.events {
+				if err := tree.Enrich(e); err != nil {
+					t.Fatalf("Tree.Enrich() error = %v", err)
+				}
+
+				last = e
+			}
+
+			// the uid of the events is not the one of the seeds
+			got := last["ancestry"].([]Ancestor)
+			for i := range got {
+				got[i].Uid = 0
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tree.Enrich() ancestry = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	tree := NewTree(0)
+	tree.Seed([]Proc{seed(4, 0, 2, "", "")})
+	if p, ok := tree.Get(4); !ok || p.Binary != "kthreadd" {
+		t.Errorf("Tree.Get() = %+v, %v, want the kernel thread kthreadd", p, ok)
+	}
+}
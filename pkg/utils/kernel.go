@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/features"
+	"github.com/intelops/tarian-detector/pkg/err"
+	"golang.org/x/sys/unix"
+)
+
+var kernelErr = err.New("utils.kernel")
+
+// kernelBtfPath is the path where the kernel exposes its own BTF information.
+const kernelBtfPath string = "/sys/kernel/btf/vmlinux"
+
+// kernelLsmPath is the path listing the active Linux security modules.
+const kernelLsmPath string = "/sys/kernel/security/lsm"
+
+// KernelFeatures describes the version and the eBPF capabilities of the running kernel.
+type KernelFeatures struct {
+	Release string // Release is the kernel release string as reported by uname, e.g. 5.15.0-91-generic.
+	Version int    // Version is the kernel version encoded with KernelVersion.
+
+	BTF        bool // BTF reports whether the kernel exposes its BTF at /sys/kernel/btf/vmlinux.
+	RingBuf    bool // RingBuf reports support for BPF_MAP_TYPE_RINGBUF.
+	Tracing    bool // Tracing reports support for BPF_PROG_TYPE_TRACING (fentry/fexit/fmod_ret).
+	LSM        bool // LSM reports support for BPF_PROG_TYPE_LSM.
+	LSMEnabled bool // LSMEnabled reports whether the bpf LSM is active, e.g. booted with lsm=bpf.
+}
+
+// DetectKernelFeatures determines the version of the running kernel and probes it for
+// the eBPF map and program types used by the detector.
+func DetectKernelFeatures() (*KernelFeatures, error) {
+	release, err := kernelRelease()
+	if err != nil {
+		return nil, kernelErr.Throwf("%v", err)
+	}
+
+	version, err := ParseKernelRelease(release)
+	if err != nil {
+		return nil, err
+	}
+
+	kf := &KernelFeatures{
+		Release: release,
+		Version: version,
+	}
+
+	_, err = os.Stat(kernelBtfPath)
+	kf.BTF = err == nil
+
+	kf.RingBuf = features.HaveMapType(ebpf.RingBuf) == nil
+	kf.Tracing = features.HaveProgramType(ebpf.Tracing) == nil
+	kf.LSM = features.HaveProgramType(ebpf.LSM) == nil
+	kf.LSMEnabled = lsmEnabled(kernelLsmPath, "bpf")
+
+	return kf, nil
+}
+
+// CurrentKernelVersion retrieves the version of the running kernel from uname.
+func CurrentKernelVersion() (int, error) {
+	release, err := kernelRelease()
+	if err != nil {
+		return 0, kernelErr.Throwf("%v", err)
+	}
+
+	return ParseKernelRelease(release)
+}
+
+// ParseKernelRelease converts a kernel release string such as 5.15.0-91-generic
+// into a version number encoded with KernelVersion. Missing minor or patch numbers
+// are treated as zero.
+func ParseKernelRelease(release string) (int, error) {
+	// drop the local version suffix, e.g. -91-generic or +
+	end := strings.IndexFunc(release, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	})
+	if end != -1 {
+		release = release[:end]
+	}
+
+	parts := strings.Split(release, ".")
+	if len(parts) == 0 || len(parts[0]) == 0 {
+		return 0, kernelErr.Throwf("invalid kernel release: %q", release)
+	}
+
+	var nums [3]int
+	for i := 0; i < len(parts) && i < len(nums); i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, kernelErr.Throwf("invalid kernel release: %q", release)
+		}
+
+		nums[i] = n
+	}
+
+	return KernelVersion(nums[0], nums[1], nums[2]), nil
+}
+
+// lsmEnabled reports whether the named security module is listed in the comma
+// separated list of active security modules at path.
+func lsmEnabled(path string, name string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	for _, lsm := range strings.Split(strings.TrimSpace(string(data)), ",") {
+		if lsm == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kernelRelease returns the release string of the running kernel.
+func kernelRelease() (string, error) {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return "", err
+	}
+
+	release := unix.ByteSliceToString(uts.Release[:])
+	if len(release) == 0 {
+		return "", errors.New("empty kernel release")
+	}
+
+	return release, nil
+}
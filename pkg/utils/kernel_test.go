@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseKernelRelease is a Go function for testing the ParseKernelRelease function.
+func TestParseKernelRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		release string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "plain release",
+			release: "5.8.3",
+			want:    KernelVersion(5, 8, 3),
+		},
+		{
+			name:    "distribution suffix",
+			release: "5.15.0-91-generic",
+			want:    KernelVersion(5, 15, 0),
+		},
+		{
+			name:    "missing patch",
+			release: "6.1-rc3",
+			want:    KernelVersion(6, 1, 0),
+		},
+		{
+			name:    "patch greater than 255",
+			release: "4.9.337+",
+			want:    KernelVersion(4, 9, 255),
+		},
+		{
+			name:    "empty release",
+			release: "",
+			wantErr: true,
+		},
+		{
+			name:    "invalid release",
+			release: "abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKernelRelease(tt.release)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseKernelRelease() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseKernelRelease() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCurrentKernelVersion is a Go function for testing the CurrentKernelVersion function.
+func TestCurrentKernelVersion(t *testing.T) {
+	got, err := CurrentKernelVersion()
+	if err != nil {
+		t.Fatalf("CurrentKernelVersion() error = %v", err)
+	}
+
+	if got < KernelVersion(3, 0, 0) {
+		t.Errorf("CurrentKernelVersion() = %v, want a valid kernel version", got)
+	}
+}
+
+// Test_lsmEnabled is a Go function for testing the lsmEnabled function.
+func Test_lsmEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		missing bool
+		want    bool
+	}{
+		{
+			name:    "bpf listed",
+			content: "lockdown,capability,landlock,yama,apparmor,bpf\n",
+			want:    true,
+		},
+		{
+			name:    "bpf not listed",
+			content: "lockdown,capability,yama,apparmor",
+			want:    false,
+		},
+		{
+			name:    "prefix of another module",
+			content: "bpfilter",
+			want:    false,
+		},
+		{
+			name:    "missing file",
+			missing: true,
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lsm")
+			if !tt.missing {
+				if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := lsmEnabled(path, "bpf"); got != tt.want {
+				t.Errorf("lsmEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,8 +6,6 @@ package utils
 import (
 	"fmt"
 	"log"
-	"os"
-	"strconv"
 
 	"github.com/intelops/tarian-detector/pkg/err"
 )
@@ -27,38 +25,6 @@ func KernelVersion(major, minor, patch int) int {
 	return (major << 16) + (minor << 8) + patch
 }
 
-// CurrentKernelVersion retrieves the current kernel version from environment variables.
-// It returns an error if the required environment variables are not set or if they cannot be converted to integers.
-func CurrentKernelVersion() (int, error) {
-	const (
-		envNotFound string = "unable to check the kernel version, LINUX_VERSION_MAJOR, LINUX_VERSION_MINOR, LINUX_VERSION_PATCH must be defined"
-	)
-
-	// Get environment variables
-	major, minor, patch := os.Getenv("LINUX_VERSION_MAJOR"), os.Getenv("LINUX_VERSION_MINOR"), os.Getenv("LINUX_VERSION_PATCH")
-	// Check if environment variables are not set
-	if len(major) == 0 || len(minor) == 0 || len(patch) == 0 {
-		return 0, utilsErr.Throw(envNotFound)
-	}
-
-	a, err := strconv.Atoi(major)
-	if err != nil {
-		return 0, utilsErr.Throwf("%v", err)
-	}
-
-	b, err := strconv.Atoi(minor)
-	if err != nil {
-		return 0, utilsErr.Throwf("%v", err)
-	}
-
-	c, err := strconv.Atoi(patch)
-	if err != nil {
-		return 0, utilsErr.Throwf("%v", err)
-	}
-
-	return KernelVersion(a, b, c), nil
-}
-
 // PrintEvent prints the given data map along with a total captured count and a divider.
 // It uses a predefined set of keys to extract values from the data map.
 func PrintEvent(data map[string]any, t int) {
@@ -71,11 +37,19 @@ func PrintEvent(data map[string]any, t int) {
 		"sysname", "nodename", "release", "version", "machine", "domainname",
 		"context",
 	}
+	// keys of the process tree, of the file descriptor table, of the syscall records merged from their entry and exit events, and of their latency summary
+	optionalKeys := []string{"synthetic", "ancestry", "descriptor", "return", "errno", "duration", "interval", "latency"}
 	div := "=================================="
 	msg := ""
 	for _, ky := range keys {
 		msg += fmt.Sprintf("%s: %+v\n", ky, data[ky])
 	}
 
+	for _, ky := range optionalKeys {
+		if vl, ok := data[ky]; ok {
+			msg += fmt.Sprintf("%s: %+v\n", ky, vl)
+		}
+	}
+
 	log.Printf("Total captured %d.\n%s\n%s%s\n", t, div, msg, div)
 }
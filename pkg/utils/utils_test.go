@@ -4,8 +4,6 @@
 package utils
 
 import (
-	"fmt"
-	"os"
 	"testing"
 )
 
@@ -15,18 +13,6 @@ type args struct {
 	patch any
 }
 
-func (a *args) setup() {
-	os.Setenv("LINUX_VERSION_MAJOR", fmt.Sprintf("%v", a.major))
-	os.Setenv("LINUX_VERSION_MINOR", fmt.Sprintf("%v", a.minor))
-	os.Setenv("LINUX_VERSION_PATCH", fmt.Sprintf("%v", a.patch))
-}
-
-func (a *args) teardown() {
-	os.Unsetenv("LINUX_VERSION_MAJOR")
-	os.Unsetenv("LINUX_VERSION_MINOR")
-	os.Unsetenv("LINUX_VERSION_PATCH")
-}
-
 // TestKernelVersion is a Go function for testing the KernelVersion function.
 func TestKernelVersion(t *testing.T) {
 	tests := []struct {
@@ -76,83 +62,6 @@ func TestKernelVersion(t *testing.T) {
 	}
 }
 
-// TestCurrentKernelVersion is a Go function for testing the CurrentKernelVersion function.
-func TestCurrentKernelVersion(t *testing.T) {
-	tests := []struct {
-		name    string
-		args    *args
-		want    int
-		wantErr bool
-	}{
-		{
-			name: "valid values",
-			args: &args{
-				major: 5,
-				minor: 8,
-				patch: 3,
-			},
-			want:    329731,
-			wantErr: false,
-		},
-		{
-			name: "invalid major value",
-			args: &args{
-				major: "ad",
-				minor: 1,
-				patch: 3,
-			},
-			want:    0,
-			wantErr: true,
-		},
-		{
-			name: "invalid minor value",
-			args: &args{
-				major: 0,
-				minor: "dsf",
-				patch: 9,
-			},
-			want:    0,
-			wantErr: true,
-		},
-		{
-			name: "invalid patch value",
-			args: &args{
-				major: 0,
-				minor: 3,
-				patch: "ds",
-			},
-			want:    0,
-			wantErr: true,
-		},
-		{
-			name: "empty values",
-			args: &args{
-				major: "",
-				minor: "",
-				patch: "",
-			},
-			want:    0,
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.args.setup()
-
-			got, err := CurrentKernelVersion()
-			if (err != nil) != tt.wantErr {
-				t.Errorf("CurrentKernelVersion() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("CurrentKernelVersion() = %v, want %v", got, tt.want)
-			}
-
-			tt.args.teardown()
-		})
-	}
-}
-
 // TestPrintEvent is a Go function for testing the PrintEvent function.
 func TestPrintEvent(t *testing.T) {
 	type args struct {
@@ -178,6 +87,17 @@ func TestPrintEvent(t *testing.T) {
 				},
 				t: 0,
 			},
+		}, {
+			name: "merged syscall record",
+			args: args{
+				data: map[string]any{
+					"eventId":  "sys_openat",
+					"return":   int64(-2),
+					"errno":    "ENOENT",
+					"duration": uint64(1500),
+				},
+				t: 1,
+			},
 		}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
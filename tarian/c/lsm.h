@@ -0,0 +1,181 @@
+#ifndef __LSM_H__
+#define __LSM_H__
+
+#include "common.h"
+
+/*
+ * Policy enforcement on lsm hooks. The policies are populated from userspace,
+ * a matching operation is reported and, if the policy enforces it and the
+ * handler runs as a bpf lsm program, denied with -EPERM.
+ */
+
+#define MAX_LSM_POLICIES 1024
+
+/* lsm hooks policies apply to, in sync with tarian.LsmHook */
+enum lsm_hook_e {
+  TDL_BPRM_CHECK_SECURITY = 0,
+  TDL_FILE_OPEN,
+  TDL_SOCKET_CONNECT,
+};
+
+/* outcome of a policy match */
+enum lsm_action_e {
+  TDA_DETECTED = 0,
+  TDA_DENIED,
+};
+
+typedef struct lsm_inode_key {
+  u64 ino;  /* inode number */
+  u32 dev;  /* device of the inode's super block */
+  u32 hook; /* lsm_hook_e */
+} lsm_inode_key_t; /* 16B */
+
+typedef struct lsm_net_key {
+  u16 family;  /* AF_INET or AF_INET6 */
+  u16 port;    /* port in network byte order */
+  u8 addr[16]; /* ipv4 in the first 4 bytes, all zero matches any address */
+} lsm_net_key_t; /* 20B */
+
+typedef struct lsm_policy {
+  u32 id;      /* policy id reported with the matches */
+  u32 enforce; /* deny the matching operations */
+} lsm_policy_t; /* 8B */
+
+BPF_HASH(lsm_inode_policies, lsm_inode_key_t, lsm_policy_t, MAX_LSM_POLICIES);
+BPF_HASH(lsm_net_policies, lsm_net_key_t, lsm_policy_t, MAX_LSM_POLICIES);
+
+stain lsm_policy_t *lookup__inode_policy(struct inode *inode, u32 hook) {
+  lsm_inode_key_t key = {};
+  key.ino = BPF_CORE_READ(inode, i_ino);
+  key.dev = BPF_CORE_READ(inode, i_sb, s_dev);
+  key.hook = hook;
+
+  return bpf_map_lookup_elem(&lsm_inode_policies, &key);
+}
+
+stain lsm_policy_t *lookup__net_policy(struct sockaddr *address) {
+  lsm_net_key_t key = {};
+  key.family = BPF_CORE_READ(address, sa_family);
+
+  if (key.family == AF_INET) {
+    struct sockaddr_in *in = (struct sockaddr_in *)address;
+    key.port = BPF_CORE_READ(in, sin_port);
+    bpf_probe_read_kernel(key.addr, 4, &in->sin_addr);
+  } else if (key.family == AF_INET6) {
+    struct sockaddr_in6 *in6 = (struct sockaddr_in6 *)address;
+    key.port = BPF_CORE_READ(in6, sin6_port);
+    bpf_probe_read_kernel(key.addr, 16, &in6->sin6_addr);
+  } else {
+    return NULL;
+  }
+
+  lsm_policy_t *policy = bpf_map_lookup_elem(&lsm_net_policies, &key);
+  if (policy)
+    return policy;
+
+  /* policies on a port only */
+  __builtin_memset(key.addr, 0, sizeof(key.addr));
+  return bpf_map_lookup_elem(&lsm_net_policies, &key);
+}
+
+/* can_deny is false when the handler runs on a kprobe, which cannot change the return value */
+stain u8 lsm_action(lsm_policy_t *policy, bool can_deny) {
+  return (can_deny && policy->enforce) ? TDA_DENIED : TDA_DETECTED;
+}
+
+stain int lsm_verdict(u8 action) {
+  return action == TDA_DENIED ? -EPERM : 0;
+}
+
+/*====================== bprm_check_security ======================*/
+
+stain int handle_bprm_check_security(void *ctx, struct linux_binprm *bprm, bool can_deny) {
+  lsm_policy_t *policy = lookup__inode_policy(BPF_CORE_READ(bprm, file, f_inode), TDL_BPRM_CHECK_SECURITY);
+  if (!policy)
+    return 0;
+
+  u32 id = policy->id;
+  u8 action = lsm_action(policy, can_deny);
+
+  tarian_event_t te;
+  int resp = new_event(ctx, TDE_LSM_BPRM_CHECK_SECURITY, &te, VARIABLE, TDS_LSM_BPRM_CHECK_SECURITY);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return lsm_verdict(action);
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_U32, &id);
+  tdf_save(&te, TDT_U8, &action);
+  tdf_flex_save(&te, TDT_STR, (unsigned long)BPF_CORE_READ(bprm, filename), 0, KERNEL);
+  /*====================== PARAMETERS ======================*/
+
+  tdf_submit_event(&te);
+
+  return lsm_verdict(action);
+}
+
+/*====================== file_open ======================*/
+
+stain int handle_file_open(void *ctx, struct file *file, bool can_deny) {
+  struct inode *inode = BPF_CORE_READ(file, f_inode);
+
+  lsm_policy_t *policy = lookup__inode_policy(inode, TDL_FILE_OPEN);
+  if (!policy)
+    return 0;
+
+  u32 id = policy->id;
+  u8 action = lsm_action(policy, can_deny);
+
+  tarian_event_t te;
+  int resp = new_event(ctx, TDE_LSM_FILE_OPEN, &te, VARIABLE, TDS_LSM_FILE_OPEN);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return lsm_verdict(action);
+  }
+
+  u32 dev = BPF_CORE_READ(inode, i_sb, s_dev);
+  u64 ino = BPF_CORE_READ(inode, i_ino);
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_U32, &id);
+  tdf_save(&te, TDT_U8, &action);
+  tdf_save(&te, TDT_U32, &dev);
+  tdf_save(&te, TDT_U64, &ino);
+  tdf_flex_save(&te, TDT_STR, (unsigned long)BPF_CORE_READ(file, f_path.dentry, d_name.name), 0, KERNEL);
+  /*====================== PARAMETERS ======================*/
+
+  tdf_submit_event(&te);
+
+  return lsm_verdict(action);
+}
+
+/*====================== socket_connect ======================*/
+
+stain int handle_socket_connect(void *ctx, struct sockaddr *address, int addrlen, bool can_deny) {
+  lsm_policy_t *policy = lookup__net_policy(address);
+  if (!policy)
+    return 0;
+
+  u32 id = policy->id;
+  u8 action = lsm_action(policy, can_deny);
+
+  tarian_event_t te;
+  int resp = new_event(ctx, TDE_LSM_SOCKET_CONNECT, &te, FIXED, TDS_LSM_SOCKET_CONNECT);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return lsm_verdict(action);
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_U32, &id);
+  tdf_save(&te, TDT_U8, &action);
+  tdf_flex_save(&te, TDT_SOCKADDR, (unsigned long)address, addrlen, KERNEL);
+  /*====================== PARAMETERS ======================*/
+
+  tdf_submit_event(&te);
+
+  return lsm_verdict(action);
+}
+
+#endif
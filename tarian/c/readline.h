@@ -0,0 +1,37 @@
+#ifndef __READLINE_H__
+#define __READLINE_H__
+
+#include "common.h"
+
+/*
+ * Lines read by the interactive shells, captured on the return of readline,
+ * either linked in the shell (bash) or from libreadline. Unlike execve, it
+ * captures the builtins and the line as typed.
+ */
+
+/* char *readline(const char *prompt) */
+stain int handle_readline_r(struct pt_regs *ctx) {
+  unsigned long line = PT_REGS_RC(ctx);
+  if (!line)
+    return 0;
+
+  /* skip the empty lines */
+  char c = 0;
+  if (bpf_probe_read_user(&c, sizeof(c), (void *)line) != 0 || c == 0)
+    return 0;
+
+  tarian_event_t te;
+  int resp = new_event(ctx, TDE_READLINE, &te, VARIABLE, TDS_READLINE);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_flex_save(&te, TDT_STR, line, 0, USER);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+#endif
@@ -0,0 +1,89 @@
+#ifndef __SCHED_H__
+#define __SCHED_H__
+
+#include "common.h"
+
+/*
+ * Process lifecycle on the sched tracepoints, independent of the syscall used:
+ * the child of every fork, vfork and clone, the successful program executions
+ * and the exit of every task. The tracepoints run in the context of the parent,
+ * of the task after the execution, and of the exiting task respectively.
+ */
+
+/* TP_PROTO(struct task_struct *parent, struct task_struct *child) */
+stain int handle_sched_process_fork(struct bpf_raw_tracepoint_args *ctx) {
+  struct task_struct *child = (struct task_struct *)ctx->args[1];
+
+  tarian_event_t te;
+  int resp = new_event(ctx, TDE_SCHED_PROCESS_FORK, &te, FIXED, TDS_SCHED_PROCESS_FORK);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  u32 child_pid = BPF_CORE_READ(child, tgid);
+  u32 child_tid = BPF_CORE_READ(child, pid);
+  /* the exec id of the events of the child */
+  u64 child_exec_id = getExecId(child_pid, child);
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_U32, &child_pid);
+  tdf_save(&te, TDT_U32, &child_tid);
+  tdf_save(&te, TDT_U64, &child_exec_id);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* TP_PROTO(struct task_struct *p, pid_t old_pid, struct linux_binprm *bprm) */
+stain int handle_sched_process_exec(struct bpf_raw_tracepoint_args *ctx) {
+  struct linux_binprm *bprm = (struct linux_binprm *)ctx->args[2];
+
+  tarian_event_t te;
+  int resp = new_event(ctx, TDE_SCHED_PROCESS_EXEC, &te, VARIABLE, TDS_SCHED_PROCESS_EXEC);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /* the thread id before the execution, other than the process id if a thread executed */
+  int32_t old_pid = (int32_t)ctx->args[1];
+
+  /*====================== PARAMETERS ======================*/
+  tdf_flex_save(&te, TDT_STR, (unsigned long)BPF_CORE_READ(bprm, filename), 0, KERNEL);
+  tdf_save(&te, TDT_S32, &old_pid);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* TP_PROTO(struct task_struct *p) */
+stain int handle_sched_process_exit(struct bpf_raw_tracepoint_args *ctx) {
+  struct task_struct *task = (struct task_struct *)ctx->args[0];
+
+  tarian_event_t te;
+  int resp = new_event(ctx, TDE_SCHED_PROCESS_EXIT, &te, FIXED, TDS_SCHED_PROCESS_EXIT);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /* wait status: the exit code in the second byte, or the signal and the core dump flag */
+  int code = BPF_CORE_READ(task, exit_code);
+  int32_t exit_code = (code >> 8) & 0xff;
+  uint16_t signal = code & 0x7f;
+  uint8_t core_dumped = (code & 0x80) != 0;
+  /* the last thread of the process exits, signal->live is decremented before the tracepoint */
+  uint8_t group_dead = BPF_CORE_READ(task, signal, live.counter) == 0;
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &exit_code);
+  tdf_save(&te, TDT_U16, &signal);
+  tdf_save(&te, TDT_U8, &core_dumped);
+  tdf_save(&te, TDT_U8, &group_dead);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+#endif
@@ -0,0 +1,1233 @@
+#ifndef __SYSCALLS_H__
+#define __SYSCALLS_H__
+
+#include "common.h"
+
+/*
+ * Syscall event writers shared by all the syscall capture backends. regs is the
+ * syscall register context, ctx the context of the calling program.
+ */
+
+/*====================== execve ======================*/
+
+stain void save_execve_args(tarian_event_t *te, struct pt_regs *regs) {
+  tdf_flex_save(te, TDT_STR, get_syscall_param(regs, 0) /* filename */, 0,USER);
+  tdf_flex_save(te, TDT_STR_ARR, get_syscall_param(regs, 1) /* argv */, 0,USER);
+  tdf_flex_save(te, TDT_STR_ARR, get_syscall_param(regs, 2) /* envp */, 0,USER);
+}
+
+stain int handle_execve_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_EXECVE_E, &te, VARIABLE, TDS_EXECVE_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_execve_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_execve_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_EXECVE_R, &te, FIXED, TDS_EXECVE_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== execveat ======================*/
+
+stain void save_execveat_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd /* fd */);
+
+  tdf_flex_save(te, TDT_STR, get_syscall_param(regs, 1) /* filename */, 0, USER);
+  tdf_flex_save(te, TDT_STR_ARR, get_syscall_param(regs, 2) /* argv */, 0, USER);
+  tdf_flex_save(te, TDT_STR_ARR, get_syscall_param(regs, 3) /* envp */, 0, USER);
+
+  int flags = get_syscall_param(regs, 4);
+  tdf_save(te, TDT_S32, &flags /* flags */);
+}
+
+stain int handle_execveat_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_EXECVEAT_E, &te, VARIABLE, TDS_EXECVEAT_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_execveat_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_execveat_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_EXECVEAT_R, &te, FIXED, TDS_EXECVEAT_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== clone ======================*/
+
+stain void save_clone_args(tarian_event_t *te, struct pt_regs *regs) {
+  uint64_t flags = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_U64, &flags /* clone_flags */);
+
+  uint64_t newsp = get_syscall_param(regs, 1);
+  tdf_save(te, TDT_U64, &newsp /* newsp */);
+
+  int parent_tid;
+  bpf_probe_read_user_str(&parent_tid, sizeof(parent_tid), (void *)get_syscall_param(regs, 2));
+  tdf_save(te, TDT_S32, &parent_tid /* parent_tidptr */);
+
+  int child_tid;
+  bpf_probe_read_user_str(&child_tid, sizeof(child_tid), (void *)get_syscall_param(regs, CLONE_CHILD_TID_PARAM));
+  tdf_save(te, TDT_S32, &child_tid /* child_tidptr */);
+
+  uint64_t tls = get_syscall_param(regs, CLONE_TLS_PARAM);
+  tdf_save(te, TDT_U64, &tls /* tls */);
+}
+
+stain int handle_clone_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CLONE_E, &te, FIXED, TDS_CLONE_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_clone_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_clone_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CLONE_R, &te, FIXED, TDS_CLONE_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_clone(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CLONE, &te, FIXED, TDS_CLONE);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_clone_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== close ======================*/
+
+stain void save_close_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd /* fd */);
+}
+
+stain int handle_close_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CLOSE_E, &te, FIXED, TDS_CLOSE_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_close_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_close_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CLOSE_R, &te, FIXED, TDS_CLOSE_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_close(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CLOSE, &te, FIXED, TDS_CLOSE);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_close_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== read ======================*/
+
+stain void save_read_args(tarian_event_t *te, struct pt_regs *regs) {
+  int32_t fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd /* fd */);
+
+  uint32_t count = get_syscall_param(regs, 2);
+  tdf_flex_save(te, TDT_BYTE_ARR, get_syscall_param(regs, 1), count, USER);
+
+  tdf_save(te, TDT_U32, &count /* count */);
+}
+
+stain int handle_read_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_READ_E, &te, VARIABLE, TDS_READ_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_read_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_read_r(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_READ_R, &te, FIXED, TDS_READ_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_read(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_READ, &te, VARIABLE, TDS_READ);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_read_args(&te, regs);
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== write ======================*/
+
+stain void save_write_args(tarian_event_t *te, struct pt_regs *regs) {
+  int32_t fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd /* fd */);
+
+  uint32_t count = get_syscall_param(regs, 2);
+  tdf_flex_save(te, TDT_BYTE_ARR, get_syscall_param(regs, 1), count, USER);
+
+  tdf_save(te, TDT_U32, &count /* count */);
+}
+
+stain int handle_write_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_WRITE_E, &te, VARIABLE, TDS_WRITE_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_write_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_write_r(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_WRITE_R, &te, FIXED, TDS_WRITE_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_write(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_WRITE, &te, VARIABLE, TDS_WRITE);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_write_args(&te, regs);
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== open ======================*/
+
+stain void save_open_args(tarian_event_t *te, struct pt_regs *regs) {
+  tdf_flex_save(te, TDT_STR, get_syscall_param(regs, 0), 0, USER);
+
+  int flags = get_syscall_param(regs, 1);
+  tdf_save(te, TDT_S32, &flags);
+
+  unsigned int mode = get_syscall_param(regs, 2);
+  tdf_save(te, TDT_U32, &mode);
+}
+
+stain int handle_open_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPEN_E, &te, VARIABLE, TDS_OPEN_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_open_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_open_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPEN_R, &te, FIXED, TDS_OPEN_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_U32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_open(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPEN, &te, VARIABLE, TDS_OPEN);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_open_args(&te, regs);
+  tdf_save(&te, TDT_U32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== readv ======================*/
+
+stain void save_readv_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd);
+
+  int vlen = get_syscall_param(regs, 2);
+
+  tdf_flex_save(te, TDT_IOVEC_ARR, get_syscall_param(regs, 1), vlen, USER);
+
+  tdf_save(te, TDT_S32, &vlen);
+}
+
+stain int handle_readv_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_READV_E, &te, VARIABLE, TDS_READV_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_readv_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_readv_r(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_READV_R, &te, FIXED, TDS_READV_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_readv(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_READV, &te, VARIABLE, TDS_READV);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_readv_args(&te, regs);
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== writev ======================*/
+
+stain void save_writev_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd);
+
+  int vlen = get_syscall_param(regs, 2);
+  tdf_flex_save(te, TDT_IOVEC_ARR, get_syscall_param(regs, 1), vlen, USER);
+
+  tdf_save(te, TDT_S32, &vlen);
+}
+
+stain int handle_writev_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_WRITEV_E, &te, VARIABLE, TDS_WRITEV_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_writev_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_writev_r(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_WRITEV_R, &te, FIXED, TDS_WRITEV_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_writev(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_WRITEV, &te, VARIABLE, TDS_WRITEV);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_writev_args(&te, regs);
+  tdf_save(&te, TDT_S64, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== openat ======================*/
+
+stain void save_openat_args(tarian_event_t *te, struct pt_regs *regs) {
+  int dfd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32,  &dfd);
+
+  tdf_flex_save(te, TDT_STR, get_syscall_param(regs, 1), 0, USER);
+
+  int flags = get_syscall_param(regs , 2);
+  tdf_save(te, TDT_S32, &flags);
+
+  unsigned int  mode = get_syscall_param(regs, 3);
+  tdf_save(te, TDT_U32, &mode);
+}
+
+stain int handle_openat_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPENAT_E, &te, VARIABLE, TDS_OPENAT_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_openat_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_openat_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPENAT_R, &te, FIXED, TDS_OPENAT_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_openat(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPENAT, &te, VARIABLE, TDS_OPENAT);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_openat_args(&te, regs);
+  tdf_save(&te, TDT_S32,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== openat2 ======================*/
+
+stain void save_openat2_args(tarian_event_t *te, struct pt_regs *regs) {
+  int dfd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32,  &dfd);
+
+  tdf_flex_save(te, TDT_STR, get_syscall_param(regs, 1), 0, USER);
+
+  struct open_how how = {0};
+  bpf_probe_read_user((void *)&how, bpf_core_type_size(struct open_how), (void *)get_syscall_param(regs, 2));
+
+  tdf_save(te, TDT_S64, &how.flags);
+  tdf_save(te, TDT_S64, &how.mode);
+  tdf_save(te, TDT_S64, &how.resolve);
+
+  int usize = get_syscall_param(regs, 3);
+  tdf_save(te, TDT_S32, &usize);
+}
+
+stain int handle_openat2_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPENAT2_E, &te, VARIABLE, TDS_OPENAT2_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_openat2_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_openat2_r(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPENAT2_R, &te, FIXED, TDS_OPENAT2_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S64,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_openat2(void *ctx, struct pt_regs *regs, long ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_OPENAT2, &te, VARIABLE, TDS_OPENAT2);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_openat2_args(&te, regs);
+  tdf_save(&te, TDT_S64,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== listen ======================*/
+
+stain void save_listen_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd);
+
+  int backlog = get_syscall_param(regs, 1);
+  tdf_save(te, TDT_S32, &backlog);
+}
+
+stain int handle_listen_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_LISTEN_E, &te, FIXED, TDS_LISTEN_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_listen_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_listen_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_LISTEN_R, &te, FIXED, TDS_LISTEN_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_listen(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_LISTEN, &te, FIXED, TDS_LISTEN);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_listen_args(&te, regs);
+  tdf_save(&te, TDT_S32,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== socket ======================*/
+
+stain void save_socket_args(tarian_event_t *te, struct pt_regs *regs) {
+  int family = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &family);
+
+  int type = get_syscall_param(regs, 1);
+  tdf_save(te, TDT_S32, &type);
+
+  int protocol = get_syscall_param(regs, 2);
+  tdf_save(te, TDT_S32, &protocol);
+}
+
+stain int handle_socket_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_SOCKET_E, &te, FIXED, TDS_SOCKET_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_socket_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_socket_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_SOCKET_R, &te, FIXED, TDS_SOCKET_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_socket(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_SOCKET, &te, FIXED, TDS_SOCKET);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_socket_args(&te, regs);
+  tdf_save(&te, TDT_S32,  &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== accept ======================*/
+
+stain void save_accept_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd);
+
+  int addrlen;
+  bpf_probe_read_user(&addrlen, sizeof(addrlen),  (void*)get_syscall_param(regs, 2));
+
+  tdf_flex_save(te, TDT_SOCKADDR, get_syscall_param(regs, 1), addrlen, USER);
+
+  tdf_save(te, TDT_S32, &addrlen);
+}
+
+/* the peer of the accepted socket, read from its sock as the address written to upeer_sockaddr
+   is optional */
+stain void save_accept_peer(tarian_event_t *te, int ret) {
+  struct sock *sk = get_task_fd_sock((struct task_struct *)bpf_get_current_task(), ret);
+  tdf_save_sock_peer(te, sk);
+}
+
+stain int handle_accept_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_ACCEPT_E, &te, VARIABLE,  TDS_ACCEPT_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_accept_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_accept_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_ACCEPT_R, &te, FIXED,  TDS_ACCEPT_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  save_accept_peer(&te, ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_accept(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_ACCEPT, &te, VARIABLE, TDS_ACCEPT);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_accept_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  save_accept_peer(&te, ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== bind ======================*/
+
+stain void save_bind_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd);
+
+  int addrlen = get_syscall_param(regs, 2);
+  tdf_flex_save(te, TDT_SOCKADDR, get_syscall_param(regs, 1), addrlen, USER);
+
+  tdf_save(te, TDT_S32, &addrlen);
+}
+
+stain int handle_bind_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_BIND_E, &te, VARIABLE,  TDS_BIND_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_bind_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_bind_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_BIND_R, &te, FIXED,  TDS_BIND_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_bind(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_BIND, &te, VARIABLE, TDS_BIND);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_bind_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== connect ======================*/
+
+stain void save_connect_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd);
+
+  int addrlen = get_syscall_param(regs, 2);
+  tdf_flex_save(te, TDT_SOCKADDR, get_syscall_param(regs, 1), addrlen, USER);
+
+  tdf_save(te, TDT_S32, &addrlen);
+}
+
+stain int handle_connect_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CONNECT_E, &te, VARIABLE,  TDS_CONNECT_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_connect_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_connect_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CONNECT_R, &te, FIXED,  TDS_CONNECT_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_connect(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_CONNECT, &te, VARIABLE, TDS_CONNECT);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_connect_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== dup ======================*/
+
+stain void save_dup_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fildes = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fildes /* fildes */);
+}
+
+stain int handle_dup_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP_E, &te, FIXED, TDS_DUP_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_dup_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_dup_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP_R, &te, FIXED, TDS_DUP_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_dup(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP, &te, FIXED, TDS_DUP);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_dup_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== dup2 ======================*/
+
+stain void save_dup2_args(tarian_event_t *te, struct pt_regs *regs) {
+  int oldfd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &oldfd /* oldfd */);
+
+  int newfd = get_syscall_param(regs, 1);
+  tdf_save(te, TDT_S32, &newfd /* newfd */);
+}
+
+stain int handle_dup2_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP2_E, &te, FIXED, TDS_DUP2_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_dup2_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_dup2_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP2_R, &te, FIXED, TDS_DUP2_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_dup2(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP2, &te, FIXED, TDS_DUP2);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_dup2_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== dup3 ======================*/
+
+stain void save_dup3_args(tarian_event_t *te, struct pt_regs *regs) {
+  int oldfd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &oldfd /* oldfd */);
+
+  int newfd = get_syscall_param(regs, 1);
+  tdf_save(te, TDT_S32, &newfd /* newfd */);
+
+  int flags = get_syscall_param(regs, 2);
+  tdf_save(te, TDT_S32, &flags /* flags */);
+}
+
+stain int handle_dup3_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP3_E, &te, FIXED, TDS_DUP3_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_dup3_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_dup3_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP3_R, &te, FIXED, TDS_DUP3_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_dup3(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_DUP3, &te, FIXED, TDS_DUP3);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_dup3_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== fcntl ======================*/
+
+stain void save_fcntl_args(tarian_event_t *te, struct pt_regs *regs) {
+  int fd = get_syscall_param(regs, 0);
+  tdf_save(te, TDT_S32, &fd /* fd */);
+
+  int cmd = get_syscall_param(regs, 1);
+  tdf_save(te, TDT_S32, &cmd /* cmd */);
+
+  unsigned long arg = get_syscall_param(regs, 2);
+  tdf_save(te, TDT_U64, &arg /* arg */);
+}
+
+stain int handle_fcntl_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_FCNTL_E, &te, FIXED, TDS_FCNTL_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_fcntl_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_fcntl_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_FCNTL_R, &te, FIXED, TDS_FCNTL_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_fcntl(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_FCNTL, &te, FIXED, TDS_FCNTL);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_fcntl_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/*====================== accept4 ======================*/
+
+stain void save_accept4_args(tarian_event_t *te, struct pt_regs *regs) {
+  save_accept_args(te, regs);
+
+  int flags = get_syscall_param(regs, 3);
+  tdf_save(te, TDT_S32, &flags /* flags */);
+}
+
+stain int handle_accept4_e(void *ctx, struct pt_regs *regs) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_ACCEPT4_E, &te, VARIABLE, TDS_ACCEPT4_E);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_accept4_args(&te, regs);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain int handle_accept4_r(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_ACCEPT4_R, &te, FIXED, TDS_ACCEPT4_R);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  tdf_save(&te, TDT_S32, &ret);
+  save_accept_peer(&te, ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+/* entry arguments and return value in one event */
+stain int handle_accept4(void *ctx, struct pt_regs *regs, int ret) {
+  tarian_event_t te;
+  int resp = new_syscall_event(ctx, regs, TDE_SYSCALL_ACCEPT4, &te, VARIABLE, TDS_ACCEPT4);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  /*====================== PARAMETERS ======================*/
+  save_accept4_args(&te, regs);
+  tdf_save(&te, TDT_S32, &ret);
+  save_accept_peer(&te, ret);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+#endif
@@ -3,627 +3,472 @@
 
 // go:build ignore
 
-#include "common.h"
+#include "syscalls.h"
+#include "sched.h"
+
+/*
+ * Every syscall can be captured by two backends producing identical events,
+ * the fentry/fexit backend lives in tarian_fexit.bpf.c:
+ *  - a kprobe & kretprobe pair on the syscall wrapper (tdf_<syscall>_e/r)
+ *  - the raw_syscalls sys_enter & sys_exit tracepoints, which tail call the
+ *    handlers (tdf_rtp_<syscall>_e/r) registered by syscall number in
+ *    sys_enter_calls & sys_exit_calls.
+ */
+
+RAW_TRACEPOINT(sys_enter)
+int tdf_sys_enter(struct bpf_raw_tracepoint_args *ctx) {
+  /* syscall numbers of compat tasks belong to a different table */
+  if (is_compat_task((struct task_struct *)bpf_get_current_task()))
+    return 0;
+
+  bpf_tail_call(ctx, &sys_enter_calls, (u32)ctx->args[1]);
+
+  return 0;
+}
+
+RAW_TRACEPOINT(sys_exit)
+int tdf_sys_exit(struct bpf_raw_tracepoint_args *ctx) {
+  if (is_compat_task((struct task_struct *)bpf_get_current_task()))
+    return 0;
+
+  struct pt_regs *regs = (struct pt_regs *)ctx->args[0];
+  bpf_tail_call(ctx, &sys_exit_calls, get_syscall_id(regs));
+
+  return 0;
+}
 
 KPROBE("__x64_sys_execve")
 int BPF_KPROBE(tdf_execve_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_EXECVE_E, &te, VARIABLE, TDS_EXECVE_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_flex_save(&te, TDT_STR, get_syscall_param(regs, 0) /* filename */, 0,USER);
-  tdf_flex_save(&te, TDT_STR_ARR, get_syscall_param(regs, 1) /* argv */, 0,USER);
-  tdf_flex_save(&te, TDT_STR_ARR, get_syscall_param(regs, 2) /* envp */, 0,USER);
-  /*====================== PARAMETERS ======================*/
+  return handle_execve_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_execve_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_execve_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_execve")
 int BPF_KRETPROBE(tdf_execve_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_EXECVE_R, &te, FIXED, TDS_EXECVE_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32, &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_execve_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_execve_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_execve_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_execveat")
 int BPF_KPROBE(tdf_execveat_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_EXECVEAT_E, &te, VARIABLE, TDS_EXECVEAT_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd /* fd */);
-
-  tdf_flex_save(&te, TDT_STR, get_syscall_param(regs, 1) /* filename */, 0, USER);
-  tdf_flex_save(&te, TDT_STR_ARR, get_syscall_param(regs, 2) /* argv */, 0, USER);
-  tdf_flex_save(&te, TDT_STR_ARR, get_syscall_param(regs, 3) /* envp */, 0, USER);
-
-  int flags = get_syscall_param(regs, 4);
-  tdf_save(&te, TDT_S32, &flags /* flags */);
-  /*====================== PARAMETERS ======================*/
+  return handle_execveat_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_execveat_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_execveat_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_execveat")
 int BPF_KRETPROBE(tdf_execveat_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_EXECVEAT_R, &te, FIXED, TDS_EXECVEAT_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32, &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_execveat_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_execveat_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_execveat_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_clone")
 int BPF_KPROBE(tdf_clone_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_CLONE_E, &te, FIXED, TDS_CLONE_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  uint64_t flags = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_U64, &flags /* clone_flags */);
-
-  uint64_t newsp = get_syscall_param(regs, 1);
-  tdf_save(&te, TDT_U64, &newsp /* newsp */);
-
-  int parent_tid;
-  bpf_probe_read_user_str(&parent_tid, sizeof(parent_tid), (void *)get_syscall_param(regs, 2));
-  tdf_save(&te, TDT_S32, &parent_tid /* parent_tidptr */);
-
-  int child_tid;
-  bpf_probe_read_user_str(&child_tid, sizeof(child_tid), (void *)get_syscall_param(regs, 3));
-  tdf_save(&te, TDT_S32, &child_tid /* child_tidptr */);
-
-  uint64_t tls = get_syscall_param(regs, 4);
-  tdf_save(&te, TDT_U64, &tls /* tls */);
-  /*====================== PARAMETERS ======================*/
+  return handle_clone_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_clone_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_clone_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_clone")
 int BPF_KRETPROBE(tdf_clone_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_CLONE_R, &te, FIXED, TDS_CLONE_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32, &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_clone_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_clone_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_clone_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_close")
 int BPF_KPROBE(tdf_close_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_CLOSE_E, &te, FIXED, TDS_CLOSE_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-  
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd /* fd */);
-  /*====================== PARAMETERS ======================*/
+  return handle_close_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_close_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_close_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_close")
 int BPF_KRETPROBE(tdf_close_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_CLOSE_R, &te, FIXED, TDS_CLOSE_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32, &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_close_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_close_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_close_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_read")
 int BPF_KPROBE(tdf_read_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_READ_E, &te, VARIABLE, TDS_READ_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int32_t fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd /* fd */);
-
-  uint32_t count = get_syscall_param(regs, 2);
-  tdf_flex_save(&te, TDT_BYTE_ARR, get_syscall_param(regs, 1), count, USER);
-
-  tdf_save(&te, TDT_U32, &count /* count */);
-  /*====================== PARAMETERS ======================*/
+  return handle_read_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_read_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_read_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_read")
-int BPF_KRETPROBE(tdf_read_r, long ret) { 
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_READ_R, &te, FIXED, TDS_READ_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S64, &ret);
-  /*====================== PARAMETERS ======================*/
+int BPF_KRETPROBE(tdf_read_r, long ret) {
+  return handle_read_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_read_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_read_r(ctx, (struct pt_regs *)ctx->args[0], (long)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_write")
 int BPF_KPROBE(tdf_write_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_WRITE_E, &te, VARIABLE, TDS_WRITE_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-  
-  /*====================== PARAMETERS ======================*/
-  int32_t fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd /* fd */);
-
-  uint32_t count = get_syscall_param(regs, 2);
-  tdf_flex_save(&te, TDT_BYTE_ARR, get_syscall_param(regs, 1), count, USER);
-
-  tdf_save(&te, TDT_U32, &count /* count */);
-  /*====================== PARAMETERS ======================*/
+  return handle_write_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_write_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_write_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_write")
 int BPF_KRETPROBE(tdf_write_r, long ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_WRITE_R, &te, FIXED, TDS_WRITE_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S64, &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_write_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_write_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_write_r(ctx, (struct pt_regs *)ctx->args[0], (long)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_open")
 int BPF_KPROBE(tdf_open_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_OPEN_E, &te, VARIABLE, TDS_OPEN_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_flex_save(&te, TDT_STR, get_syscall_param(regs, 0), 0, USER);
-
-  int flags = get_syscall_param(regs, 1);
-  tdf_save(&te, TDT_S32, &flags);
-
-  unsigned int mode = get_syscall_param(regs, 2);
-  tdf_save(&te, TDT_U32, &mode);
-  /*====================== PARAMETERS ======================*/
+  return handle_open_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_open_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_open_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_open")
 int BPF_KRETPROBE(tdf_open_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_OPEN_R, &te, FIXED, TDS_OPEN_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_U32, &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_open_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_open_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_open_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_readv")
 int BPF_KPROBE(tdf_readv_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_READV_E, &te, VARIABLE, TDS_READV_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd);
-
-  int vlen = get_syscall_param(regs, 2);
-
-  tdf_flex_save(&te, TDT_IOVEC_ARR, get_syscall_param(regs, 1), vlen, USER);
-
-  tdf_save(&te, TDT_S32, &vlen);
-  /*====================== PARAMETERS ======================*/
+  return handle_readv_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_readv_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_readv_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_readv")
-int BPF_KRETPROBE(tdf_readv_r,  long ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_READV_R, &te, FIXED, TDS_READV_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-  
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S64, &ret);
-  /*====================== PARAMETERS ======================*/
+int BPF_KRETPROBE(tdf_readv_r, long ret) {
+  return handle_readv_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_readv_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_readv_r(ctx, (struct pt_regs *)ctx->args[0], (long)ctx->args[1]);
 }
 
 
 KPROBE("__x64_sys_writev")
 int BPF_KPROBE(tdf_writev_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_WRITEV_E, &te, VARIABLE, TDS_WRITEV_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd);
-
-  int vlen = get_syscall_param(regs, 2);
-  tdf_flex_save(&te, TDT_IOVEC_ARR, get_syscall_param(regs, 1), vlen, USER);
-
-  tdf_save(&te, TDT_S32, &vlen);
-  /*====================== PARAMETERS ======================*/
+  return handle_writev_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_writev_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_writev_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_writev")
-int BPF_KRETPROBE(tdf_writev_r,  long ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_WRITEV_R, &te, FIXED, TDS_WRITEV_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-  
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S64, &ret);
-  /*====================== PARAMETERS ======================*/
+int BPF_KRETPROBE(tdf_writev_r, long ret) {
+  return handle_writev_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_writev_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_writev_r(ctx, (struct pt_regs *)ctx->args[0], (long)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_openat")
-int BPF_KPROBE(tdf_openat_e, struct pt_regs  *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_OPENAT_E, &te, VARIABLE, TDS_OPENAT_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int dfd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32,  &dfd);
-
-  tdf_flex_save(&te, TDT_STR, get_syscall_param(regs, 1), 0, USER);
-
-  int flags = get_syscall_param(regs , 2);
-  tdf_save(&te, TDT_S32, &flags);
-
-  unsigned int  mode = get_syscall_param(regs, 3);
-  tdf_save(&te, TDT_U32, &mode);
-  /*====================== PARAMETERS ======================*/
+int BPF_KPROBE(tdf_openat_e, struct pt_regs *regs) {
+  return handle_openat_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_openat_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_openat_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_openat")
 int BPF_KRETPROBE(tdf_openat_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_OPENAT_R, &te, FIXED, TDS_OPENAT_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32,  &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_openat_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_openat_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_openat_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_openat2")
-int BPF_KPROBE(tdf_openat2_e, struct pt_regs  *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_OPENAT2_E, &te, VARIABLE, TDS_OPENAT2_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int dfd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32,  &dfd);
-
-  tdf_flex_save(&te, TDT_STR, get_syscall_param(regs, 1), 0, USER);
-
-  struct open_how how = {0};
-  bpf_probe_read_user((void *)&how, bpf_core_type_size(struct open_how), (void *)get_syscall_param(regs, 2));
-
-  tdf_save(&te, TDT_S64, &how.flags);
-  tdf_save(&te, TDT_S64, &how.mode);
-  tdf_save(&te, TDT_S64, &how.resolve);
-
-  int usize = get_syscall_param(regs, 3);
-  tdf_save(&te, TDT_S32, &usize);
-  /*====================== PARAMETERS ======================*/
+int BPF_KPROBE(tdf_openat2_e, struct pt_regs *regs) {
+  return handle_openat2_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_openat2_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_openat2_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
 KRETPROBE("__x64_sys_openat2")
 int BPF_KRETPROBE(tdf_openat2_r, long ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_OPENAT2_R, &te, FIXED, TDS_OPENAT2_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S64,  &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_openat2_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_openat2_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_openat2_r(ctx, (struct pt_regs *)ctx->args[0], (long)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_listen")
 int BPF_KPROBE(tdf_listen_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_LISTEN_E, &te, FIXED, TDS_LISTEN_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd);
-  
-  int backlog = get_syscall_param(regs, 1);
-  tdf_save(&te, TDT_S32, &backlog);
-  /*====================== PARAMETERS ======================*/
-
-  return tdf_submit_event(&te);
-};
+  return handle_listen_e(ctx, regs);
+}
+
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_listen_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_listen_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
 
 KRETPROBE("__x64_sys_listen")
 int BPF_KRETPROBE(tdf_listen_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_LISTEN_R, &te, FIXED, TDS_LISTEN_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32,  &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_listen_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_listen_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_listen_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_socket")
 int BPF_KPROBE(tdf_socket_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_SOCKET_E, &te, FIXED, TDS_SOCKET_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  int family = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &family);
-  
-  int type = get_syscall_param(regs, 1);
-  tdf_save(&te, TDT_S32, &type);
-
-  int protocol = get_syscall_param(regs, 2);
-  tdf_save(&te, TDT_S32, &protocol);
-  /*====================== PARAMETERS ======================*/
-
-  return tdf_submit_event(&te);
-};
+  return handle_socket_e(ctx, regs);
+}
+
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_socket_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_socket_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
 
 KRETPROBE("__x64_sys_socket")
 int BPF_KRETPROBE(tdf_socket_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_SOCKET_R, &te, FIXED, TDS_SOCKET_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32,  &ret);
-  /*====================== PARAMETERS ======================*/
+  return handle_socket_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_socket_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_socket_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
 KPROBE("__x64_sys_accept")
 int BPF_KPROBE(tdf_accept_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_ACCEPT_E, &te, VARIABLE,  TDS_ACCEPT_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
+  return handle_accept_e(ctx, regs);
+}
 
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_accept_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_accept_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
 
-  int addrlen;
-  bpf_probe_read_user(&addrlen, sizeof(addrlen),  (void*)get_syscall_param(regs, 2));
+KRETPROBE("__x64_sys_accept")
+int BPF_KRETPROBE(tdf_accept_r, int ret) {
+  return handle_accept_r(ctx, NULL, ret);
+}
 
-  tdf_flex_save(&te, TDT_SOCKADDR, get_syscall_param(regs, 1), addrlen, USER);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_accept_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_accept_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
+}
 
-  tdf_save(&te, TDT_S32, &addrlen);
-  /*====================== PARAMETERS ======================*/
+KPROBE("__x64_sys_bind")
+int BPF_KPROBE(tdf_bind_e, struct pt_regs *regs) {
+  return handle_bind_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_bind_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_bind_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
-KRETPROBE("__x64_sys_accept")
-int BPF_KRETPROBE(tdf_accept_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_ACCEPT_R, &te, FIXED,  TDS_ACCEPT_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
+KRETPROBE("__x64_sys_bind")
+int BPF_KRETPROBE(tdf_bind_r, int ret) {
+  return handle_bind_r(ctx, NULL, ret);
+}
 
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32, &ret);
-  /*====================== PARAMETERS ======================*/
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_bind_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_bind_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
+}
 
-  return tdf_submit_event(&te);
+KPROBE("__x64_sys_connect")
+int BPF_KPROBE(tdf_connect_e, struct pt_regs *regs) {
+  return handle_connect_e(ctx, regs);
 }
 
-KPROBE("__x64_sys_bind")
-int BPF_KPROBE(tdf_bind_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_BIND_E, &te, VARIABLE,  TDS_BIND_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_connect_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_connect_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
 
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd);
+KRETPROBE("__x64_sys_connect")
+int BPF_KRETPROBE(tdf_connect_r, int ret) {
+  return handle_connect_r(ctx, NULL, ret);
+}
 
-  int addrlen = get_syscall_param(regs, 2);
-  tdf_flex_save(&te, TDT_SOCKADDR, get_syscall_param(regs, 1), addrlen, USER);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_connect_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_connect_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
+}
 
-  tdf_save(&te, TDT_S32, &addrlen);
-  /*====================== PARAMETERS ======================*/
+KPROBE("__x64_sys_dup")
+int BPF_KPROBE(tdf_dup_e, struct pt_regs *regs) {
+  return handle_dup_e(ctx, regs);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_dup_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_dup_e(ctx, (struct pt_regs *)ctx->args[0]);
 }
 
-KRETPROBE("__x64_sys_bind")
-int BPF_KRETPROBE(tdf_bind_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_BIND_R, &te, FIXED,  TDS_BIND_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
+KRETPROBE("__x64_sys_dup")
+int BPF_KRETPROBE(tdf_dup_r, int ret) {
+  return handle_dup_r(ctx, NULL, ret);
+}
 
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32, &ret);
-  /*====================== PARAMETERS ======================*/
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_dup_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_dup_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
+}
 
-  return tdf_submit_event(&te);
+KPROBE("__x64_sys_dup2")
+int BPF_KPROBE(tdf_dup2_e, struct pt_regs *regs) {
+  return handle_dup2_e(ctx, regs);
 }
 
-KPROBE("__x64_sys_connect")
-int BPF_KPROBE(tdf_connect_e, struct pt_regs *regs) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_CONNECT_E, &te, VARIABLE,  TDS_CONNECT_E);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_dup2_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_dup2_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
+
+KRETPROBE("__x64_sys_dup2")
+int BPF_KRETPROBE(tdf_dup2_r, int ret) {
+  return handle_dup2_r(ctx, NULL, ret);
+}
+
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_dup2_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_dup2_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
+}
 
-  /*====================== PARAMETERS ======================*/
-  int fd = get_syscall_param(regs, 0);
-  tdf_save(&te, TDT_S32, &fd);
+KPROBE("__x64_sys_dup3")
+int BPF_KPROBE(tdf_dup3_e, struct pt_regs *regs) {
+  return handle_dup3_e(ctx, regs);
+}
 
-  int addrlen = get_syscall_param(regs, 2);
-  tdf_flex_save(&te, TDT_SOCKADDR, get_syscall_param(regs, 1), addrlen, USER);
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_dup3_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_dup3_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
 
-  tdf_save(&te, TDT_S32, &addrlen);
-  /*====================== PARAMETERS ======================*/
+KRETPROBE("__x64_sys_dup3")
+int BPF_KRETPROBE(tdf_dup3_r, int ret) {
+  return handle_dup3_r(ctx, NULL, ret);
+}
 
-  return tdf_submit_event(&te);
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_dup3_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_dup3_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
 }
 
-KRETPROBE("__x64_sys_connect")
-int BPF_KRETPROBE(tdf_connect_r, int ret) {
-  tarian_event_t te;
-  int resp = new_event(ctx, TDE_SYSCALL_CONNECT_R, &te, FIXED,  TDS_CONNECT_R);
-  if (resp != TDC_SUCCESS) {
-    stats__add(resp);
-    return resp;
-  }
-
-  /*====================== PARAMETERS ======================*/
-  tdf_save(&te, TDT_S32, &ret);
-  /*====================== PARAMETERS ======================*/
-
-  return tdf_submit_event(&te);
+KPROBE("__x64_sys_fcntl")
+int BPF_KPROBE(tdf_fcntl_e, struct pt_regs *regs) {
+  return handle_fcntl_e(ctx, regs);
+}
+
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_fcntl_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_fcntl_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
+
+KRETPROBE("__x64_sys_fcntl")
+int BPF_KRETPROBE(tdf_fcntl_r, int ret) {
+  return handle_fcntl_r(ctx, NULL, ret);
+}
+
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_fcntl_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_fcntl_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
+}
+
+KPROBE("__x64_sys_accept4")
+int BPF_KPROBE(tdf_accept4_e, struct pt_regs *regs) {
+  return handle_accept4_e(ctx, regs);
+}
+
+RAW_TRACEPOINT(sys_enter)
+int tdf_rtp_accept4_e(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_accept4_e(ctx, (struct pt_regs *)ctx->args[0]);
+}
+
+KRETPROBE("__x64_sys_accept4")
+int BPF_KRETPROBE(tdf_accept4_r, int ret) {
+  return handle_accept4_r(ctx, NULL, ret);
+}
+
+RAW_TRACEPOINT(sys_exit)
+int tdf_rtp_accept4_r(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_accept4_r(ctx, (struct pt_regs *)ctx->args[0], (int)ctx->args[1]);
+}
+
+RAW_TRACEPOINT(sched_process_fork)
+int tdf_sched_process_fork(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_sched_process_fork(ctx);
+}
+
+RAW_TRACEPOINT(sched_process_exec)
+int tdf_sched_process_exec(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_sched_process_exec(ctx);
+}
+
+RAW_TRACEPOINT(sched_process_exit)
+int tdf_sched_process_exit(struct bpf_raw_tracepoint_args *ctx) {
+  return handle_sched_process_exit(ctx);
 }
\ No newline at end of file
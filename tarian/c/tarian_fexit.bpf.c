@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+// go:build ignore
+
+#include "syscalls.h"
+
+/*
+ * fentry/fexit backend. BPF trampolines pass the entry arguments and the return
+ * value to the fexit program, so a single event is written per syscall. execve
+ * and execveat replace the user memory holding their arguments, which are
+ * therefore read on entry.
+ */
+
+#if defined(bpf_target_x86)
+#define SYSCALL_SYMBOL(__name) "__x64_sys_" #__name
+#elif defined(bpf_target_arm64)
+#define SYSCALL_SYMBOL(__name) "__arm64_sys_" #__name
+#endif
+
+#define FENTRY(__name) SEC("fentry/" SYSCALL_SYMBOL(__name))
+#define FEXIT(__name) SEC("fexit/" SYSCALL_SYMBOL(__name))
+
+FENTRY(execve)
+int BPF_PROG(tdf_fentry_execve, struct pt_regs *regs) {
+  return handle_execve_e(ctx, regs);
+}
+
+FEXIT(execve)
+int BPF_PROG(tdf_fexit_execve, struct pt_regs *regs, long ret) {
+  return handle_execve_r(ctx, regs, ret);
+}
+
+FENTRY(execveat)
+int BPF_PROG(tdf_fentry_execveat, struct pt_regs *regs) {
+  return handle_execveat_e(ctx, regs);
+}
+
+FEXIT(execveat)
+int BPF_PROG(tdf_fexit_execveat, struct pt_regs *regs, long ret) {
+  return handle_execveat_r(ctx, regs, ret);
+}
+
+FEXIT(clone)
+int BPF_PROG(tdf_fexit_clone, struct pt_regs *regs, long ret) {
+  return handle_clone(ctx, regs, ret);
+}
+
+FEXIT(close)
+int BPF_PROG(tdf_fexit_close, struct pt_regs *regs, long ret) {
+  return handle_close(ctx, regs, ret);
+}
+
+FEXIT(read)
+int BPF_PROG(tdf_fexit_read, struct pt_regs *regs, long ret) {
+  return handle_read(ctx, regs, ret);
+}
+
+FEXIT(write)
+int BPF_PROG(tdf_fexit_write, struct pt_regs *regs, long ret) {
+  return handle_write(ctx, regs, ret);
+}
+
+FEXIT(open)
+int BPF_PROG(tdf_fexit_open, struct pt_regs *regs, long ret) {
+  return handle_open(ctx, regs, ret);
+}
+
+FEXIT(readv)
+int BPF_PROG(tdf_fexit_readv, struct pt_regs *regs, long ret) {
+  return handle_readv(ctx, regs, ret);
+}
+
+FEXIT(writev)
+int BPF_PROG(tdf_fexit_writev, struct pt_regs *regs, long ret) {
+  return handle_writev(ctx, regs, ret);
+}
+
+FEXIT(openat)
+int BPF_PROG(tdf_fexit_openat, struct pt_regs *regs, long ret) {
+  return handle_openat(ctx, regs, ret);
+}
+
+FEXIT(openat2)
+int BPF_PROG(tdf_fexit_openat2, struct pt_regs *regs, long ret) {
+  return handle_openat2(ctx, regs, ret);
+}
+
+FEXIT(listen)
+int BPF_PROG(tdf_fexit_listen, struct pt_regs *regs, long ret) {
+  return handle_listen(ctx, regs, ret);
+}
+
+FEXIT(socket)
+int BPF_PROG(tdf_fexit_socket, struct pt_regs *regs, long ret) {
+  return handle_socket(ctx, regs, ret);
+}
+
+FEXIT(accept)
+int BPF_PROG(tdf_fexit_accept, struct pt_regs *regs, long ret) {
+  return handle_accept(ctx, regs, ret);
+}
+
+FEXIT(bind)
+int BPF_PROG(tdf_fexit_bind, struct pt_regs *regs, long ret) {
+  return handle_bind(ctx, regs, ret);
+}
+
+FEXIT(connect)
+int BPF_PROG(tdf_fexit_connect, struct pt_regs *regs, long ret) {
+  return handle_connect(ctx, regs, ret);
+}
+
+FEXIT(dup)
+int BPF_PROG(tdf_fexit_dup, struct pt_regs *regs, long ret) {
+  return handle_dup(ctx, regs, ret);
+}
+
+FEXIT(dup2)
+int BPF_PROG(tdf_fexit_dup2, struct pt_regs *regs, long ret) {
+  return handle_dup2(ctx, regs, ret);
+}
+
+FEXIT(dup3)
+int BPF_PROG(tdf_fexit_dup3, struct pt_regs *regs, long ret) {
+  return handle_dup3(ctx, regs, ret);
+}
+
+FEXIT(fcntl)
+int BPF_PROG(tdf_fexit_fcntl, struct pt_regs *regs, long ret) {
+  return handle_fcntl(ctx, regs, ret);
+}
+
+FEXIT(accept4)
+int BPF_PROG(tdf_fexit_accept4, struct pt_regs *regs, long ret) {
+  return handle_accept4(ctx, regs, ret);
+}
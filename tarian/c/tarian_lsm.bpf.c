@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+// go:build ignore
+
+#include "lsm.h"
+
+/*
+ * Every lsm hook is handled by two programs, only one of which is loaded:
+ *  - a bpf lsm program (tdf_lsm_<hook>), which denies the operations matching
+ *    an enforced policy. Requires the bpf lsm to be enabled, i.e. lsm=bpf.
+ *  - a kprobe on the security_* function calling the lsm hook
+ *    (tdf_lsm_<hook>_kprobe), which only reports the matching operations.
+ */
+
+#define LSM(__hook) SEC("lsm/" #__hook)
+
+LSM(bprm_check_security)
+int BPF_PROG(tdf_lsm_bprm_check_security, struct linux_binprm *bprm, int ret) {
+  /* denied by a previous lsm */
+  if (ret != 0)
+    return ret;
+
+  return handle_bprm_check_security(ctx, bprm, true);
+}
+
+KPROBE(security_bprm_check)
+int BPF_KPROBE(tdf_lsm_bprm_check_security_kprobe, struct linux_binprm *bprm) {
+  handle_bprm_check_security(ctx, bprm, false);
+
+  return 0;
+}
+
+LSM(file_open)
+int BPF_PROG(tdf_lsm_file_open, struct file *file, int ret) {
+  if (ret != 0)
+    return ret;
+
+  return handle_file_open(ctx, file, true);
+}
+
+KPROBE(security_file_open)
+int BPF_KPROBE(tdf_lsm_file_open_kprobe, struct file *file) {
+  handle_file_open(ctx, file, false);
+
+  return 0;
+}
+
+LSM(socket_connect)
+int BPF_PROG(tdf_lsm_socket_connect, struct socket *sock, struct sockaddr *address, int addrlen, int ret) {
+  if (ret != 0)
+    return ret;
+
+  return handle_socket_connect(ctx, address, addrlen, true);
+}
+
+KPROBE(security_socket_connect)
+int BPF_KPROBE(tdf_lsm_socket_connect_kprobe, struct socket *sock, struct sockaddr *address, int addrlen) {
+  handle_socket_connect(ctx, address, addrlen, false);
+
+  return 0;
+}
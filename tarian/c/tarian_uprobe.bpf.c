@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+// go:build ignore
+
+#include "readline.h"
+#include "tls.h"
+
+/*
+ * uprobes on userspace libraries, attached per executable as the libraries
+ * are discovered in the running processes.
+ */
+
+UPROBE(SSL_write)
+int tdf_uprobe_ssl_write(struct pt_regs *ctx) {
+  return handle_ssl_write(ctx);
+}
+
+UPROBE(SSL_read)
+int tdf_uprobe_ssl_read(struct pt_regs *ctx) {
+  return handle_ssl_read_e(ctx);
+}
+
+URETPROBE(SSL_read)
+int tdf_uretprobe_ssl_read(struct pt_regs *ctx) {
+  return handle_ssl_read_r(ctx);
+}
+
+UPROBE(crypto/tls.(*Conn).Write)
+int tdf_uprobe_go_tls_write(struct pt_regs *ctx) {
+  return handle_go_tls_write(ctx);
+}
+
+UPROBE(crypto/tls.(*Conn).Read)
+int tdf_uprobe_go_tls_read(struct pt_regs *ctx) {
+  return handle_go_tls_read_e(ctx);
+}
+
+/* attached on every RET instruction of crypto/tls.(*Conn).Read */
+UPROBE(crypto/tls.(*Conn).Read)
+int tdf_uprobe_go_tls_read_ret(struct pt_regs *ctx) {
+  return handle_go_tls_read_r(ctx);
+}
+
+URETPROBE(readline)
+int tdf_uretprobe_readline(struct pt_regs *ctx) {
+  return handle_readline_r(ctx);
+}
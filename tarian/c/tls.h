@@ -0,0 +1,121 @@
+#ifndef __TLS_H__
+#define __TLS_H__
+
+#include "common.h"
+
+/*
+ * Plaintext of the tls connections, captured on the userspace tls libraries:
+ *  - OpenSSL's SSL_write & SSL_read (libssl)
+ *  - Go's crypto/tls.(*Conn).Write & crypto/tls.(*Conn).Read
+ * The written data is read on entry. The read data is only available on return,
+ * the buffer is therefore stored on entry and read on return.
+ */
+
+#define MAX_TLS_READS 10240
+
+typedef struct tls_key {
+  u64 id; /* pid_tgid for libssl, tgid in the upper 32 bits for go */
+  u64 g;  /* goroutine for go, zero for libssl */
+} tls_key_t; /* 16B */
+
+BPF_LRU_HASH(tls_reads, tls_key_t, u64, MAX_TLS_READS);
+
+stain int tls_submit(void *ctx, int event, unsigned long buf, int32_t len) {
+  if (len <= 0)
+    return 0;
+
+  tarian_event_t te;
+  int resp = new_event(ctx, event, &te, VARIABLE, TDS_TLS);
+  if (resp != TDC_SUCCESS) {
+    stats__add(resp);
+    return resp;
+  }
+
+  uint32_t n = len < MAX_STRING_SIZE ? len : MAX_STRING_SIZE;
+
+  /*====================== PARAMETERS ======================*/
+  tdf_flex_save(&te, TDT_BYTE_ARR, buf, n, USER);
+  tdf_save(&te, TDT_S32, &len /* len */);
+  /*====================== PARAMETERS ======================*/
+
+  return tdf_submit_event(&te);
+}
+
+stain void tls_read_store(tls_key_t *key, u64 buf) {
+  bpf_map_update_elem(&tls_reads, key, &buf, BPF_ANY);
+}
+
+stain int tls_read_submit(void *ctx, int event, tls_key_t *key, int32_t len) {
+  u64 *buf = bpf_map_lookup_elem(&tls_reads, key);
+  if (!buf)
+    return 0;
+
+  unsigned long ptr = *buf;
+  bpf_map_delete_elem(&tls_reads, key);
+
+  return tls_submit(ctx, event, ptr, len);
+}
+
+/*====================== openssl ======================*/
+
+stain tls_key_t ssl_key() {
+  tls_key_t key = {};
+  key.id = bpf_get_current_pid_tgid();
+
+  return key;
+}
+
+/* int SSL_write(SSL *ssl, const void *buf, int num) */
+stain int handle_ssl_write(struct pt_regs *ctx) {
+  return tls_submit(ctx, TDE_SSL_WRITE, PT_REGS_PARM2(ctx), PT_REGS_PARM3(ctx));
+}
+
+/* int SSL_read(SSL *ssl, void *buf, int num) */
+stain int handle_ssl_read_e(struct pt_regs *ctx) {
+  tls_key_t key = ssl_key();
+  tls_read_store(&key, PT_REGS_PARM2(ctx));
+
+  return 0;
+}
+
+stain int handle_ssl_read_r(struct pt_regs *ctx) {
+  tls_key_t key = ssl_key();
+
+  return tls_read_submit(ctx, TDE_SSL_READ, &key, PT_REGS_RC(ctx));
+}
+
+/*====================== go crypto/tls ======================*/
+
+/*
+ * goroutines move between threads and their stacks are moved by the runtime,
+ * so calls are keyed by goroutine and the returns are probed on the RET
+ * instructions of the function: uretprobes corrupt the moved stacks.
+ */
+stain tls_key_t go_tls_key(struct pt_regs *ctx) {
+  tls_key_t key = {};
+  key.id = bpf_get_current_pid_tgid() & 0xffffffff00000000;
+  key.g = GO_REGS_G_CORE(ctx);
+
+  return key;
+}
+
+/* func (c *Conn) Write(b []byte) (int, error) */
+stain int handle_go_tls_write(struct pt_regs *ctx) {
+  return tls_submit(ctx, TDE_GO_TLS_WRITE, GO_REGS_PARM2_CORE(ctx), GO_REGS_PARM3_CORE(ctx));
+}
+
+/* func (c *Conn) Read(b []byte) (int, error) */
+stain int handle_go_tls_read_e(struct pt_regs *ctx) {
+  tls_key_t key = go_tls_key(ctx);
+  tls_read_store(&key, GO_REGS_PARM2_CORE(ctx));
+
+  return 0;
+}
+
+stain int handle_go_tls_read_r(struct pt_regs *ctx) {
+  tls_key_t key = go_tls_key(ctx);
+
+  return tls_read_submit(ctx, TDE_GO_TLS_READ, &key, GO_REGS_RC_CORE(ctx));
+}
+
+#endif
@@ -4,16 +4,26 @@
 #include "index.h"
 
 stain int new_event(void *, int, tarian_event_t *, enum allocation_type,int);
+stain int new_syscall_event(void *, struct pt_regs *, int, tarian_event_t *, enum allocation_type, int);
 stain int init_tarian_meta_data_t(tarian_event_t *, int);
 stain int init_task_meta_data_t(tarian_event_t *);
 stain int init_event_meta_data_t(tarian_event_t *, int);
 stain int read_node_info_into(node_meta_data_t *ni, struct task_struct *t);
 
 stain int new_event(void *ctx, int tarian_event, tarian_event_t *te, enum allocation_type at,int req_buf_sz) {
+  return new_syscall_event(ctx, NULL, tarian_event, te, at, req_buf_sz);
+};
+
+/* regs is the syscall register context, it is independent of the hook type of ctx */
+stain int new_syscall_event(void *ctx, struct pt_regs *regs, int tarian_event, tarian_event_t *te, enum allocation_type at, int req_buf_sz) {
+  /* paused events are neither counted nor written */
+  if (is_paused(tarian_event)) return TDC_PAUSED;
+
   stats__add_trigger();
 
   te->allocation_mode = 0;
   te->ctx = ctx;
+  te->regs = regs;
   te->task = (struct task_struct *)bpf_get_current_task();
   
   scratch_space_t *ss = get__scratch_space();
@@ -29,7 +39,10 @@ stain int new_event(void *ctx, int tarian_event, tarian_event_t *te, enum alloca
   };
 
   resp = init_tarian_meta_data_t(te, tarian_event);
-  if (resp != TDC_SUCCESS) return resp;
+  if (resp != TDC_SUCCESS) {
+    tdf_discard_event(te);
+    return resp;
+  }
   
   uint32_t len = 0;
   u8 *filepath = get__cwd_d_path(&len, ss, te->task);
@@ -40,6 +53,7 @@ stain int new_event(void *ctx, int tarian_event, tarian_event_t *te, enum alloca
 
   resp = flush(te->tarian->meta_data.task.cwd, sizeof(te->tarian->meta_data.task.cwd));
   if (resp != TDC_SUCCESS) {
+    tdf_discard_event(te);
     return resp;
   }
   bpf_probe_read_kernel_str(te->tarian->meta_data.task.cwd, len & (MAX_TARIAN_PATH - 1), filepath);
@@ -63,12 +77,8 @@ stain int init_event_meta_data_t(tarian_event_t *te, int event) {
     em->ts = bpf_ktime_get_ns();
     em->event = event;
     em->nparams = 0;
-#if LINUX_VERSION_CODE < KERNEL_VERSION(4, 17, 0)
-    em->syscall = get_syscall_id(te->ctx);
-#else
-    struct pt_regs *regs = PT_REGS_SYSCALL_REGS(te->ctx);
-    em->syscall = get_syscall_id(regs);
-#endif
+    /* -1 if the syscall is unknown, e.g. on return probes */
+    em->syscall = te->regs ? get_syscall_id(te->regs) : -1;
     em->processor = (uint16_t)bpf_get_smp_processor_id();
 
     return init_task_meta_data_t(te);
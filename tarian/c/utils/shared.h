@@ -8,24 +8,75 @@ char LICENSE[] SEC("license") = "Dual MIT/GPL";
 
 #define KPROBE(__hook) SEC("kprobe/" #__hook)
 #define KRETPROBE(__hook) SEC("kprobe/" #__hook)
+#define RAW_TRACEPOINT(__hook) SEC("raw_tracepoint/" #__hook)
+#define UPROBE(__hook) SEC("uprobe/" #__hook)
+#define URETPROBE(__hook) SEC("uretprobe/" #__hook)
 
 #if defined(bpf_target_x86)
 #define __PT_PARM6_REG r9
-#define __PT_SYSCALL_ID orig_ax
+#define PT_REGS_SYSCALL_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), orig_ax)
+
+/* go register based calling convention (go1.17+): arguments & results in ax, bx, cx, ..., g in r14 */
+#define GO_REGS_PARM1_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), ax)
+#define GO_REGS_PARM2_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), bx)
+#define GO_REGS_PARM3_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), cx)
+#define GO_REGS_G_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), r14)
 #elif defined(bpf_target_arm64)
+/*
+ * headers/vmlinux.h is generated on x86, so the arm64 register layouts are
+ * declared here. Field offsets are relocated against the running kernel's BTF.
+ */
+struct user_pt_regs {
+  __u64 regs[31];
+  __u64 sp;
+  __u64 pc;
+  __u64 pstate;
+} __attribute__((preserve_access_index));
+
+struct pt_regs___tarian_arm64 {
+  __s32 syscallno;
+} __attribute__((preserve_access_index));
+
 #define __PT_PARM6_REG regs[5]
-#define __PT_SYSCALL_ID syscallno
+#define PT_REGS_SYSCALL_CORE(x) BPF_CORE_READ((const struct pt_regs___tarian_arm64 *)(x), syscallno)
+
+/* go register based calling convention (go1.18+): arguments & results in r0, r1, r2, ..., g in r28 */
+#define GO_REGS_PARM1_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), regs[0])
+#define GO_REGS_PARM2_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), regs[1])
+#define GO_REGS_PARM3_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), regs[2])
+#define GO_REGS_G_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), regs[28])
+#else
+#error "tarian: unsupported target architecture, only x86 and arm64 are supported"
+#endif
+
+#if defined(bpf_target_arm64)
+/* arm64 selects CONFIG_CLONE_BACKWARDS: clone(flags, newsp, parent_tid, tls, child_tid) */
+#define CLONE_TLS_PARAM 3
+#define CLONE_CHILD_TID_PARAM 4
+#else
+#define CLONE_CHILD_TID_PARAM 3
+#define CLONE_TLS_PARAM 4
 #endif
 
 #define PT_REGS_PARM6_CORE(x) BPF_CORE_READ(__PT_REGS_CAST(x), __PT_PARM6_REG)
 #define PT_REGS_PARM6_CORE_SYSCALL(x) PT_REGS_PARM6_CORE(x)
-#define PT_REGS_SYSCALL_CORE(x)                                                \
-  BPF_CORE_READ(__PT_REGS_CAST(x), __PT_SYSCALL_ID)
+
+/* the first result shares the register of the first argument */
+#define GO_REGS_RC_CORE(x) GO_REGS_PARM1_CORE(x)
 
 stain uint32_t get_syscall_id(struct pt_regs *regs) {
   return (uint32_t)PT_REGS_SYSCALL_CORE(regs);
 };
 
+/* reports whether the task runs in compat (32 bit) mode */
+stain bool is_compat_task(struct task_struct *task) {
+#if defined(bpf_target_x86)
+  return BPF_CORE_READ(task, thread_info.status) & TS_COMPAT;
+#elif defined(bpf_target_arm64)
+  return BPF_CORE_READ(task, thread_info.flags) & (1UL << TIF_32BIT);
+#endif
+}
+
 stain unsigned long get_syscall_param(struct pt_regs *regs, int idx) {
   unsigned long param = 0;
 
@@ -3,6 +3,7 @@
 
 #define TDC_SUCCESS 100
 #define TDC_FAILURE 101
+#define TDC_PAUSED 102
 
 #define TDCE_RESERVE_SPACE 400
 #define TDCE_NULL_POINTER 401
@@ -11,25 +11,30 @@
 
 #define TASK_COMM_LEN 16
 
-#ifndef LINUX_VERSION_CODE
-#define LINUX_VERSION_CODE KERNEL_VERSION(LINUX_VERSION_MAJOR, LINUX_VERSION_MINOR, LINUX_VERSION_PATCH)
-#endif
-
+#define AF_UNSPEC 0
 #define AF_UNIX 1
 #define AF_INET 2
 #define AF_INET6 10
 
 #define EVENT_RINGBUF_MAP_NAME events
-#define RINGBUF_MAX_ENTRIES 1024 * 1024 * 128 /* 128MB */
+#define RINGBUF_MAX_ENTRIES 1024 * 256 /* 256kB per cpu, resized at load time */
 #define ARRAY_OF_MAPS_MAX_ENTRIES 16
 
 #define stain static __always_inline
 
-#if(LINUX_VERSION_CODE >= KERNEL_VERSION(5, 2, 0))
+/* upper bound of the syscall numbers on the supported architectures */
+#define MAX_SYSCALL_ID 512
+
+/* upper bound of the event codes, see tarian_event_code */
+#define MAX_TARIAN_EVENTS 128
+
+#define EPERM 1 /* operation not permitted */
+
+#define TS_COMPAT 0x0002 /* x86: thread_info.status, 32 bit syscall */
+#define TIF_32BIT 22     /* arm64: thread_info.flags, 32 bit process */
+
+/* global data (used for runtime configuration) requires 5.2+, where the 1M instruction limit applies */
 #define MAX_NUM_COMPONENTS 48
-#else
-#define MAX_NUM_COMPONENTS 24
-#endif
 
 enum tarian_param_type_e{
     TDT_NONE = 0,
@@ -112,11 +117,74 @@ typedef enum tarian_events_e{
     // connect
     TDE_SYSCALL_CONNECT_E,
     TDE_SYSCALL_CONNECT_R,
+
+    // entry arguments and return value in one event
+    TDE_SYSCALL_CLONE = 34,
+    TDE_SYSCALL_CLOSE,
+    TDE_SYSCALL_READ,
+    TDE_SYSCALL_WRITE,
+    TDE_SYSCALL_OPEN,
+    TDE_SYSCALL_READV,
+    TDE_SYSCALL_WRITEV,
+    TDE_SYSCALL_OPENAT,
+    TDE_SYSCALL_OPENAT2,
+    TDE_SYSCALL_LISTEN,
+    TDE_SYSCALL_SOCKET,
+    TDE_SYSCALL_ACCEPT,
+    TDE_SYSCALL_BIND,
+    TDE_SYSCALL_CONNECT,
+
+    // policy matches of the lsm hooks
+    TDE_LSM_BPRM_CHECK_SECURITY = 48,
+    TDE_LSM_FILE_OPEN,
+    TDE_LSM_SOCKET_CONNECT,
+
+    // plaintext of the tls libraries
+    TDE_SSL_WRITE = 51,
+    TDE_SSL_READ,
+    TDE_GO_TLS_WRITE,
+    TDE_GO_TLS_READ,
+
+    // line read by an interactive shell
+    TDE_READLINE = 55,
+
+    // process lifecycle on the sched tracepoints
+    TDE_SCHED_PROCESS_FORK = 56,
+    TDE_SCHED_PROCESS_EXEC,
+    TDE_SCHED_PROCESS_EXIT,
+
+    // dup
+    TDE_SYSCALL_DUP_E = 59,
+    TDE_SYSCALL_DUP_R,
+
+    // dup2
+    TDE_SYSCALL_DUP2_E,
+    TDE_SYSCALL_DUP2_R,
+
+    // dup3
+    TDE_SYSCALL_DUP3_E,
+    TDE_SYSCALL_DUP3_R,
+
+    // fcntl
+    TDE_SYSCALL_FCNTL_E,
+    TDE_SYSCALL_FCNTL_R,
+
+    // accept4
+    TDE_SYSCALL_ACCEPT4_E,
+    TDE_SYSCALL_ACCEPT4_R,
+
+    // entry arguments and return value in one event
+    TDE_SYSCALL_DUP = 69,
+    TDE_SYSCALL_DUP2,
+    TDE_SYSCALL_DUP3,
+    TDE_SYSCALL_FCNTL,
+    TDE_SYSCALL_ACCEPT4,
 } tarian_event_code;
 
 /*****Event Data Size - START****/
 #define MD_SIZE sizeof(tarian_meta_data_t) /* sizeof tarian meta data for each event*/
 #define PARAM_SIZE sizeof(uint16_t)
+#define TDS_SOCK_PEER (sizeof(uint8_t) + sizeof(uint32_t) * 4 + sizeof(uint16_t)) /* family, ipv6 address and port of a peer */
 
 #define TDS_EXECVE_E (MD_SIZE + MAX_STRING_SIZE*2 + PARAM_SIZE*2)
 #define TDS_EXECVE_R (MD_SIZE + sizeof(int32_t))
@@ -158,13 +226,63 @@ typedef enum tarian_events_e{
 #define TDS_SOCKET_R (MD_SIZE + sizeof(int32_t))
 
 #define TDS_ACCEPT_E (MD_SIZE + sizeof(int32_t) * 2 + MAX_UNIX_SOCKET_PATH + PARAM_SIZE)
-#define TDS_ACCEPT_R (MD_SIZE + sizeof(int32_t))
+#define TDS_ACCEPT_R (MD_SIZE + sizeof(int32_t) + TDS_SOCK_PEER)
 
 #define TDS_BIND_E (MD_SIZE + sizeof(int32_t) * 2 +  MAX_UNIX_SOCKET_PATH + PARAM_SIZE)
 #define TDS_BIND_R (MD_SIZE + sizeof(int32_t))
 
 #define TDS_CONNECT_E (MD_SIZE + sizeof(int32_t) * 2 +  MAX_UNIX_SOCKET_PATH + PARAM_SIZE)
 #define TDS_CONNECT_R (MD_SIZE + sizeof(int32_t))
+
+#define TDS_DUP_E (MD_SIZE + sizeof(int32_t))
+#define TDS_DUP_R (MD_SIZE + sizeof(int32_t))
+
+#define TDS_DUP2_E (MD_SIZE + sizeof(int32_t) * 2)
+#define TDS_DUP2_R (MD_SIZE + sizeof(int32_t))
+
+#define TDS_DUP3_E (MD_SIZE + sizeof(int32_t) * 3)
+#define TDS_DUP3_R (MD_SIZE + sizeof(int32_t))
+
+#define TDS_FCNTL_E (MD_SIZE + sizeof(int32_t) * 2 + sizeof(uint64_t))
+#define TDS_FCNTL_R (MD_SIZE + sizeof(int32_t))
+
+#define TDS_ACCEPT4_E (MD_SIZE + sizeof(int32_t) * 3 + MAX_UNIX_SOCKET_PATH + PARAM_SIZE)
+#define TDS_ACCEPT4_R (MD_SIZE + sizeof(int32_t) + TDS_SOCK_PEER)
+
+/* entry arguments and return value in one event */
+#define TDS_CLONE (TDS_CLONE_E + TDS_CLONE_R - MD_SIZE)
+#define TDS_CLOSE (TDS_CLOSE_E + TDS_CLOSE_R - MD_SIZE)
+#define TDS_READ (TDS_READ_E + TDS_READ_R - MD_SIZE)
+#define TDS_WRITE (TDS_WRITE_E + TDS_WRITE_R - MD_SIZE)
+#define TDS_OPEN (TDS_OPEN_E + TDS_OPEN_R - MD_SIZE)
+#define TDS_READV (TDS_READV_E + TDS_READV_R - MD_SIZE)
+#define TDS_WRITEV (TDS_WRITEV_E + TDS_WRITEV_R - MD_SIZE)
+#define TDS_OPENAT (TDS_OPENAT_E + TDS_OPENAT_R - MD_SIZE)
+#define TDS_OPENAT2 (TDS_OPENAT2_E + TDS_OPENAT2_R - MD_SIZE)
+#define TDS_LISTEN (TDS_LISTEN_E + TDS_LISTEN_R - MD_SIZE)
+#define TDS_SOCKET (TDS_SOCKET_E + TDS_SOCKET_R - MD_SIZE)
+#define TDS_ACCEPT (TDS_ACCEPT_E + TDS_ACCEPT_R - MD_SIZE)
+#define TDS_BIND (TDS_BIND_E + TDS_BIND_R - MD_SIZE)
+#define TDS_CONNECT (TDS_CONNECT_E + TDS_CONNECT_R - MD_SIZE)
+#define TDS_DUP (TDS_DUP_E + TDS_DUP_R - MD_SIZE)
+#define TDS_DUP2 (TDS_DUP2_E + TDS_DUP2_R - MD_SIZE)
+#define TDS_DUP3 (TDS_DUP3_E + TDS_DUP3_R - MD_SIZE)
+#define TDS_FCNTL (TDS_FCNTL_E + TDS_FCNTL_R - MD_SIZE)
+#define TDS_ACCEPT4 (TDS_ACCEPT4_E + TDS_ACCEPT4_R - MD_SIZE)
+
+/* policy id & action followed by the operation */
+#define TDS_LSM_BPRM_CHECK_SECURITY (MD_SIZE + sizeof(uint32_t) + sizeof(uint8_t) + MAX_STRING_SIZE + PARAM_SIZE)
+#define TDS_LSM_FILE_OPEN (MD_SIZE + sizeof(uint32_t) + sizeof(uint8_t) + sizeof(uint32_t) + sizeof(uint64_t) + MAX_STRING_SIZE + PARAM_SIZE)
+#define TDS_LSM_SOCKET_CONNECT (MD_SIZE + sizeof(uint32_t) + sizeof(uint8_t) + MAX_UNIX_SOCKET_PATH + PARAM_SIZE)
+
+/* truncated plaintext followed by its length */
+#define TDS_TLS (MD_SIZE + MAX_STRING_SIZE + PARAM_SIZE + sizeof(int32_t))
+
+#define TDS_READLINE (MD_SIZE + MAX_STRING_SIZE + PARAM_SIZE)
+
+#define TDS_SCHED_PROCESS_FORK (MD_SIZE + sizeof(uint32_t) * 2 + sizeof(uint64_t))
+#define TDS_SCHED_PROCESS_EXEC (MD_SIZE + MAX_STRING_SIZE + PARAM_SIZE + sizeof(int32_t))
+#define TDS_SCHED_PROCESS_EXIT (MD_SIZE + sizeof(int32_t) + sizeof(uint16_t) + sizeof(uint8_t) * 2)
 /*****Event Data Size - END*****/
 
 #endif
\ No newline at end of file
@@ -19,6 +19,10 @@
 #define BPF_PERCPU_ARRAY(_map_name, _value_type, _max_entries)                 \
   BPF_MAP(_map_name, BPF_MAP_TYPE_PERCPU_ARRAY, u32, _value_type, _max_entries);
 
+// Hash map
+#define BPF_HASH(_map_name, _key_type, _value_type, _max_entries)              \
+  BPF_MAP(_map_name, BPF_MAP_TYPE_HASH, _key_type, _value_type, _max_entries);
+
 // LRU Hash map
 #define BPF_LRU_HASH(_map_name, _key_type, _value_type, _max_entries)          \
   BPF_MAP(_map_name, BPF_MAP_TYPE_LRU_HASH, _key_type, _value_type,            \
@@ -85,128 +89,171 @@ stain void *map__allocate_space(void *map) {
   return get__current_cpu_buf(map);
 };
 
-#if LINUX_VERSION_CODE >= KERNEL_VERSION(5, 8, 0) && false
-  /*
-  *
-  * RINGBUF
-  * This map is used for sending events to
-  * userspace
-  *
-  */
-  struct ringbuf {
-    __uint(type, BPF_MAP_TYPE_RINGBUF);
-    __uint(max_entries, RINGBUF_MAX_ENTRIES);
-  };
-
-  #define BPF_RINGBUF(_map_name) struct ringbuf _map_name SEC(".maps");
-
-  BPF_RINGBUF(erb_cpu0);
-  BPF_RINGBUF(erb_cpu1);
-  BPF_RINGBUF(erb_cpu2);
-  BPF_RINGBUF(erb_cpu3);
-  BPF_RINGBUF(erb_cpu4);
-  BPF_RINGBUF(erb_cpu5);
-  BPF_RINGBUF(erb_cpu6);
-  BPF_RINGBUF(erb_cpu7);
-  BPF_RINGBUF(erb_cpu8);
-  BPF_RINGBUF(erb_cpu9);
-  BPF_RINGBUF(erb_cpu10);
-  BPF_RINGBUF(erb_cpu11);
-  BPF_RINGBUF(erb_cpu12);
-  BPF_RINGBUF(erb_cpu13);
-  BPF_RINGBUF(erb_cpu14);
-  BPF_RINGBUF(erb_cpu15);
-
-  struct {
-    __uint(type, BPF_MAP_TYPE_ARRAY_OF_MAPS);
-    __uint(max_entries, ARRAY_OF_MAPS_MAX_ENTRIES);
-    __type(key, u32);
-    __array(values, struct ringbuf);
-  } events SEC(".maps") = {.values = {
-                              &erb_cpu0,
-                              &erb_cpu1,
-                              &erb_cpu2,
-                              &erb_cpu3,
-                              &erb_cpu4,
-                              &erb_cpu5,
-                              &erb_cpu6,
-                              &erb_cpu7,
-                              &erb_cpu8,
-                              &erb_cpu9,
-                              &erb_cpu10,
-                              &erb_cpu11,
-                              &erb_cpu12,
-                              &erb_cpu13,
-                              &erb_cpu14,
-                              &erb_cpu15,
-                          }};
-
-  stain struct ringbuffer *get_cpu_ringbuffer(void *map) {
-    uint32_t cpu_id = (uint32_t)bpf_get_smp_processor_id();
-	  return (struct ringbuffer *)bpf_map_lookup_elem(map, &cpu_id);
-  }
-
-  stain void *map__reserve_space(void *map, u64 size) {
-    struct ringbuffer *rbuf = get_cpu_ringbuffer(map);
-    if (!rbuf) return NULL;
-
-    return bpf_ringbuf_reserve(rbuf, size, 0);
-  };
-
-  stain int map__reserve_submit(void *data) {
-    if (!data) return TDCE_NULL_POINTER;
-    
-    bpf_ringbuf_submit(data, 0);
-
-    return TDC_SUCCESS;
-  };
-
-  stain int map__submit(void *map, void *data, u64 size) {
-    if (!map || !data) return TDCE_NULL_POINTER;
-    
-    if (bpf_ringbuf_output(map, data, size, 0) != 0) return TDCE_MAP_SUBMIT;
-
-    return TDC_SUCCESS;
-  }
-
-  stain int map__pringbuf_submit(void *map, void *data, u64 size) {
-    if (!data) return TDCE_NULL_POINTER;
-    
-    struct ringbuffer *rbuf = get_cpu_ringbuffer(map);
-    if (!rbuf) return TDCE_MAP_SUBMIT;
-
-    return map__submit(rbuf, data, size);
-  }
-
-  stain int map__discard(void *data) {
-    if (!data) return TDCE_NULL_POINTER;
-    
-    bpf_ringbuf_discard(data, 0);
-
-    return TDC_SUCCESS;
-  };
-
-#else
-  /*
-  * 
-  * PERF_EVENT_ARRAY
-  * This map is used as an fallback map 
-  * on kernel version which do not support ringbuf
-  * 
-  */
-  struct {
-    __uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
-    __uint(key_size, sizeof(int));
-    __uint(value_size, sizeof(u32));
-  } events SEC(".maps");
-
-  stain int map__submit(void *ctx, void *map, void *data, u64 size) {
-    if (!map || !data) return TDCE_NULL_POINTER;
-
-    if (bpf_perf_event_output(ctx, map, BPF_F_CURRENT_CPU, data, size) != 0) return TDCE_MAP_SUBMIT;
-
-    return TDC_SUCCESS;
-  };
-#endif
+/*
+*
+* Transport selection
+* Set from userspace before the programs are loaded. When the running
+* kernel supports BPF_MAP_TYPE_RINGBUF, events are sent through the per cpu
+* ring buffers, otherwise through the perf event array. The verifier prunes
+* the branch that is not taken.
+*
+*/
+const volatile bool use_ringbuf = false;
+
+/*
+*
+* RINGBUF
+* This map is used for sending events to
+* userspace
+*
+*/
+struct ringbuf {
+  __uint(type, BPF_MAP_TYPE_RINGBUF);
+  __uint(max_entries, RINGBUF_MAX_ENTRIES);
+};
+
+#define BPF_RINGBUF(_map_name) struct ringbuf _map_name SEC(".maps");
+
+BPF_RINGBUF(erb_cpu0);
+BPF_RINGBUF(erb_cpu1);
+BPF_RINGBUF(erb_cpu2);
+BPF_RINGBUF(erb_cpu3);
+BPF_RINGBUF(erb_cpu4);
+BPF_RINGBUF(erb_cpu5);
+BPF_RINGBUF(erb_cpu6);
+BPF_RINGBUF(erb_cpu7);
+BPF_RINGBUF(erb_cpu8);
+BPF_RINGBUF(erb_cpu9);
+BPF_RINGBUF(erb_cpu10);
+BPF_RINGBUF(erb_cpu11);
+BPF_RINGBUF(erb_cpu12);
+BPF_RINGBUF(erb_cpu13);
+BPF_RINGBUF(erb_cpu14);
+BPF_RINGBUF(erb_cpu15);
+
+struct {
+  __uint(type, BPF_MAP_TYPE_ARRAY_OF_MAPS);
+  __uint(max_entries, ARRAY_OF_MAPS_MAX_ENTRIES);
+  __type(key, u32);
+  __array(values, struct ringbuf);
+} events_ringbuf SEC(".maps") = {.values = {
+                                     &erb_cpu0,
+                                     &erb_cpu1,
+                                     &erb_cpu2,
+                                     &erb_cpu3,
+                                     &erb_cpu4,
+                                     &erb_cpu5,
+                                     &erb_cpu6,
+                                     &erb_cpu7,
+                                     &erb_cpu8,
+                                     &erb_cpu9,
+                                     &erb_cpu10,
+                                     &erb_cpu11,
+                                     &erb_cpu12,
+                                     &erb_cpu13,
+                                     &erb_cpu14,
+                                     &erb_cpu15,
+                                 }};
+
+stain void *get_cpu_ringbuffer(void *map) {
+  uint32_t cpu_id = (uint32_t)bpf_get_smp_processor_id();
+  return bpf_map_lookup_elem(map, &cpu_id);
+}
+
+stain void *map__reserve_space(void *map, u64 size) {
+  void *rbuf = get_cpu_ringbuffer(map);
+  if (!rbuf) return NULL;
+
+  return bpf_ringbuf_reserve(rbuf, size, 0);
+};
+
+stain int map__reserve_submit(void *data) {
+  if (!data) return TDCE_NULL_POINTER;
+
+  bpf_ringbuf_submit(data, 0);
+
+  return TDC_SUCCESS;
+};
+
+stain int map__ringbuf_output(void *map, void *data, u64 size) {
+  if (!map || !data) return TDCE_NULL_POINTER;
+
+  if (bpf_ringbuf_output(map, data, size & (MAX_EVENT_SIZE - 1), 0) != 0) return TDCE_MAP_SUBMIT;
+
+  return TDC_SUCCESS;
+}
+
+stain int map__pringbuf_submit(void *map, void *data, u64 size) {
+  if (!data) return TDCE_NULL_POINTER;
+
+  void *rbuf = get_cpu_ringbuffer(map);
+  if (!rbuf) return TDCE_MAP_SUBMIT;
+
+  return map__ringbuf_output(rbuf, data, size);
+}
+
+stain int map__discard(void *data) {
+  if (!data) return TDCE_NULL_POINTER;
+
+  bpf_ringbuf_discard(data, 0);
+
+  return TDC_SUCCESS;
+};
+
+/*
+*
+* PROG_ARRAY
+* syscall handlers of the raw tracepoint backend indexed by syscall number,
+* populated from userspace
+*
+*/
+struct {
+  __uint(type, BPF_MAP_TYPE_PROG_ARRAY);
+  __uint(max_entries, MAX_SYSCALL_ID);
+  __type(key, u32);
+  __type(value, u32);
+} sys_enter_calls SEC(".maps"), sys_exit_calls SEC(".maps");
+
+/*
+*
+* ARRAY
+* events paused from userspace indexed by event code. The programs
+* writing a paused event stay attached but skip the event.
+*
+*/
+struct {
+  __uint(type, BPF_MAP_TYPE_ARRAY);
+  __uint(max_entries, MAX_TARIAN_EVENTS);
+  __type(key, u32);
+  __type(value, u8);
+} paused_events SEC(".maps");
+
+stain bool is_paused(int event) {
+  u32 key = event;
+  u8 *paused = bpf_map_lookup_elem(&paused_events, &key);
+
+  return paused && *paused;
+}
+
+/*
+*
+* PERF_EVENT_ARRAY
+* This map is used as an fallback map
+* on kernel version which do not support ringbuf
+*
+*/
+struct {
+  __uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
+  __uint(key_size, sizeof(int));
+  __uint(value_size, sizeof(u32));
+} events SEC(".maps");
+
+stain int map__submit(void *ctx, void *map, void *data, u64 size) {
+  if (!map || !data) return TDCE_NULL_POINTER;
+
+  if (bpf_perf_event_output(ctx, map, BPF_F_CURRENT_CPU, data, size) != 0) return TDCE_MAP_SUBMIT;
+
+  return TDC_SUCCESS;
+};
 
 #endif
\ No newline at end of file
@@ -64,4 +64,19 @@ stain struct task_struct *get_task_parent(struct task_struct *task) {
   return BPF_CORE_READ(task, parent);
 }
 
+// task->files->fdt->fd[fd]->private_data->sk, the sock of the socket fd
+stain struct sock *get_task_fd_sock(struct task_struct *task, int fd) {
+  if (fd < 0)
+    return NULL;
+
+  struct file **fds = BPF_CORE_READ(task, files, fdt, fd);
+
+  struct file *file = NULL;
+  if (bpf_probe_read_kernel(&file, sizeof(file), &fds[fd]) != 0 || !file)
+    return NULL;
+
+  struct socket *socket = BPF_CORE_READ(file, private_data);
+  return BPF_CORE_READ(socket, sk);
+}
+
 #endif
@@ -83,9 +83,10 @@ typedef struct event {
   struct task_struct *task;
 
   struct pt_regs *ctx; /* pointer to register context */
+  struct pt_regs *regs; /* pointer to the syscall register context, NULL if unavailable */
   tarian_meta_data_t *tarian;
   event_buffer_t buf;
-} tarian_event_t; /* 56B */
+} tarian_event_t; /* 64B */
 
 typedef struct tarian_stats {
   /* count of times the tarian detector hook was triggered,
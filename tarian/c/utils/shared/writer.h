@@ -250,4 +250,35 @@ stain void write_sockaddr(uint8_t *buf, uint64_t *pos, unsigned long data_ptr, u
   }
 }
 
-#endif
\ No newline at end of file
+/* the address of the peer of the socket sk in the format of write_sockaddr, AF_UNSPEC for
+   the families other than AF_INET & AF_INET6 */
+stain void write_sock_peer(uint8_t *buf, uint64_t *pos, struct sock *sk) {
+  uint16_t socket_family = sk ? BPF_CORE_READ(sk, __sk_common.skc_family) : AF_UNSPEC;
+
+  switch (socket_family) {
+    case AF_INET: {
+      uint32_t ipv4 = BPF_CORE_READ(sk, __sk_common.skc_daddr);
+      uint16_t port = BPF_CORE_READ(sk, __sk_common.skc_dport);
+
+      write_u8(buf, pos, socket_family);
+      write_u32(buf, pos, ipv4);
+      write_u16(buf, pos, port);
+      break;
+    }
+    case AF_INET6: {
+      uint32_t ipv6[4] = {0, 0, 0, 0};
+      BPF_CORE_READ_INTO(&ipv6, sk, __sk_common.skc_v6_daddr.in6_u.u6_addr32);
+
+      uint16_t port = BPF_CORE_READ(sk, __sk_common.skc_dport);
+
+      write_u8(buf, pos, socket_family);
+      write_ipv6(buf, pos, ipv6);
+      write_u16(buf, pos, port);
+      break;
+    }
+    default:
+      write_u8(buf, pos, AF_UNSPEC);
+  }
+}
+
+#endif
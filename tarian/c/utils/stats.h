@@ -33,6 +33,8 @@ stain void stats__add(int error_code) {
     case TDC_SUCCESS:
         ts->n_trgs_sent++;
         break;
+    case TDC_PAUSED:
+        break;
     case TDCE_RESERVE_SPACE:
     case TDCE_MAP_SUBMIT:
         ts->n_trgs_dropped_max_map_capacity++;
@@ -12,32 +12,37 @@ stain int tdf_discard_event(tarian_event_t *);
 stain int tdf_save(tarian_event_t *, int, void *);
 
 stain int tdf_reserve_space(tarian_event_t *te, enum allocation_type at, u64 size) {
-#if LINUX_VERSION_CODE >= KERNEL_VERSION(5, 8, 0) && false
     u64 sz = 0;
     u8 *store = NULL;
 
-    if (at == FIXED) {
-        te->allocation_mode = 2;
-        store = map__reserve_space(&events, size);
-        if (!store) return TDCE_RESERVE_SPACE;
-        
-        sz = size;
-    } else if ( at == VARIABLE) {
-        te->allocation_mode = 3;
+    if (use_ringbuf) {
+        if (at == FIXED) {
+            te->allocation_mode = 2;
+            store = map__reserve_space(&events_ringbuf, size);
+            if (!store) return TDCE_RESERVE_SPACE;
+
+            sz = size;
+        } else if ( at == VARIABLE) {
+            /*
+              the size of a variable event is only known once written, it is staged in
+              pea_per_cpu_array and copied with bpf_ringbuf_output. Reserving MAX_EVENT_SIZE
+              for every such event would exhaust the ring buffer, reserving them in place is
+              out of scope for now.
+            */
+            te->allocation_mode = 3;
+            store = map__allocate_space(&pea_per_cpu_array);
+            if (!store) return TDCE_RESERVE_SPACE;
+
+            sz = MAX_EVENT_SIZE;
+        }
+    } else {
+        te->allocation_mode = 1;
         store = map__allocate_space(&pea_per_cpu_array);
         if (!store) return TDCE_RESERVE_SPACE;
 
         sz = MAX_EVENT_SIZE;
     }
 
-#else
-    te->allocation_mode = 1;
-    u8 *store = map__allocate_space(&pea_per_cpu_array);
-    if (!store) return TDCE_RESERVE_SPACE;
-
-    u64 sz = MAX_EVENT_SIZE;
-#endif
-
     te->buf.reserved_space = sz;
     te->buf.pos = 0;
     te->buf.data = store;
@@ -46,16 +51,15 @@ stain int tdf_reserve_space(tarian_event_t *te, enum allocation_type at, u64 siz
 }
 
 stain int tdf_submit_event(tarian_event_t *te) {
-#if LINUX_VERSION_CODE >= KERNEL_VERSION(5, 8, 0) && false
     int resp = 0;
     if (te->allocation_mode == 2) {
         resp = map__reserve_submit(te->buf.data);
     } else if (te->allocation_mode == 3) {
-        resp = map__pringbuf_submit(&events, te->buf.data, te->buf.pos);
+        resp = map__pringbuf_submit(&events_ringbuf, te->buf.data, te->buf.pos);
+    } else {
+        resp = map__submit(te->ctx, &events, te->buf.data, te->buf.pos);
     }
-#else
-    int resp = map__submit(te->ctx, &events, te->buf.data, te->buf.pos);
-#endif
+
     stats__add(resp);
     if (resp != TDC_SUCCESS) return resp;
 
@@ -63,10 +67,10 @@ stain int tdf_submit_event(tarian_event_t *te) {
 }
 
 stain int tdf_discard_event(tarian_event_t *te) {
-#if LINUX_VERSION_CODE >= KERNEL_VERSION(5, 8, 0) && false
-    int resp = map__discard(te->buf.data);
-    if (resp != TDC_SUCCESS) return resp;
-#endif
+    if (te->allocation_mode == 2) {
+        int resp = map__discard(te->buf.data);
+        if (resp != TDC_SUCCESS) return resp;
+    }
 
     return TDC_SUCCESS;
 };
@@ -143,4 +147,14 @@ stain int tdf_flex_save(tarian_event_t *te, int type, unsigned long src, uint64_
     return TDC_SUCCESS;
 };
 
-#endif
\ No newline at end of file
+stain int tdf_save_sock_peer(tarian_event_t *te, struct sock *sk) {
+    /*
+      Data save format: [family 1B][...address...][port 2B], a TDT_SOCKADDR
+    */
+    write_sock_peer(te->buf.data, &te->buf.pos, sk);
+
+    te->tarian->meta_data.nparams++;
+    return TDC_SUCCESS;
+};
+
+#endif
@@ -0,0 +1,311 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	cilium_ebpf "github.com/cilium/ebpf"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/intelops/tarian-detector/pkg/k8s"
+	"golang.org/x/sys/unix"
+)
+
+// LsmHook is a Linux security module hook on which policies are applied.
+type LsmHook uint32
+
+const (
+	// BprmCheckSecurity matches the execution of a file.
+	BprmCheckSecurity LsmHook = iota
+	// FileOpen matches the opening of a file.
+	FileOpen
+	// SocketConnect matches the connection of a socket to an address.
+	SocketConnect
+)
+
+// String returns the name of the LsmHook.
+func (lh LsmHook) String() string {
+	switch lh {
+	case BprmCheckSecurity:
+		return "bprm_check_security"
+	case FileOpen:
+		return "file_open"
+	case SocketConnect:
+		return "socket_connect"
+	default:
+		return fmt.Sprintf("unknown LsmHook(%d)", uint32(lh))
+	}
+}
+
+// ParseLsmHook returns the LsmHook with the given name.
+func ParseLsmHook(name string) (LsmHook, error) {
+	for _, lh := range []LsmHook{BprmCheckSecurity, FileOpen, SocketConnect} {
+		if lh.String() == name {
+			return lh, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown lsm hook %q, expected %q, %q or %q", name, BprmCheckSecurity, FileOpen, SocketConnect)
+}
+
+// MarshalText returns the name of the LsmHook.
+func (lh LsmHook) MarshalText() ([]byte, error) {
+	return []byte(lh.String()), nil
+}
+
+// UnmarshalText sets the LsmHook from its name.
+func (lh *LsmHook) UnmarshalText(text []byte) error {
+	hook, err := ParseLsmHook(string(text))
+	if err != nil {
+		return err
+	}
+
+	*lh = hook
+
+	return nil
+}
+
+// LsmPolicy describes the operations matched on a LSM hook. Every match is reported
+// as an event, carrying the Id of the policy.
+//
+// BprmCheckSecurity and FileOpen policies match the inode of the file at Path on the host,
+// resolved when the policy is loaded. SocketConnect policies match the destination Addr and Port,
+// an invalid (zero) Addr matches any address.
+type LsmPolicy struct {
+	Id   uint32     `json:"id"`
+	Hook LsmHook    `json:"hook"`
+	Path string     `json:"path,omitempty"`
+	Addr netip.Addr `json:"addr,omitempty"`
+	Port uint16     `json:"port,omitempty"`
+
+	// Enforce denies the matching operations with EPERM. It is ignored, and the
+	// operations are only reported, when the bpf LSM is not active.
+	Enforce bool `json:"enforce,omitempty"`
+}
+
+// ReadLsmPolicies reads the policies of the JSON file at path, an array of objects with the
+// fields of LsmPolicy, e.g.
+//
+//	[
+//		{"id": 1, "hook": "bprm_check_security", "path": "/usr/bin/nc", "enforce": true},
+//		{"id": 2, "hook": "socket_connect", "addr": "169.254.169.254", "port": 80}
+//	]
+func ReadLsmPolicies(path string) ([]LsmPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var policies []LsmPolicy
+	if err := dec.Decode(&policies); err != nil {
+		return nil, fmt.Errorf("failed to read the lsm policies of %s: %w", path, err)
+	}
+
+	return policies, nil
+}
+
+// lsmPrograms holds the programs of a LSM hook.
+type lsmPrograms struct {
+	probe  string // name of the probe, see Probes
+	lsm    string // bpf lsm program, able to deny the operation
+	kprobe string // kprobe on the security function calling the hook
+	symbol string // security function calling the hook
+}
+
+// lsmHooks maps the LSM hooks to their programs.
+var lsmHooks = map[LsmHook]lsmPrograms{
+	BprmCheckSecurity: {"lsm_bprm_check_security", "tdf_lsm_bprm_check_security", "tdf_lsm_bprm_check_security_kprobe", "security_bprm_check"},
+	FileOpen:          {"lsm_file_open", "tdf_lsm_file_open", "tdf_lsm_file_open_kprobe", "security_file_open"},
+	SocketConnect:     {"lsm_socket_connect", "tdf_lsm_socket_connect", "tdf_lsm_socket_connect_kprobe", "security_socket_connect"},
+}
+
+// addLsm loads the policies and adds the programs of the LSM hooks they apply to. With
+// enforce the bpf lsm programs are used, otherwise kprobes which only report the matches.
+// The programs of the unselected hooks are disabled, their policies are neither reported nor enforced.
+func addLsm(m *ebpf.Module, objs *tarianObjects, useRingBuf bool, enforce bool, policies []LsmPolicy, sel ProbeSelection) error {
+	spec, err := loadTarianLsm()
+	if err != nil {
+		return err
+	}
+
+	hooks := make(map[LsmHook]bool)
+	for _, p := range policies {
+		if _, ok := lsmHooks[p.Hook]; !ok {
+			return fmt.Errorf("policy %d: unsupported lsm hook %v", p.Id, p.Hook)
+		}
+
+		hooks[p.Hook] = true
+	}
+
+	// only the programs of the selected mode are loaded, the bpf lsm programs
+	// are rejected by kernels without BPF_PROG_TYPE_LSM
+	for hook, progs := range lsmHooks {
+		if !hooks[hook] || !enforce {
+			delete(spec.Programs, progs.lsm)
+		}
+
+		if !hooks[hook] || enforce {
+			delete(spec.Programs, progs.kprobe)
+		}
+	}
+
+	coll, err := loadSharedCollection(spec, objs, useRingBuf)
+	if err != nil {
+		return err
+	}
+
+	root := lsmRoot()
+	for _, p := range policies {
+		if err := putLsmPolicy(coll, p, root); err != nil {
+			return fmt.Errorf("policy %d: %w", p.Id, err)
+		}
+	}
+
+	for _, hook := range []LsmHook{BprmCheckSecurity, FileOpen, SocketConnect} {
+		if !hooks[hook] {
+			continue
+		}
+
+		progs := lsmHooks[hook]
+		if enforce {
+			m.AddProgram(newProgram(sel, progs.probe, coll.Programs[progs.lsm], ebpf.NewHookInfo().Lsm()))
+		} else {
+			m.AddProgram(newProgram(sel, progs.probe, coll.Programs[progs.kprobe], ebpf.NewHookInfo().Kprobe(progs.symbol)))
+		}
+	}
+
+	return nil
+}
+
+// lsmRoot returns the directory the paths of the policies are resolved in, the root of the init
+// process of the host in k8s.HostProcDir, or / if the detector runs without it, in the mount
+// namespace of the host.
+func lsmRoot() string {
+	root := k8s.ContainerPath(1, "/")
+	if _, err := os.Stat(root); err != nil {
+		return "/"
+	}
+
+	return root
+}
+
+// putLsmPolicy stores the policy in the policy map of its LSM hook, its path resolved in root.
+func putLsmPolicy(coll *cilium_ebpf.Collection, p LsmPolicy, root string) error {
+	value := tarianLsmLsmPolicyT{Id: p.Id}
+	if p.Enforce {
+		value.Enforce = 1
+	}
+
+	switch p.Hook {
+	case BprmCheckSecurity, FileOpen:
+		key, err := p.inodeKey(root)
+		if err != nil {
+			return err
+		}
+
+		return coll.Maps["lsm_inode_policies"].Put(key, value)
+	case SocketConnect:
+		keys, err := p.netKeys()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if err := coll.Maps["lsm_net_policies"].Put(key, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported lsm hook %v", p.Hook)
+	}
+}
+
+// inodeKey returns the key matching the inode of the file at the policy's Path, resolved in the
+// directory root, see statInRoot.
+func (p LsmPolicy) inodeKey(root string) (tarianLsmLsmInodeKeyT, error) {
+	st, err := statInRoot(root, p.Path)
+	if err != nil {
+		return tarianLsmLsmInodeKeyT{}, fmt.Errorf("failed to stat %s in %s: %w", p.Path, root, err)
+	}
+
+	return tarianLsmLsmInodeKeyT{
+		Ino:  st.Ino,
+		Dev:  kernelDev(uint64(st.Dev)),
+		Hook: uint32(p.Hook),
+	}, nil
+}
+
+// statInRoot returns the status of the file at path as seen by the processes whose root directory is
+// root, its symbolic links resolved within root. On kernels without openat2, before 5.6, the
+// absolute symbolic links are resolved outside root.
+func statInRoot(root, path string) (unix.Stat_t, error) {
+	var st unix.Stat_t
+
+	dirfd, err := unix.Open(root, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return st, err
+	}
+	defer unix.Close(dirfd)
+
+	fd, err := unix.Openat2(dirfd, path, &unix.OpenHow{Flags: unix.O_PATH | unix.O_CLOEXEC, Resolve: unix.RESOLVE_IN_ROOT})
+	if errors.Is(err, unix.ENOSYS) {
+		return st, unix.Stat(filepath.Join(root, path), &st)
+	}
+
+	if err != nil {
+		return st, err
+	}
+	defer unix.Close(fd)
+
+	return st, unix.Fstat(fd, &st)
+}
+
+// netKeys returns the keys matching the policy's Addr and Port. An invalid Addr
+// results in a key for any address of both address families.
+func (p LsmPolicy) netKeys() ([]tarianLsmLsmNetKeyT, error) {
+	// the port is compared in network byte order, as read from the sockaddr on the little endian targets
+	port := binary.LittleEndian.Uint16(binary.BigEndian.AppendUint16(nil, p.Port))
+
+	if !p.Addr.IsValid() {
+		return []tarianLsmLsmNetKeyT{
+			{Family: unix.AF_INET, Port: port},
+			{Family: unix.AF_INET6, Port: port},
+		}, nil
+	}
+
+	addr := p.Addr.Unmap()
+	if addr.Zone() != "" {
+		return nil, fmt.Errorf("unsupported address %v, zones are not matched", p.Addr)
+	}
+
+	key := tarianLsmLsmNetKeyT{Port: port}
+	if addr.Is4() {
+		key.Family = unix.AF_INET
+		a := addr.As4()
+		copy(key.Addr[:], a[:])
+	} else {
+		key.Family = unix.AF_INET6
+		key.Addr = addr.As16()
+	}
+
+	return []tarianLsmLsmNetKeyT{key}, nil
+}
+
+// kernelDev converts a device number as reported by stat into the kernel's
+// internal representation, as found in super_block.s_dev.
+func kernelDev(dev uint64) uint32 {
+	return unix.Major(dev)<<20 | unix.Minor(dev)
+}
@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// TestLsmHook_String tests the String function
+func TestLsmHook_String(t *testing.T) {
+	tests := []struct {
+		name string
+		lh   LsmHook
+		want string
+	}{
+		{name: "bprm_check_security", lh: BprmCheckSecurity, want: "bprm_check_security"},
+		{name: "file_open", lh: FileOpen, want: "file_open"},
+		{name: "socket_connect", lh: SocketConnect, want: "socket_connect"},
+		{name: "unknown", lh: LsmHook(99), want: "unknown LsmHook(99)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lh.String(); got != tt.want {
+				t.Errorf("LsmHook.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseLsmHook tests the ParseLsmHook function
+func TestParseLsmHook(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    LsmHook
+		wantErr bool
+	}{
+		{name: "bprm_check_security", s: "bprm_check_security", want: BprmCheckSecurity},
+		{name: "socket_connect", s: "socket_connect", want: SocketConnect},
+		{name: "unknown", s: "inode_unlink", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLsmHook(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLsmHook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseLsmHook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestReadLsmPolicies tests the ReadLsmPolicies function
+func TestReadLsmPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []LsmPolicy
+		wantErr bool
+	}{
+		{
+			name: "policies",
+			content: `[
+				{"id": 1, "hook": "bprm_check_security", "path": "/usr/bin/nc", "enforce": true},
+				{"id": 2, "hook": "socket_connect", "addr": "169.254.169.254", "port": 80},
+				{"id": 3, "hook": "socket_connect", "port": 22}
+			]`,
+			want: []LsmPolicy{
+				{Id: 1, Hook: BprmCheckSecurity, Path: "/usr/bin/nc", Enforce: true},
+				{Id: 2, Hook: SocketConnect, Addr: netip.MustParseAddr("169.254.169.254"), Port: 80},
+				{Id: 3, Hook: SocketConnect, Port: 22},
+			},
+		},
+		{name: "unknown hook", content: `[{"id": 1, "hook": "inode_unlink"}]`, wantErr: true},
+		{name: "unknown field", content: `[{"id": 1, "hook": "file_open", "file": "/etc/shadow"}]`, wantErr: true},
+		{name: "invalid address", content: `[{"id": 1, "hook": "socket_connect", "addr": "localhost"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policies.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadLsmPolicies(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadLsmPolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadLsmPolicies() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ReadLsmPolicies(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("ReadLsmPolicies() error = %v, wantErr %v", err, true)
+	}
+}
+
+// TestLsmPolicy_netKeys tests the netKeys function
+func TestLsmPolicy_netKeys(t *testing.T) {
+	// 443 in network byte order, as read on the little endian targets
+	const port = 0xbb01
+
+	tests := []struct {
+		name    string
+		policy  LsmPolicy
+		want    []tarianLsmLsmNetKeyT
+		wantErr bool
+	}{
+		{
+			name:   "ipv4",
+			policy: LsmPolicy{Hook: SocketConnect, Addr: netip.MustParseAddr("10.0.0.1"), Port: 443},
+			want: []tarianLsmLsmNetKeyT{
+				{Family: unix.AF_INET, Port: port, Addr: [16]uint8{10, 0, 0, 1}},
+			},
+		},
+		{
+			name:   "ipv4 mapped ipv6",
+			policy: LsmPolicy{Hook: SocketConnect, Addr: netip.MustParseAddr("::ffff:10.0.0.1"), Port: 443},
+			want: []tarianLsmLsmNetKeyT{
+				{Family: unix.AF_INET, Port: port, Addr: [16]uint8{10, 0, 0, 1}},
+			},
+		},
+		{
+			name:   "ipv6",
+			policy: LsmPolicy{Hook: SocketConnect, Addr: netip.MustParseAddr("2001:db8::1"), Port: 443},
+			want: []tarianLsmLsmNetKeyT{
+				{Family: unix.AF_INET6, Port: port, Addr: [16]uint8{0x20, 0x01, 0x0d, 0xb8, 15: 1}},
+			},
+		},
+		{
+			name:   "any address",
+			policy: LsmPolicy{Hook: SocketConnect, Port: 443},
+			want: []tarianLsmLsmNetKeyT{
+				{Family: unix.AF_INET, Port: port},
+				{Family: unix.AF_INET6, Port: port},
+			},
+		},
+		{
+			name:    "ipv6 with zone",
+			policy:  LsmPolicy{Hook: SocketConnect, Addr: netip.MustParseAddr("fe80::1%eth0"), Port: 443},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.netKeys()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LsmPolicy.netKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LsmPolicy.netKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLsmPolicy_inodeKey tests the inodeKey function
+func TestLsmPolicy_inodeKey(t *testing.T) {
+	// root is the root directory of the processes, with an absolute symbolic link to its file
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "file"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink("/file", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	var st unix.Stat_t
+	if err := unix.Stat(filepath.Join(root, "file"), &st); err != nil {
+		t.Fatal(err)
+	}
+
+	want := tarianLsmLsmInodeKeyT{Ino: st.Ino, Dev: kernelDev(uint64(st.Dev)), Hook: uint32(FileOpen)}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "file", path: "/file"},
+		{name: "symbolic link resolved in the root", path: "/link"},
+		{name: "missing file", path: "/missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LsmPolicy{Hook: FileOpen, Path: tt.path}.inodeKey(root)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LsmPolicy.inodeKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != want {
+				t.Errorf("LsmPolicy.inodeKey() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// Test_kernelDev tests the kernelDev function
+func Test_kernelDev(t *testing.T) {
+	tests := []struct {
+		name string
+		dev  uint64
+		want uint32
+	}{
+		{name: "sda1", dev: unix.Mkdev(8, 1), want: 8<<20 | 1},
+		{name: "large minor", dev: unix.Mkdev(0, 1234), want: 1234},
+		{name: "nvme", dev: unix.Mkdev(259, 3), want: 259<<20 | 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kernelDev(tt.dev); got != tt.want {
+				t.Errorf("kernelDev() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"fmt"
+	"strings"
+
+	cilium_ebpf "github.com/cilium/ebpf"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// ProbeGroup classifies the probes by the activity they capture.
+type ProbeGroup string
+
+const (
+	ProcessGroup ProbeGroup = "process" // ProcessGroup captures the creation and the execution of processes.
+	FileGroup    ProbeGroup = "file"    // FileGroup captures the opening, the duplication and the closing of files.
+	NetworkGroup ProbeGroup = "network" // NetworkGroup captures the sockets, their connections and their tls traffic.
+	IoGroup      ProbeGroup = "io"      // IoGroup captures the data read and written on file descriptors.
+)
+
+// Probe is a set of programs capturing an event, e.g. the kprobe & kretprobe of the
+// read syscall. Its Name is the name of the syscall for the syscall probes, and the
+// name of the event otherwise.
+type Probe struct {
+	Name  string
+	Group ProbeGroup
+}
+
+// probes is the catalogue of the probes of the detector.
+var probes = []Probe{
+	{"execve", ProcessGroup},
+	{"execveat", ProcessGroup},
+	{"clone", ProcessGroup},
+	{"close", FileGroup},
+	{"read", IoGroup},
+	{"write", IoGroup},
+	{"open", FileGroup},
+	{"readv", IoGroup},
+	{"writev", IoGroup},
+	{"openat", FileGroup},
+	{"openat2", FileGroup},
+	{"listen", NetworkGroup},
+	{"socket", NetworkGroup},
+	{"accept", NetworkGroup},
+	{"bind", NetworkGroup},
+	{"connect", NetworkGroup},
+	{"dup", FileGroup},
+	{"dup2", FileGroup},
+	{"dup3", FileGroup},
+	{"fcntl", FileGroup},
+	{"accept4", NetworkGroup},
+	{"lsm_bprm_check_security", ProcessGroup},
+	{"lsm_file_open", FileGroup},
+	{"lsm_socket_connect", NetworkGroup},
+	{"ssl_write", NetworkGroup},
+	{"ssl_read", NetworkGroup},
+	{"go_tls_write", NetworkGroup},
+	{"go_tls_read", NetworkGroup},
+	{"readline", ProcessGroup},
+	{"sched_process_fork", ProcessGroup},
+	{"sched_process_exec", ProcessGroup},
+	{"sched_process_exit", ProcessGroup},
+}
+
+// Probes returns the catalogue of the probes of the detector. The lsm, tls and
+// readline probes are only loaded when enabled by the Options.
+func Probes() []Probe {
+	return append([]Probe(nil), probes...)
+}
+
+// ProbeSelection holds the names of the selected probes and probe groups. An empty
+// selection selects every probe.
+type ProbeSelection []string
+
+// ParseProbeSelection parses a comma separated list of probe and group names. The names
+// of the syscall events, such as sys_read or sys_read_entry, select the probe of their syscall.
+func ParseProbeSelection(s string) (ProbeSelection, error) {
+	var sel ProbeSelection
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+
+		sel = append(sel, name)
+	}
+
+	return sel, sel.validate()
+}
+
+// Selects reports whether the probe named name is selected.
+func (ps ProbeSelection) Selects(name string) bool {
+	if len(ps) == 0 {
+		return true
+	}
+
+	group, ok := probeGroup(name)
+	if !ok {
+		return false
+	}
+
+	for _, s := range ps {
+		if probeName(s) == name || ProbeGroup(s) == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validate returns an error if a name of the selection is neither a probe nor a group.
+func (ps ProbeSelection) validate() error {
+	for _, s := range ps {
+		switch ProbeGroup(s) {
+		case ProcessGroup, FileGroup, NetworkGroup, IoGroup:
+			continue
+		}
+
+		if _, ok := probeGroup(probeName(s)); !ok {
+			return fmt.Errorf("unknown probe or probe group %q", s)
+		}
+	}
+
+	return nil
+}
+
+// probeName returns the name of the probe capturing the event named name.
+func probeName(name string) string {
+	if !strings.HasPrefix(name, "sys_") {
+		return name
+	}
+
+	name = strings.TrimPrefix(name, "sys_")
+	name = strings.TrimSuffix(name, "_entry")
+	return strings.TrimSuffix(name, "_exit")
+}
+
+// probeGroup returns the group of the probe named name.
+func probeGroup(name string) (ProbeGroup, bool) {
+	for _, p := range probes {
+		if p.Name == name {
+			return p.Group, true
+		}
+	}
+
+	return "", false
+}
+
+// newProgram returns the program of the probe named probe, disabled when the probe is
+// not selected. Disabled programs are part of the module but not attached.
+func newProgram(sel ProbeSelection, probe string, prog *cilium_ebpf.Program, hook *ebpf.HookInfo) *ebpf.ProgramInfo {
+	pi := ebpf.NewProgram(prog, hook).Probe(probe)
+	if !sel.Selects(probe) {
+		pi.Disable()
+	}
+
+	return pi
+}
+
+// pauseEvents returns the function pausing the events of a probe, by flagging its events
+// in the paused_events map checked by the programs before they submit an event.
+func pauseEvents(paused *cilium_ebpf.Map) ebpf.PauseFunc {
+	return func(probe string, pause bool) error {
+		var value uint8
+		if pause {
+			value = 1
+		}
+
+		for id, event := range eventparser.GenerateTarianEvents() {
+			if probeName(event.GetName()) != probe {
+				continue
+			}
+
+			if err := paused.Put(uint32(id), value); err != nil {
+				return fmt.Errorf("failed to update the event %s: %w", event.GetName(), err)
+			}
+		}
+
+		return nil
+	}
+}
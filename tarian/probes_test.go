@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseProbeSelection tests the ParseProbeSelection function
+func TestParseProbeSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    ProbeSelection
+		wantErr bool
+	}{
+		{name: "empty", arg: "", want: nil},
+		{name: "probes and groups", arg: "read, network,lsm_file_open", want: ProbeSelection{"read", "network", "lsm_file_open"}},
+		{name: "event names", arg: "sys_write_entry,sys_execve", want: ProbeSelection{"sys_write_entry", "sys_execve"}},
+		{name: "trailing comma", arg: "io,", want: ProbeSelection{"io"}},
+		{name: "unknown probe", arg: "read,mmap", want: ProbeSelection{"read", "mmap"}, wantErr: true},
+		{name: "unknown event", arg: "sys_mmap_entry", want: ProbeSelection{"sys_mmap_entry"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProbeSelection(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseProbeSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProbeSelection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProbeSelection_Selects tests the Selects function
+func TestProbeSelection_Selects(t *testing.T) {
+	tests := []struct {
+		name  string
+		sel   ProbeSelection
+		probe string
+		want  bool
+	}{
+		{name: "empty selection", sel: nil, probe: "read", want: true},
+		{name: "probe", sel: ProbeSelection{"read"}, probe: "read", want: true},
+		{name: "other probe", sel: ProbeSelection{"read"}, probe: "write", want: false},
+		{name: "group", sel: ProbeSelection{"network"}, probe: "connect", want: true},
+		{name: "other group", sel: ProbeSelection{"process", "file"}, probe: "write", want: false},
+		{name: "entry event", sel: ProbeSelection{"sys_openat_entry"}, probe: "openat", want: true},
+		{name: "combined event", sel: ProbeSelection{"sys_openat"}, probe: "openat2", want: false},
+		{name: "tls group", sel: ProbeSelection{"network"}, probe: "go_tls_read", want: true},
+		{name: "unknown probe", sel: ProbeSelection{"io"}, probe: "mmap", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sel.Selects(tt.probe); got != tt.want {
+				t.Errorf("ProbeSelection.Selects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProbes tests that every syscall captured by the detector has a probe
+func TestProbes(t *testing.T) {
+	for _, sp := range getSyscallPrograms(&tarianObjects{}) {
+		if _, ok := probeGroup(sp.name); !ok {
+			t.Errorf("Probes() missing syscall %s", sp.name)
+		}
+	}
+
+	for _, progs := range lsmHooks {
+		if _, ok := probeGroup(progs.probe); !ok {
+			t.Errorf("Probes() missing lsm probe %s", progs.probe)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+)
+
+// readlineProgram captures the lines returned by readline.
+const readlineProgram = "tdf_uretprobe_readline"
+
+// addReadline adds the providers of the shells reading their input with GNU readline,
+// either linked in the executable, as bash does, or from libreadline.
+func (d *uprobeDiscovery) addReadline() {
+	d.libraries["libreadline.so"] = d.readlinePrograms
+	d.executables = append(d.executables, func(path string) ([]*ebpf.ProgramInfo, error) {
+		ok, err := hasFunction(path, "readline")
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		return d.readlinePrograms(path)
+	})
+}
+
+// readlinePrograms returns the programs of the executable or the library at path defining readline.
+func (d *uprobeDiscovery) readlinePrograms(path string) ([]*ebpf.ProgramInfo, error) {
+	return []*ebpf.ProgramInfo{
+		d.program("readline", readlineProgram, ebpf.NewHookInfo().Uretprobe(path, "readline")),
+	}, nil
+}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	cilium_ebpf "github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+)
+
+// addSchedTracepoints adds the programs of the sched_process_fork, sched_process_exec and
+// sched_process_exit tracepoints, capturing the lifecycle of the processes whatever the
+// syscall backend.
+func addSchedTracepoints(m *ebpf.Module, objs *tarianObjects, sel ProbeSelection) {
+	for _, tp := range []struct {
+		name string
+		prog *cilium_ebpf.Program
+	}{
+		{"sched_process_fork", objs.TdfSchedProcessFork},
+		{"sched_process_exec", objs.TdfSchedProcessExec},
+		{"sched_process_exit", objs.TdfSchedProcessExit},
+	} {
+		m.AddProgram(newProgram(sel, tp.name, tp.prog, ebpf.NewHookInfo().RawTracepoint(link.RawTracepointOptions{Name: tp.name, Program: tp.prog})))
+	}
+}
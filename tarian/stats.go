@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	cilium_ebpf "github.com/cilium/ebpf"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+)
+
+// StatNames holds the names of the kernel counters returned by the Stats of the handler of
+// the module, the fields of tarian_stats_t, in the order they are declared.
+var StatNames = []string{
+	"n_trgs",                          // events triggered
+	"n_trgs_sent",                     // events sent to userspace
+	"n_trgs_dropped",                  // events dropped, for any reason
+	"n_trgs_dropped_max_map_capacity", // events dropped because the perf or ring buffer was full
+	"n_trgs_dropped_max_buffer_size",  // events dropped because they did not fit in their buffer
+	"n_trgs_read_error",               // events with a bpf_probe_read_* failure
+	"n_trgs_unknown",                  // events dropped for another reason
+}
+
+// statsReader returns the function reading the per-CPU counters of the tarian_stats map,
+// summed across the processors.
+func statsReader(m *cilium_ebpf.Map) ebpf.StatsFunc {
+	return func() (map[string]uint64, error) {
+		var perCpu []tarianTarianStatsT
+		if err := m.Lookup(uint32(0), &perCpu); err != nil {
+			return nil, err
+		}
+
+		return sumStats(perCpu), nil
+	}
+}
+
+// sumStats returns the counters of the processors summed, by counter name.
+func sumStats(perCpu []tarianTarianStatsT) map[string]uint64 {
+	stats := make(map[string]uint64, len(StatNames))
+	for _, name := range StatNames {
+		stats[name] = 0
+	}
+
+	for _, s := range perCpu {
+		stats["n_trgs"] += s.N_trgs
+		stats["n_trgs_sent"] += s.N_trgsSent
+		stats["n_trgs_dropped"] += s.N_trgsDropped
+		stats["n_trgs_dropped_max_map_capacity"] += s.N_trgsDroppedMaxMapCapacity
+		stats["n_trgs_dropped_max_buffer_size"] += s.N_trgsDroppedMaxBufferSize
+		stats["n_trgs_read_error"] += s.N_trgsReadError
+		stats["n_trgs_unknown"] += s.N_trgsUnknown
+	}
+
+	return stats
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test_sumStats tests the sumStats function.
+func Test_sumStats(t *testing.T) {
+	tests := []struct {
+		name   string
+		perCpu []tarianTarianStatsT
+		want   map[string]uint64
+	}{
+		{
+			name: "no processors",
+			want: map[string]uint64{
+				"n_trgs": 0, "n_trgs_sent": 0, "n_trgs_dropped": 0, "n_trgs_dropped_max_map_capacity": 0,
+				"n_trgs_dropped_max_buffer_size": 0, "n_trgs_read_error": 0, "n_trgs_unknown": 0,
+			},
+		},
+		{
+			name: "summed across processors",
+			perCpu: []tarianTarianStatsT{
+				{N_trgs: 10, N_trgsSent: 7, N_trgsDropped: 3, N_trgsDroppedMaxMapCapacity: 2, N_trgsUnknown: 1},
+				{N_trgs: 5, N_trgsSent: 4, N_trgsDropped: 1, N_trgsDroppedMaxBufferSize: 1, N_trgsReadError: 2},
+			},
+			want: map[string]uint64{
+				"n_trgs": 15, "n_trgs_sent": 11, "n_trgs_dropped": 4, "n_trgs_dropped_max_map_capacity": 2,
+				"n_trgs_dropped_max_buffer_size": 1, "n_trgs_read_error": 2, "n_trgs_unknown": 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sumStats(tt.perCpu)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sumStats() = %v, want %v", got, tt.want)
+			}
+
+			if len(got) != len(StatNames) {
+				t.Errorf("sumStats() = %d counters, want %d", len(got), len(StatNames))
+			}
+		})
+	}
+}
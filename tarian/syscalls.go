@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"fmt"
+	"strings"
+
+	cilium_ebpf "github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+)
+
+// SyscallBackend selects the hooks used to capture the syscalls. All backends produce identical events.
+type SyscallBackend int
+
+const (
+	// KprobeBackend attaches a kprobe & kretprobe to the wrapper of every syscall.
+	KprobeBackend SyscallBackend = iota
+	// RawTracepointBackend attaches to the raw_syscalls sys_enter & sys_exit tracepoints
+	// and dispatches to the syscall handlers by syscall number.
+	RawTracepointBackend
+	// FexitBackend attaches fexit programs to the wrapper of every syscall. The entry
+	// arguments and the return value are written as a single event. Requires BTF
+	// trampolines, see utils.KernelFeatures.Tracing.
+	FexitBackend
+)
+
+// String returns the name of the SyscallBackend.
+func (sb SyscallBackend) String() string {
+	switch sb {
+	case KprobeBackend:
+		return "kprobe"
+	case RawTracepointBackend:
+		return "raw_tracepoint"
+	case FexitBackend:
+		return "fexit"
+	default:
+		return fmt.Sprintf("unknown SyscallBackend(%d)", int(sb))
+	}
+}
+
+// ParseSyscallBackend returns the SyscallBackend with the given name.
+func ParseSyscallBackend(name string) (SyscallBackend, error) {
+	switch name {
+	case KprobeBackend.String():
+		return KprobeBackend, nil
+	case RawTracepointBackend.String():
+		return RawTracepointBackend, nil
+	case FexitBackend.String():
+		return FexitBackend, nil
+	default:
+		return -1, fmt.Errorf("unknown syscall backend %q, expected %q, %q or %q", name, KprobeBackend, RawTracepointBackend, FexitBackend)
+	}
+}
+
+// syscallPrograms holds the entry & exit programs of a syscall for each backend.
+type syscallPrograms struct {
+	name     string               // name of the syscall
+	entry    *cilium_ebpf.Program // kprobe on the syscall wrapper
+	exit     *cilium_ebpf.Program // kretprobe on the syscall wrapper
+	rtpEntry *cilium_ebpf.Program // handler tail called from raw_syscalls/sys_enter
+	rtpExit  *cilium_ebpf.Program // handler tail called from raw_syscalls/sys_exit
+}
+
+// getSyscallPrograms returns the programs of all the syscalls captured by the detector.
+func getSyscallPrograms(objs *tarianObjects) []syscallPrograms {
+	return []syscallPrograms{
+		{"execve", objs.TdfExecveE, objs.TdfExecveR, objs.TdfRtpExecveE, objs.TdfRtpExecveR},
+		{"execveat", objs.TdfExecveatE, objs.TdfExecveatR, objs.TdfRtpExecveatE, objs.TdfRtpExecveatR},
+		{"clone", objs.TdfCloneE, objs.TdfCloneR, objs.TdfRtpCloneE, objs.TdfRtpCloneR},
+		{"close", objs.TdfCloseE, objs.TdfCloseR, objs.TdfRtpCloseE, objs.TdfRtpCloseR},
+		{"read", objs.TdfReadE, objs.TdfReadR, objs.TdfRtpReadE, objs.TdfRtpReadR},
+		{"write", objs.TdfWriteE, objs.TdfWriteR, objs.TdfRtpWriteE, objs.TdfRtpWriteR},
+		{"open", objs.TdfOpenE, objs.TdfOpenR, objs.TdfRtpOpenE, objs.TdfRtpOpenR},
+		{"readv", objs.TdfReadvE, objs.TdfReadvR, objs.TdfRtpReadvE, objs.TdfRtpReadvR},
+		{"writev", objs.TdfWritevE, objs.TdfWritevR, objs.TdfRtpWritevE, objs.TdfRtpWritevR},
+		{"openat", objs.TdfOpenatE, objs.TdfOpenatR, objs.TdfRtpOpenatE, objs.TdfRtpOpenatR},
+		{"openat2", objs.TdfOpenat2E, objs.TdfOpenat2R, objs.TdfRtpOpenat2E, objs.TdfRtpOpenat2R},
+		{"listen", objs.TdfListenE, objs.TdfListenR, objs.TdfRtpListenE, objs.TdfRtpListenR},
+		{"socket", objs.TdfSocketE, objs.TdfSocketR, objs.TdfRtpSocketE, objs.TdfRtpSocketR},
+		{"accept", objs.TdfAcceptE, objs.TdfAcceptR, objs.TdfRtpAcceptE, objs.TdfRtpAcceptR},
+		{"bind", objs.TdfBindE, objs.TdfBindR, objs.TdfRtpBindE, objs.TdfRtpBindR},
+		{"connect", objs.TdfConnectE, objs.TdfConnectR, objs.TdfRtpConnectE, objs.TdfRtpConnectR},
+		{"dup", objs.TdfDupE, objs.TdfDupR, objs.TdfRtpDupE, objs.TdfRtpDupR},
+		{"dup2", objs.TdfDup2E, objs.TdfDup2R, objs.TdfRtpDup2E, objs.TdfRtpDup2R},
+		{"dup3", objs.TdfDup3E, objs.TdfDup3R, objs.TdfRtpDup3E, objs.TdfRtpDup3R},
+		{"fcntl", objs.TdfFcntlE, objs.TdfFcntlR, objs.TdfRtpFcntlE, objs.TdfRtpFcntlR},
+		{"accept4", objs.TdfAccept4E, objs.TdfAccept4R, objs.TdfRtpAccept4E, objs.TdfRtpAccept4R},
+	}
+}
+
+// addKprobes adds a kprobe & kretprobe on the syscall wrapper of every syscall available on the architecture.
+func addKprobes(m *ebpf.Module, objs *tarianObjects, st eventparser.SyscallTable, prefix string, sel ProbeSelection) {
+	for _, sp := range getSyscallPrograms(objs) {
+		// e.g. open is not available on architectures using the generic syscall table
+		if !st.Has(sp.name) {
+			continue
+		}
+
+		m.AddProgram(newProgram(sel, sp.name, sp.entry, ebpf.NewHookInfo().Kprobe(prefix+sp.name)))
+		m.AddProgram(newProgram(sel, sp.name, sp.exit, ebpf.NewHookInfo().Kretprobe(prefix+sp.name)))
+	}
+}
+
+// addRawTracepoints adds the handlers of the syscalls available on the architecture, stored by
+// syscall number in the program arrays of the raw_syscalls sys_enter & sys_exit dispatchers. The
+// handlers of a probe are attached and detached by updating their slots, the dispatchers stay
+// attached so that the probes can be attached at runtime.
+func addRawTracepoints(m *ebpf.Module, objs *tarianObjects, st eventparser.SyscallTable, sel ProbeSelection) {
+	for _, sp := range getSyscallPrograms(objs) {
+		if !st.Has(sp.name) {
+			continue
+		}
+
+		id := uint32(st.Id(sp.name))
+		m.AddProgram(newProgram(sel, sp.name, sp.rtpEntry, ebpf.NewHookInfo().TailCall(ebpf.TailCallOptions{Map: objs.SysEnterCalls, Key: id})))
+		m.AddProgram(newProgram(sel, sp.name, sp.rtpExit, ebpf.NewHookInfo().TailCall(ebpf.TailCallOptions{Map: objs.SysExitCalls, Key: id})))
+	}
+
+	m.AddProgram(ebpf.NewProgram(objs.TdfSysEnter, ebpf.NewHookInfo().RawTracepoint(link.RawTracepointOptions{Name: "sys_enter", Program: objs.TdfSysEnter})))
+	m.AddProgram(ebpf.NewProgram(objs.TdfSysExit, ebpf.NewHookInfo().RawTracepoint(link.RawTracepointOptions{Name: "sys_exit", Program: objs.TdfSysExit})))
+}
+
+// addFexits loads the fentry & fexit programs and adds a fexit on the syscall wrapper of
+// every syscall available on the architecture. Syscalls replacing their arguments, such
+// as execve, are additionally captured on entry with a fentry.
+func addFexits(m *ebpf.Module, objs *tarianObjects, useRingBuf bool, st eventparser.SyscallTable, prefix string, sel ProbeSelection) error {
+	coll, err := getFexitCollection(objs, useRingBuf, st)
+	if err != nil {
+		return err
+	}
+
+	var progs []*ebpf.ProgramInfo
+	for _, sp := range getSyscallPrograms(objs) {
+		if !st.Has(sp.name) {
+			continue
+		}
+
+		if prog, ok := coll.Programs["tdf_fentry_"+sp.name]; ok {
+			progs = append(progs, newProgram(sel, sp.name, prog, ebpf.NewHookInfo().Fentry(prefix+sp.name)))
+		}
+
+		prog, ok := coll.Programs["tdf_fexit_"+sp.name]
+		if !ok {
+			coll.Close()
+			return fmt.Errorf("missing fexit program for %s", sp.name)
+		}
+
+		progs = append(progs, newProgram(sel, sp.name, prog, ebpf.NewHookInfo().Fexit(prefix+sp.name)))
+	}
+
+	for _, prog := range progs {
+		m.AddProgram(prog)
+	}
+
+	return nil
+}
+
+// getFexitCollection loads the fentry & fexit programs sharing the maps of objs.
+func getFexitCollection(objs *tarianObjects, useRingBuf bool, st eventparser.SyscallTable) (*cilium_ebpf.Collection, error) {
+	spec, err := loadTarianFexit()
+	if err != nil {
+		return nil, err
+	}
+
+	// the traced function of a tracing program is resolved when the program is loaded
+	for name := range spec.Programs {
+		syscall := strings.TrimPrefix(strings.TrimPrefix(name, "tdf_fentry_"), "tdf_fexit_")
+		if !st.Has(syscall) {
+			delete(spec.Programs, name)
+		}
+	}
+
+	return loadSharedCollection(spec, objs, useRingBuf)
+}
@@ -5,21 +5,102 @@ package tarian
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"math/bits"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
 
 	cilium_ebpf "github.com/cilium/ebpf"
 	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
 	"github.com/intelops/tarian-detector/pkg/err"
+	"github.com/intelops/tarian-detector/pkg/eventparser"
 	"github.com/intelops/tarian-detector/pkg/utils"
 )
 
 var tarianErr = err.New("tarian.tarian")
 
-//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -cc clang -cflags $BPF_CFLAGS -target $CURR_ARCH tarian c/tarian.bpf.c -- -I../headers -I./c
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -cc clang -cflags $BPF_CFLAGS -target amd64,arm64 tarian c/tarian.bpf.c -- -I../headers -I./c
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -cc clang -cflags $BPF_CFLAGS -target amd64,arm64 tarianFexit c/tarian_fexit.bpf.c -- -I../headers -I./c
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -cc clang -cflags $BPF_CFLAGS -target amd64,arm64 tarianLsm c/tarian_lsm.bpf.c -- -I../headers -I./c
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -cc clang -cflags $BPF_CFLAGS -target amd64,arm64 tarianUprobe c/tarian_uprobe.bpf.c -- -I../headers -I./c
+
+// minKernelVersion is the oldest kernel supported by the detector. Global data, used
+// to configure the eBPF programs at load time, is available from 5.2 onwards.
+var minKernelVersion = utils.KernelVersion(5, 2, 0)
+
+// DefaultRingBufferSize is the size in bytes of the ring buffers of all the cpus together by default.
+const DefaultRingBufferSize = 64 << 20
+
+// maxEventSize is the size of the largest event, MAX_EVENT_SIZE, a ring buffer holds at least two.
+const maxEventSize = 64 << 10
+
+// syscallPrefixes maps the architecture to the symbol prefix of its syscall wrappers.
+var syscallPrefixes = map[string]string{
+	"amd64": "__x64_sys_",
+	"arm64": "__arm64_sys_",
+}
+
+// Options configures the eBPF module returned by GetModule.
+type Options struct {
+	SyscallBackend SyscallBackend // SyscallBackend selects the hooks used to capture the syscalls, defaults to KprobeBackend.
+	LsmPolicies    []LsmPolicy    // LsmPolicies are reported, and denied if enforced, on their LSM hooks.
+	TlsCapture     bool           // TlsCapture captures the plaintext of the OpenSSL and Go crypto/tls connections.
+	ShellCapture   bool           // ShellCapture captures the lines read by the interactive shells using readline, e.g. bash.
+	Probes         ProbeSelection // Probes selects the probes attached, the programs of the others are disabled. Defaults to all.
+	RingBufferSize int            // RingBufferSize is the size in bytes of the ring buffers of all the cpus together, defaults to DefaultRingBufferSize.
+}
 
 // GetModule loads the eBPF specifications, such as maps, programs, and structures, from a file.
 // It returns a pointer to an ebpf.Module and an error, if any occurred during the loading process.
-func GetModule() (*ebpf.Module, error) {
-	bpfObjs, err := getBpfObject()
+func GetModule(opts Options) (*ebpf.Module, error) {
+	kf, err := utils.DetectKernelFeatures()
+	if err != nil {
+		return nil, tarianErr.Throwf("failed to detect kernel features: %v", err)
+	}
+
+	return getModule(kf, opts)
+}
+
+// getModule loads the eBPF specifications selecting the code paths supported by the given kernel.
+func getModule(kf *utils.KernelFeatures, opts Options) (*ebpf.Module, error) {
+	sys, ok := syscallPrefixes[runtime.GOARCH]
+	if !ok {
+		return nil, tarianErr.Throwf("unsupported architecture %s", runtime.GOARCH)
+	}
+
+	st, _ := eventparser.GetSyscallTable(runtime.GOARCH)
+
+	if err := opts.Probes.validate(); err != nil {
+		return nil, tarianErr.Throwf("%v", err)
+	}
+
+	if kf.Version < minKernelVersion {
+		return nil, tarianErr.Throwf("unsupported kernel version %s, minimum required version is 5.2", kf.Release)
+	}
+
+	if !kf.BTF {
+		return nil, tarianErr.Throwf("kernel %s does not expose BTF information, CONFIG_DEBUG_INFO_BTF is required", kf.Release)
+	}
+
+	// BPF_MAP_TYPE_RINGBUF is available from 5.8 onwards, but distributions may
+	// disable or backport it, so the probed feature is used instead of the version.
+	useRingBuf := kf.RingBuf
+
+	ringBufSize := opts.RingBufferSize
+	if ringBufSize <= 0 {
+		ringBufSize = DefaultRingBufferSize
+	}
+
+	bpfObjs, err := getBpfObject(useRingBuf, ringBufSize)
+	if err != nil && useRingBuf {
+		// fallback to the perf event array if the ring buffer transport can not be loaded
+		useRingBuf = false
+		bpfObjs, err = getBpfObject(useRingBuf, ringBufSize)
+	}
+
 	if err != nil {
 		var verr *cilium_ebpf.VerifierError
 		if errors.As(err, &verr) {
@@ -30,91 +111,252 @@ func GetModule() (*ebpf.Module, error) {
 	}
 
 	tarianDetectorModule := ebpf.NewModule("tarian_detector")
-	ckv, err := utils.CurrentKernelVersion()
+	tarianDetectorModule.Pauser(pauseEvents(bpfObjs.PausedEvents))
+	tarianDetectorModule.Stats(statsReader(bpfObjs.TarianStats))
+	if useRingBuf {
+		tarianDetectorModule.Map(ebpf.NewArrayOfRingBuf(bpfObjs.EventsRingbuf))
+	} else {
+		tarianDetectorModule.Map(ebpf.NewPerfEventWithBuffer(bpfObjs.Events, bpfObjs.PeaPerCpuArray))
+	}
+
+	switch opts.SyscallBackend {
+	case FexitBackend:
+		// fallback to the kprobes if BPF trampolines are not supported or the
+		// fexit programs can not be loaded, e.g. no BTF for the syscall wrappers
+		if kf.Tracing {
+			err := addFexits(tarianDetectorModule, bpfObjs, useRingBuf, st, sys, opts.Probes)
+			if err == nil {
+				break
+			}
+
+			log.Printf("fexit programs not loaded, falling back to the kprobes: %v", err)
+		}
+
+		fallthrough
+	case KprobeBackend:
+		addKprobes(tarianDetectorModule, bpfObjs, st, sys, opts.Probes)
+	case RawTracepointBackend:
+		addRawTracepoints(tarianDetectorModule, bpfObjs, st, opts.Probes)
+	default:
+		return nil, tarianErr.Throwf("unsupported syscall backend: %v", opts.SyscallBackend)
+	}
+
+	addSchedTracepoints(tarianDetectorModule, bpfObjs, opts.Probes)
+
+	if len(opts.LsmPolicies) > 0 {
+		// policies are only reported if the bpf lsm is not active
+		enforce := kf.LSM && kf.LSMEnabled
+
+		if err := addLsm(tarianDetectorModule, bpfObjs, useRingBuf, enforce, opts.LsmPolicies, opts.Probes); err != nil {
+			return nil, tarianErr.Throwf("failed to load the lsm policies: %v", err)
+		}
+	}
+
+	if opts.TlsCapture || opts.ShellCapture {
+		// the files are discovered in the running processes on every refresh of the handler
+		if err := addUprobes(tarianDetectorModule, bpfObjs, useRingBuf, opts); err != nil {
+			return nil, tarianErr.Throwf("failed to load the uprobe programs: %v", err)
+		}
+	}
+
+	return tarianDetectorModule, nil
+}
+
+// loads the ebpf specs like maps, programs, with ring buffers of ringBufSize bytes for all the cpus
+func getBpfObject(useRingBuf bool, ringBufSize int) (*tarianObjects, error) {
+	spec, err := loadTarian()
 	if err != nil {
-		return nil, tarianErr.Throwf("failed to get current kernel version: %v", err)
+		return nil, err
 	}
 
-	if ckv >= utils.KernelVersion(5, 8, 0) && false {
-		tarianDetectorModule.Map(ebpf.NewArrayOfRingBuf(bpfObjs.Events))
-	} else {
-		tarianDetectorModule.Map(ebpf.NewPerfEventWithBuffer(bpfObjs.Events, bpfObjs.PeaPerCpuArray))
+	err = spec.RewriteConstants(map[string]interface{}{
+		"use_ringbuf": useRingBuf,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// kprobe & kretprobe execve
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfExecveE, ebpf.NewHookInfo().Kprobe("__x64_sys_execve")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfExecveR, ebpf.NewHookInfo().Kretprobe("__x64_sys_execve")))
+	ncpu, err := cilium_ebpf.PossibleCPU()
+	if err != nil {
+		return nil, err
+	}
 
-	// kprobe & kretprobe execveat
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfExecveatE, ebpf.NewHookInfo().Kprobe("__x64_sys_execveat")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfExecveatR, ebpf.NewHookInfo().Kretprobe("__x64_sys_execveat")))
+	err = sizeEventMaps(spec, useRingBuf, ncpu, ringBufferEntries(ringBufSize, ncpu, os.Getpagesize()))
+	if err != nil {
+		return nil, err
+	}
+
+	var bpfObj tarianObjects
+	err = spec.LoadAndAssign(&bpfObj, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bpfObj, nil
+}
+
+// ringBufferEntries returns the size of the ring buffer of each of the ncpu cpus, the size in bytes of
+// the ring buffers of all the cpus split between them. The ring buffers are sized in powers of two, and
+// multiples of the page size pageSize, holding at least two of the largest events.
+func ringBufferEntries(size, ncpu, pageSize int) uint32 {
+	perCpu := size / max(ncpu, 1)
+	if perCpu < 2*maxEventSize || perCpu < pageSize {
+		return uint32(max(2*maxEventSize, pageSize))
+	}
+
+	return uint32(1) << (bits.Len(uint(perCpu)) - 1)
+}
 
-	// kprobe & kretprobe clone
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfCloneE, ebpf.NewHookInfo().Kprobe("__x64_sys_clone")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfCloneR, ebpf.NewHookInfo().Kretprobe("__x64_sys_clone")))
+// sizeEventMaps sizes the per cpu staging buffer and the per cpu ring buffers to
+// the ncpu possible cpus, the ring buffers of entries bytes each. The ring buffers
+// of the cpus beyond ncpu, and all of them when the ring buffer transport is not
+// used, are replaced with placeholder maps so that they take no memory and the
+// object still loads on kernels without BPF_MAP_TYPE_RINGBUF.
+func sizeEventMaps(spec *cilium_ebpf.CollectionSpec, useRingBuf bool, ncpu int, entries uint32) error {
+	staging, ok := spec.Maps["pea_per_cpu_array"]
+	if !ok {
+		return fmt.Errorf("missing map spec pea_per_cpu_array")
+	}
 
-	// kprobe & kretprobe close
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfCloseE, ebpf.NewHookInfo().Kprobe("__x64_sys_close")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfCloseR, ebpf.NewHookInfo().Kretprobe("__x64_sys_close")))
+	if staging.MaxEntries < uint32(ncpu) {
+		staging.MaxEntries = uint32(ncpu)
+	}
 
-	// kprobe & kretprobe read
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfReadE, ebpf.NewHookInfo().Kprobe("__x64_sys_read")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfReadR, ebpf.NewHookInfo().Kretprobe("__x64_sys_read")))
+	outer, ok := spec.Maps["events_ringbuf"]
+	if !ok {
+		return fmt.Errorf("missing map spec events_ringbuf")
+	}
 
-	// kprobe & kretprobe write
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfWriteE, ebpf.NewHookInfo().Kprobe("__x64_sys_write")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfWriteR, ebpf.NewHookInfo().Kretprobe("__x64_sys_write")))
+	placeholder := &cilium_ebpf.MapSpec{
+		Type:       cilium_ebpf.Array,
+		KeySize:    4,
+		ValueSize:  4,
+		MaxEntries: 1,
+	}
+
+	if !useRingBuf {
+		for _, kv := range outer.Contents {
+			name, ok := kv.Value.(string)
+			if !ok {
+				continue
+			}
+
+			inner := placeholder.Copy()
+			inner.Name = name
+			spec.Maps[name] = inner
+		}
 
-	// kprobe & kretprobe open
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfOpenE, ebpf.NewHookInfo().Kprobe("__x64_sys_open")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfOpenR, ebpf.NewHookInfo().Kretprobe("__x64_sys_open")))
+		outer.InnerMap = placeholder
 
-	// kprobe & kretprobe readv
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfReadvE, ebpf.NewHookInfo().Kprobe("__x64_sys_readv")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfReadvR, ebpf.NewHookInfo().Kretprobe("__x64_sys_readv")))
+		return nil
+	}
 
-	// kprobe & kretprobe writev
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfWritevE, ebpf.NewHookInfo().Kprobe("__x64_sys_writev")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfWritevR, ebpf.NewHookInfo().Kretprobe("__x64_sys_writev")))
+	inner, ok := spec.Maps["erb_cpu0"]
+	if !ok {
+		return fmt.Errorf("missing map spec erb_cpu0")
+	}
 
-	// kprobe & kretprobe openat
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfOpenatE, ebpf.NewHookInfo().Kprobe("__x64_sys_openat")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfOpenatR, ebpf.NewHookInfo().Kretprobe("__x64_sys_openat")))
+	inner = inner.Copy()
 
-	// kprobe & kretprobe openat2
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfOpenat2E, ebpf.NewHookInfo().Kprobe("__x64_sys_openat2")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfOpenat2R, ebpf.NewHookInfo().Kretprobe("__x64_sys_openat2")))
+	contents := make([]cilium_ebpf.MapKV, 0, max(len(outer.Contents), ncpu))
+	for _, kv := range outer.Contents {
+		name, ok := kv.Value.(string)
+		if !ok || spec.Maps[name] == nil {
+			continue
+		}
 
-	// kprobe & kretprobe listen
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfListenE, ebpf.NewHookInfo().Kprobe("__x64_sys_listen")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfListenR, ebpf.NewHookInfo().Kretprobe("__x64_sys_listen")))
+		if index, ok := kv.Key.(uint32); ok && int(index) >= ncpu {
+			// not a possible cpu, left out of events_ringbuf
+			unused := placeholder.Copy()
+			unused.Name = name
+			spec.Maps[name] = unused
+			continue
+		}
 
-	// kprobe & kretprobe socket
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfSocketE, ebpf.NewHookInfo().Kprobe("__x64_sys_socket")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfSocketR, ebpf.NewHookInfo().Kretprobe("__x64_sys_socket")))
+		spec.Maps[name].MaxEntries = entries
+		contents = append(contents, kv)
+	}
 
-	// kprobe & kretprobe accept
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfAcceptE, ebpf.NewHookInfo().Kprobe("__x64_sys_accept")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfAcceptR, ebpf.NewHookInfo().Kretprobe("__x64_sys_accept")))
+	if outer.InnerMap != nil {
+		outer.InnerMap.MaxEntries = entries
+	}
 
-	// kprobe & kretprobe bind
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfBindE, ebpf.NewHookInfo().Kprobe("__x64_sys_bind")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfBindR, ebpf.NewHookInfo().Kretprobe("__x64_sys_bind")))
+	inner.MaxEntries = entries
+	for i := len(outer.Contents); i < ncpu; i++ {
+		name := fmt.Sprintf("erb_cpu%d", i)
 
-	// kprobe & kretprobe connect
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfConnectE, ebpf.NewHookInfo().Kprobe("__x64_sys_connect")))
-	tarianDetectorModule.AddProgram(ebpf.NewProgram(bpfObjs.TdfConnectR, ebpf.NewHookInfo().Kretprobe("__x64_sys_connect")))
+		rb := inner.Copy()
+		rb.Name = name
+		spec.Maps[name] = rb
 
-	return tarianDetectorModule, nil
+		contents = append(contents, cilium_ebpf.MapKV{Key: uint32(i), Value: name})
+	}
+
+	outer.Contents = contents
+	if outer.MaxEntries < uint32(ncpu) {
+		outer.MaxEntries = uint32(ncpu)
+	}
+
+	return nil
 }
 
-// loads the ebpf specs like maps, programs
-func getBpfObject() (*tarianObjects, error) {
-	var bpfObj tarianObjects
-	err := loadTarianObjects(&bpfObj, nil)
+// loadSharedCollection loads spec, an object built with the maps of the main object,
+// replacing its maps with those of objs. The programs of spec thereby share the
+// event transport and the statistics of objs.
+func loadSharedCollection(spec *cilium_ebpf.CollectionSpec, objs *tarianObjects, useRingBuf bool) (*cilium_ebpf.Collection, error) {
+	err := spec.RewriteConstants(map[string]interface{}{
+		"use_ringbuf": useRingBuf,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &bpfObj, nil
+	ncpu, err := cilium_ebpf.PossibleCPU()
+	if err != nil {
+		return nil, err
+	}
+
+	// sized like the maps of objs so that they are compatible replacements
+	err = sizeEventMaps(spec, useRingBuf, ncpu, objs.ErbCpu0.MaxEntries())
+	if err != nil {
+		return nil, err
+	}
+
+	replacements := sharedMaps(objs)
+	for name, ms := range spec.Maps {
+		if _, ok := replacements[name]; ok {
+			// already populated when objs was loaded
+			ms.Contents = nil
+			continue
+		}
+
+		// the ring buffers of the cpus beyond those declared in the object
+		// are only referenced by events_ringbuf of objs
+		if strings.HasPrefix(name, "erb_cpu") {
+			delete(spec.Maps, name)
+		}
+	}
+
+	return cilium_ebpf.NewCollectionWithOptions(spec, cilium_ebpf.CollectionOptions{
+		MapReplacements: replacements,
+	})
+}
+
+// sharedMaps returns the maps of objs keyed by their name in the eBPF object.
+func sharedMaps(objs *tarianObjects) map[string]*cilium_ebpf.Map {
+	maps := make(map[string]*cilium_ebpf.Map)
+
+	v := reflect.ValueOf(objs.tarianMaps)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("ebpf")
+
+		m, ok := v.Field(i).Interface().(*cilium_ebpf.Map)
+		if !ok || m == nil || len(name) == 0 {
+			continue
+		}
+
+		maps[name] = m
+	}
+
+	return maps
 }
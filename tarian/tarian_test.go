@@ -5,45 +5,56 @@ package tarian
 
 import (
 	"fmt"
-	"os"
+	"runtime"
 	"testing"
 
+	cilium_ebpf "github.com/cilium/ebpf"
 	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/intelops/tarian-detector/pkg/eventparser"
+	"github.com/intelops/tarian-detector/pkg/utils"
 )
 
-func setup(major, minor, patch any) {
-	os.Setenv("LINUX_VERSION_MAJOR", fmt.Sprintf("%v", major))
-	os.Setenv("LINUX_VERSION_MINOR", fmt.Sprintf("%v", minor))
-	os.Setenv("LINUX_VERSION_PATCH", fmt.Sprintf("%v", patch))
-}
+// detect returns the features of the running kernel, failing the test if they can not be detected.
+func detect(t *testing.T) *utils.KernelFeatures {
+	t.Helper()
 
-func teardown() {
-	os.Unsetenv("LINUX_VERSION_MAJOR")
-	os.Unsetenv("LINUX_VERSION_MINOR")
-	os.Unsetenv("LINUX_VERSION_PATCH")
+	kf, err := utils.DetectKernelFeatures()
+	if err != nil {
+		t.Fatalf("DetectKernelFeatures() error = %v", err)
+	}
+
+	return kf
 }
 
 // TestGetModule_Probe_count tests the GetModule function with a specific probe count.
 func TestGetModule_Probe_count(t *testing.T) {
-	setup(5, 8, 0)
-	got, err := GetModule()
+	got, err := GetModule(Options{})
 
 	if err != nil {
 		t.Errorf("GetModule() error = %v", err)
 	}
 
+	// the open syscall does not exist on architectures using the generic syscall table
 	probeCount := 16 * 2
+	if st, _ := eventparser.GetSyscallTable(runtime.GOARCH); !st.Has("open") {
+		probeCount -= 2
+	}
+
+	// the sched tracepoints
+	probeCount += 3
+
 	if len(got.GetPrograms()) != probeCount {
 		t.Errorf("GetModule() = %v, want %v", len(got.GetPrograms()), probeCount)
 	}
-
-	teardown()
 }
 
 // TestGetModule_Perf_Check tests the GetModule function for the map type PerfEventArray
 func TestGetModule_Perf_Check(t *testing.T) {
-	setup(5, 6, 0)
-	got, err := GetModule()
+	// simulate a kernel without BPF_MAP_TYPE_RINGBUF
+	kf := detect(t)
+	kf.RingBuf = false
+
+	got, err := getModule(kf, Options{})
 
 	if err != nil {
 		t.Errorf("GetModule() error = %v", err)
@@ -52,38 +63,261 @@ func TestGetModule_Perf_Check(t *testing.T) {
 	if got.GetMap().GetMapType() != ebpf.PerfEventArray {
 		t.Errorf("GetModule().ebpfMap = %v, want %v", got.GetMap().GetMapType(), ebpf.PerfEventArray)
 	}
-
-	teardown()
 }
 
-// TestGetModule_Ring_Check tests the GetModule function for the map type PerfEventArray
+// TestGetModule_Ring_Check tests the GetModule function for the map type ArrayOfMaps of RingBuffer
 func TestGetModule_Ring_Check(t *testing.T) {
-	setup(5, 19, 0)
-	got, err := GetModule()
+	kf := detect(t)
+	got, err := getModule(kf, Options{})
 
 	if err != nil {
 		t.Errorf("GetModule() error = %v", err)
 	}
 
-	// this is intended to be a perf event as we are currently only supporting perf events
-	// once we were able to create an array ring buffer directly then we would need to change this test
-	if got.GetMap().GetMapType() != ebpf.PerfEventArray {
-		t.Errorf("GetModule().ebpfMap = %v, want %v", got.GetMap().GetMapType(), ebpf.PerfEventArray)
+	// the ring buffer transport is only selected if the running kernel supports it,
+	// otherwise the module falls back to the perf event array
+	want := ebpf.PerfEventArray
+	wantInner := ebpf.MapInfoType(-1)
+	if kf.RingBuf {
+		want = ebpf.ArrayOfMaps
+		wantInner = ebpf.RingBuffer
 	}
 
-	teardown()
+	if got.GetMap().GetMapType() != want {
+		t.Errorf("GetModule().ebpfMap = %v, want %v", got.GetMap().GetMapType(), want)
+	}
+
+	if got.GetMap().GetInnerMapType() != wantInner {
+		t.Errorf("GetModule().ebpfMap inner type = %v, want %v", got.GetMap().GetInnerMapType(), wantInner)
+	}
 }
 
-// TestGetModule_Kernel_Version_Err tests the GetModule function with invalid arguments
+// TestGetModule_Kernel_Version_Err tests the GetModule function with an unsupported kernel version
 func TestGetModule_Kernel_Version_Err(t *testing.T) {
-	setup("ab", "cd", "ef") // invalid arguments
+	kf := detect(t)
+	kf.Release = "4.19.0"
+	kf.Version = utils.KernelVersion(4, 19, 0)
+
+	_, err := getModule(kf, Options{})
+
+	if err == nil {
+		t.Errorf("getModule() error = %v, wantErr %v", err, "true")
+	}
+}
 
-	_, err := GetModule()
+// TestGetModule_BTF_Err tests the GetModule function on a kernel without BTF
+func TestGetModule_BTF_Err(t *testing.T) {
+	kf := detect(t)
+	kf.BTF = false
+
+	_, err := getModule(kf, Options{})
 
 	if err == nil {
-		t.Errorf("GetModule() error = %v, wantErr %v", err, "true")
+		t.Errorf("getModule() error = %v, wantErr %v", err, "true")
+	}
+}
+
+// TestGetModule_RawTracepoint tests the GetModule function with the raw tracepoint syscall backend
+func TestGetModule_RawTracepoint(t *testing.T) {
+	got, err := GetModule(Options{SyscallBackend: RawTracepointBackend})
+
+	if err != nil {
+		t.Fatalf("GetModule() error = %v", err)
+	}
+
+	// raw_syscalls sys_enter & sys_exit
+	if len(got.GetPrograms()) != 2 {
+		t.Errorf("GetModule() = %v, want %v", len(got.GetPrograms()), 2)
+	}
+
+	for _, prog := range got.GetPrograms() {
+		if prog.GetHook().GetHookType() != ebpf.RawTracepoint {
+			t.Errorf("GetModule() hook type = %v, want %v", prog.GetHook().GetHookType(), ebpf.RawTracepoint)
+		}
+	}
+}
+
+// TestGetModule_Fexit tests the fexit syscall backend, which falls back to the kprobes
+// on kernels without BPF trampolines.
+func TestGetModule_Fexit(t *testing.T) {
+	kf := detect(t)
+
+	got, err := getModule(kf, Options{SyscallBackend: FexitBackend})
+	if err != nil {
+		t.Fatalf("GetModule() error = %v", err)
+	}
+
+	if len(got.GetPrograms()) == 0 {
+		t.Fatalf("GetModule() = %v, want programs", len(got.GetPrograms()))
+	}
+
+	tracing := 0
+	for _, prog := range got.GetPrograms() {
+		switch prog.GetHook().GetHookType() {
+		case ebpf.Fentry, ebpf.Fexit:
+			tracing++
+		case ebpf.Kprobe, ebpf.Kretprobe:
+		default:
+			t.Errorf("GetModule() hook type = %v, want fentry, fexit or kprobes", prog.GetHook().GetHookType())
+		}
+	}
+
+	// either all syscalls are captured with fentry/fexit or all fallback to the kprobes
+	if tracing != 0 && tracing != len(got.GetPrograms()) {
+		t.Errorf("GetModule() tracing programs = %v, want 0 or %v", tracing, len(got.GetPrograms()))
+	}
+
+	if !kf.Tracing && tracing != 0 {
+		t.Errorf("GetModule() tracing programs = %v, want 0 without BPF trampolines", tracing)
+	}
+}
+
+// TestGetModule_Probes tests the GetModule function with a probe selection
+func TestGetModule_Probes(t *testing.T) {
+	got, err := GetModule(Options{Probes: ProbeSelection{"read", "sys_write_entry"}})
+	if err != nil {
+		t.Fatalf("GetModule() error = %v", err)
+	}
+
+	// the programs of the unselected probes are disabled, not removed
+	enabled := 0
+	for _, prog := range got.GetPrograms() {
+		if prog.GetShouldAttach() {
+			enabled++
+		}
+	}
+
+	if enabled != 4 {
+		t.Errorf("GetModule() enabled programs = %v, want %v", enabled, 4)
+	}
+
+	if _, err := GetModule(Options{Probes: ProbeSelection{"mmap"}}); err == nil {
+		t.Errorf("GetModule() error = %v, wantErr %v", err, true)
+	}
+}
+
+// TestParseSyscallBackend tests the ParseSyscallBackend function
+func TestParseSyscallBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    SyscallBackend
+		wantErr bool
+	}{
+		{name: "kprobe", arg: "kprobe", want: KprobeBackend},
+		{name: "raw tracepoint", arg: "raw_tracepoint", want: RawTracepointBackend},
+		{name: "fexit", arg: "fexit", want: FexitBackend},
+		{name: "unknown", arg: "fentry", want: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSyscallBackend(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSyscallBackend() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseSyscallBackend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_ringBufferEntries tests the ringBufferEntries function
+func Test_ringBufferEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		ncpu     int
+		pageSize int
+		want     uint32
+	}{
+		{name: "split between the cpus", size: 64 << 20, ncpu: 8, pageSize: 4096, want: 8 << 20},
+		{name: "rounded down to a power of two", size: 64 << 20, ncpu: 6, pageSize: 4096, want: 8 << 20},
+		{name: "many cpus", size: 64 << 20, ncpu: 1024, pageSize: 4096, want: 128 << 10},
+		{name: "large pages", size: 1 << 20, ncpu: 4, pageSize: 1 << 20, want: 1 << 20},
+		{name: "no cpu", size: 1 << 20, ncpu: 0, pageSize: 4096, want: 1 << 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ringBufferEntries(tt.size, tt.ncpu, tt.pageSize); got != tt.want {
+				t.Errorf("ringBufferEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_sizeEventMaps tests the sizeEventMaps function
+func Test_sizeEventMaps(t *testing.T) {
+	const budget = 64 << 20
+
+	tests := []struct {
+		name       string
+		useRingBuf bool
+		ncpu       int
+		wantCpus   int
+	}{
+		{name: "fewer cpus than ring buffers", useRingBuf: true, ncpu: 4, wantCpus: 4},
+		{name: "as many cpus as ring buffers", useRingBuf: true, ncpu: 16, wantCpus: 16},
+		{name: "more cpus than ring buffers", useRingBuf: true, ncpu: 20, wantCpus: 20},
+		{name: "perf event array", useRingBuf: false, ncpu: 4, wantCpus: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := eventMapsSpec(16)
+			entries := ringBufferEntries(budget, tt.ncpu, 4096)
+
+			if err := sizeEventMaps(spec, tt.useRingBuf, tt.ncpu, entries); err != nil {
+				t.Fatalf("sizeEventMaps() error = %v", err)
+			}
+
+			total := 0
+			for _, ms := range spec.Maps {
+				if ms.Type == cilium_ebpf.RingBuf {
+					total += int(ms.MaxEntries)
+				}
+			}
+
+			if total > budget {
+				t.Errorf("sizeEventMaps() ring buffers of %v bytes, want at most %v", total, budget)
+			}
+
+			if got := len(spec.Maps["events_ringbuf"].Contents); tt.useRingBuf && got != tt.wantCpus {
+				t.Errorf("sizeEventMaps() %v ring buffers in events_ringbuf, want %v", got, tt.wantCpus)
+			}
+
+			if got := total / int(entries); got != tt.wantCpus {
+				t.Errorf("sizeEventMaps() %v ring buffers sized, want %v", got, tt.wantCpus)
+			}
+		})
+	}
+}
+
+// eventMapsSpec returns the specs of the event maps declared with n ring buffers, like maps.h.
+func eventMapsSpec(n int) *cilium_ebpf.CollectionSpec {
+	spec := &cilium_ebpf.CollectionSpec{
+		Maps: map[string]*cilium_ebpf.MapSpec{
+			"pea_per_cpu_array": {Name: "pea_per_cpu_array", Type: cilium_ebpf.PerCPUArray, KeySize: 4, ValueSize: 4, MaxEntries: 1},
+			"events_ringbuf": {
+				Name:       "events_ringbuf",
+				Type:       cilium_ebpf.ArrayOfMaps,
+				KeySize:    4,
+				ValueSize:  4,
+				MaxEntries: uint32(n),
+				InnerMap:   &cilium_ebpf.MapSpec{Type: cilium_ebpf.RingBuf, MaxEntries: 4096},
+			},
+		},
+	}
 
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("erb_cpu%d", i)
+		spec.Maps[name] = &cilium_ebpf.MapSpec{Name: name, Type: cilium_ebpf.RingBuf, MaxEntries: 4096}
+		spec.Maps["events_ringbuf"].Contents = append(spec.Maps["events_ringbuf"].Contents, cilium_ebpf.MapKV{Key: uint32(i), Value: name})
 	}
 
-	teardown()
+	return spec
 }
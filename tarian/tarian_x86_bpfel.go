@@ -70,47 +70,124 @@ type tarianSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type tarianProgramSpecs struct {
-	TdfAcceptE   *ebpf.ProgramSpec `ebpf:"tdf_accept_e"`
-	TdfAcceptR   *ebpf.ProgramSpec `ebpf:"tdf_accept_r"`
-	TdfBindE     *ebpf.ProgramSpec `ebpf:"tdf_bind_e"`
-	TdfBindR     *ebpf.ProgramSpec `ebpf:"tdf_bind_r"`
-	TdfCloneE    *ebpf.ProgramSpec `ebpf:"tdf_clone_e"`
-	TdfCloneR    *ebpf.ProgramSpec `ebpf:"tdf_clone_r"`
-	TdfCloseE    *ebpf.ProgramSpec `ebpf:"tdf_close_e"`
-	TdfCloseR    *ebpf.ProgramSpec `ebpf:"tdf_close_r"`
-	TdfConnectE  *ebpf.ProgramSpec `ebpf:"tdf_connect_e"`
-	TdfConnectR  *ebpf.ProgramSpec `ebpf:"tdf_connect_r"`
-	TdfExecveE   *ebpf.ProgramSpec `ebpf:"tdf_execve_e"`
-	TdfExecveR   *ebpf.ProgramSpec `ebpf:"tdf_execve_r"`
-	TdfExecveatE *ebpf.ProgramSpec `ebpf:"tdf_execveat_e"`
-	TdfExecveatR *ebpf.ProgramSpec `ebpf:"tdf_execveat_r"`
-	TdfListenE   *ebpf.ProgramSpec `ebpf:"tdf_listen_e"`
-	TdfListenR   *ebpf.ProgramSpec `ebpf:"tdf_listen_r"`
-	TdfOpenE     *ebpf.ProgramSpec `ebpf:"tdf_open_e"`
-	TdfOpenR     *ebpf.ProgramSpec `ebpf:"tdf_open_r"`
-	TdfOpenat2E  *ebpf.ProgramSpec `ebpf:"tdf_openat2_e"`
-	TdfOpenat2R  *ebpf.ProgramSpec `ebpf:"tdf_openat2_r"`
-	TdfOpenatE   *ebpf.ProgramSpec `ebpf:"tdf_openat_e"`
-	TdfOpenatR   *ebpf.ProgramSpec `ebpf:"tdf_openat_r"`
-	TdfReadE     *ebpf.ProgramSpec `ebpf:"tdf_read_e"`
-	TdfReadR     *ebpf.ProgramSpec `ebpf:"tdf_read_r"`
-	TdfReadvE    *ebpf.ProgramSpec `ebpf:"tdf_readv_e"`
-	TdfReadvR    *ebpf.ProgramSpec `ebpf:"tdf_readv_r"`
-	TdfSocketE   *ebpf.ProgramSpec `ebpf:"tdf_socket_e"`
-	TdfSocketR   *ebpf.ProgramSpec `ebpf:"tdf_socket_r"`
-	TdfWriteE    *ebpf.ProgramSpec `ebpf:"tdf_write_e"`
-	TdfWriteR    *ebpf.ProgramSpec `ebpf:"tdf_write_r"`
-	TdfWritevE   *ebpf.ProgramSpec `ebpf:"tdf_writev_e"`
-	TdfWritevR   *ebpf.ProgramSpec `ebpf:"tdf_writev_r"`
+	TdfAccept4E         *ebpf.ProgramSpec `ebpf:"tdf_accept4_e"`
+	TdfAccept4R         *ebpf.ProgramSpec `ebpf:"tdf_accept4_r"`
+	TdfAcceptE          *ebpf.ProgramSpec `ebpf:"tdf_accept_e"`
+	TdfAcceptR          *ebpf.ProgramSpec `ebpf:"tdf_accept_r"`
+	TdfBindE            *ebpf.ProgramSpec `ebpf:"tdf_bind_e"`
+	TdfBindR            *ebpf.ProgramSpec `ebpf:"tdf_bind_r"`
+	TdfCloneE           *ebpf.ProgramSpec `ebpf:"tdf_clone_e"`
+	TdfCloneR           *ebpf.ProgramSpec `ebpf:"tdf_clone_r"`
+	TdfCloseE           *ebpf.ProgramSpec `ebpf:"tdf_close_e"`
+	TdfCloseR           *ebpf.ProgramSpec `ebpf:"tdf_close_r"`
+	TdfConnectE         *ebpf.ProgramSpec `ebpf:"tdf_connect_e"`
+	TdfConnectR         *ebpf.ProgramSpec `ebpf:"tdf_connect_r"`
+	TdfDup2E            *ebpf.ProgramSpec `ebpf:"tdf_dup2_e"`
+	TdfDup2R            *ebpf.ProgramSpec `ebpf:"tdf_dup2_r"`
+	TdfDup3E            *ebpf.ProgramSpec `ebpf:"tdf_dup3_e"`
+	TdfDup3R            *ebpf.ProgramSpec `ebpf:"tdf_dup3_r"`
+	TdfDupE             *ebpf.ProgramSpec `ebpf:"tdf_dup_e"`
+	TdfDupR             *ebpf.ProgramSpec `ebpf:"tdf_dup_r"`
+	TdfExecveE          *ebpf.ProgramSpec `ebpf:"tdf_execve_e"`
+	TdfExecveR          *ebpf.ProgramSpec `ebpf:"tdf_execve_r"`
+	TdfExecveatE        *ebpf.ProgramSpec `ebpf:"tdf_execveat_e"`
+	TdfExecveatR        *ebpf.ProgramSpec `ebpf:"tdf_execveat_r"`
+	TdfFcntlE           *ebpf.ProgramSpec `ebpf:"tdf_fcntl_e"`
+	TdfFcntlR           *ebpf.ProgramSpec `ebpf:"tdf_fcntl_r"`
+	TdfListenE          *ebpf.ProgramSpec `ebpf:"tdf_listen_e"`
+	TdfListenR          *ebpf.ProgramSpec `ebpf:"tdf_listen_r"`
+	TdfOpenE            *ebpf.ProgramSpec `ebpf:"tdf_open_e"`
+	TdfOpenR            *ebpf.ProgramSpec `ebpf:"tdf_open_r"`
+	TdfOpenat2E         *ebpf.ProgramSpec `ebpf:"tdf_openat2_e"`
+	TdfOpenat2R         *ebpf.ProgramSpec `ebpf:"tdf_openat2_r"`
+	TdfOpenatE          *ebpf.ProgramSpec `ebpf:"tdf_openat_e"`
+	TdfOpenatR          *ebpf.ProgramSpec `ebpf:"tdf_openat_r"`
+	TdfReadE            *ebpf.ProgramSpec `ebpf:"tdf_read_e"`
+	TdfReadR            *ebpf.ProgramSpec `ebpf:"tdf_read_r"`
+	TdfReadvE           *ebpf.ProgramSpec `ebpf:"tdf_readv_e"`
+	TdfReadvR           *ebpf.ProgramSpec `ebpf:"tdf_readv_r"`
+	TdfRtpAccept4E      *ebpf.ProgramSpec `ebpf:"tdf_rtp_accept4_e"`
+	TdfRtpAccept4R      *ebpf.ProgramSpec `ebpf:"tdf_rtp_accept4_r"`
+	TdfRtpAcceptE       *ebpf.ProgramSpec `ebpf:"tdf_rtp_accept_e"`
+	TdfRtpAcceptR       *ebpf.ProgramSpec `ebpf:"tdf_rtp_accept_r"`
+	TdfRtpBindE         *ebpf.ProgramSpec `ebpf:"tdf_rtp_bind_e"`
+	TdfRtpBindR         *ebpf.ProgramSpec `ebpf:"tdf_rtp_bind_r"`
+	TdfRtpCloneE        *ebpf.ProgramSpec `ebpf:"tdf_rtp_clone_e"`
+	TdfRtpCloneR        *ebpf.ProgramSpec `ebpf:"tdf_rtp_clone_r"`
+	TdfRtpCloseE        *ebpf.ProgramSpec `ebpf:"tdf_rtp_close_e"`
+	TdfRtpCloseR        *ebpf.ProgramSpec `ebpf:"tdf_rtp_close_r"`
+	TdfRtpConnectE      *ebpf.ProgramSpec `ebpf:"tdf_rtp_connect_e"`
+	TdfRtpConnectR      *ebpf.ProgramSpec `ebpf:"tdf_rtp_connect_r"`
+	TdfRtpDup2E         *ebpf.ProgramSpec `ebpf:"tdf_rtp_dup2_e"`
+	TdfRtpDup2R         *ebpf.ProgramSpec `ebpf:"tdf_rtp_dup2_r"`
+	TdfRtpDup3E         *ebpf.ProgramSpec `ebpf:"tdf_rtp_dup3_e"`
+	TdfRtpDup3R         *ebpf.ProgramSpec `ebpf:"tdf_rtp_dup3_r"`
+	TdfRtpDupE          *ebpf.ProgramSpec `ebpf:"tdf_rtp_dup_e"`
+	TdfRtpDupR          *ebpf.ProgramSpec `ebpf:"tdf_rtp_dup_r"`
+	TdfRtpExecveE       *ebpf.ProgramSpec `ebpf:"tdf_rtp_execve_e"`
+	TdfRtpExecveR       *ebpf.ProgramSpec `ebpf:"tdf_rtp_execve_r"`
+	TdfRtpExecveatE     *ebpf.ProgramSpec `ebpf:"tdf_rtp_execveat_e"`
+	TdfRtpExecveatR     *ebpf.ProgramSpec `ebpf:"tdf_rtp_execveat_r"`
+	TdfRtpFcntlE        *ebpf.ProgramSpec `ebpf:"tdf_rtp_fcntl_e"`
+	TdfRtpFcntlR        *ebpf.ProgramSpec `ebpf:"tdf_rtp_fcntl_r"`
+	TdfRtpListenE       *ebpf.ProgramSpec `ebpf:"tdf_rtp_listen_e"`
+	TdfRtpListenR       *ebpf.ProgramSpec `ebpf:"tdf_rtp_listen_r"`
+	TdfRtpOpenE         *ebpf.ProgramSpec `ebpf:"tdf_rtp_open_e"`
+	TdfRtpOpenR         *ebpf.ProgramSpec `ebpf:"tdf_rtp_open_r"`
+	TdfRtpOpenat2E      *ebpf.ProgramSpec `ebpf:"tdf_rtp_openat2_e"`
+	TdfRtpOpenat2R      *ebpf.ProgramSpec `ebpf:"tdf_rtp_openat2_r"`
+	TdfRtpOpenatE       *ebpf.ProgramSpec `ebpf:"tdf_rtp_openat_e"`
+	TdfRtpOpenatR       *ebpf.ProgramSpec `ebpf:"tdf_rtp_openat_r"`
+	TdfRtpReadE         *ebpf.ProgramSpec `ebpf:"tdf_rtp_read_e"`
+	TdfRtpReadR         *ebpf.ProgramSpec `ebpf:"tdf_rtp_read_r"`
+	TdfRtpReadvE        *ebpf.ProgramSpec `ebpf:"tdf_rtp_readv_e"`
+	TdfRtpReadvR        *ebpf.ProgramSpec `ebpf:"tdf_rtp_readv_r"`
+	TdfRtpSocketE       *ebpf.ProgramSpec `ebpf:"tdf_rtp_socket_e"`
+	TdfRtpSocketR       *ebpf.ProgramSpec `ebpf:"tdf_rtp_socket_r"`
+	TdfRtpWriteE        *ebpf.ProgramSpec `ebpf:"tdf_rtp_write_e"`
+	TdfRtpWriteR        *ebpf.ProgramSpec `ebpf:"tdf_rtp_write_r"`
+	TdfRtpWritevE       *ebpf.ProgramSpec `ebpf:"tdf_rtp_writev_e"`
+	TdfRtpWritevR       *ebpf.ProgramSpec `ebpf:"tdf_rtp_writev_r"`
+	TdfSchedProcessExec *ebpf.ProgramSpec `ebpf:"tdf_sched_process_exec"`
+	TdfSchedProcessExit *ebpf.ProgramSpec `ebpf:"tdf_sched_process_exit"`
+	TdfSchedProcessFork *ebpf.ProgramSpec `ebpf:"tdf_sched_process_fork"`
+	TdfSocketE          *ebpf.ProgramSpec `ebpf:"tdf_socket_e"`
+	TdfSocketR          *ebpf.ProgramSpec `ebpf:"tdf_socket_r"`
+	TdfSysEnter         *ebpf.ProgramSpec `ebpf:"tdf_sys_enter"`
+	TdfSysExit          *ebpf.ProgramSpec `ebpf:"tdf_sys_exit"`
+	TdfWriteE           *ebpf.ProgramSpec `ebpf:"tdf_write_e"`
+	TdfWriteR           *ebpf.ProgramSpec `ebpf:"tdf_write_r"`
+	TdfWritevE          *ebpf.ProgramSpec `ebpf:"tdf_writev_e"`
+	TdfWritevR          *ebpf.ProgramSpec `ebpf:"tdf_writev_r"`
 }
 
 // tarianMapSpecs contains maps before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type tarianMapSpecs struct {
+	ErbCpu0        *ebpf.MapSpec `ebpf:"erb_cpu0"`
+	ErbCpu1        *ebpf.MapSpec `ebpf:"erb_cpu1"`
+	ErbCpu10       *ebpf.MapSpec `ebpf:"erb_cpu10"`
+	ErbCpu11       *ebpf.MapSpec `ebpf:"erb_cpu11"`
+	ErbCpu12       *ebpf.MapSpec `ebpf:"erb_cpu12"`
+	ErbCpu13       *ebpf.MapSpec `ebpf:"erb_cpu13"`
+	ErbCpu14       *ebpf.MapSpec `ebpf:"erb_cpu14"`
+	ErbCpu15       *ebpf.MapSpec `ebpf:"erb_cpu15"`
+	ErbCpu2        *ebpf.MapSpec `ebpf:"erb_cpu2"`
+	ErbCpu3        *ebpf.MapSpec `ebpf:"erb_cpu3"`
+	ErbCpu4        *ebpf.MapSpec `ebpf:"erb_cpu4"`
+	ErbCpu5        *ebpf.MapSpec `ebpf:"erb_cpu5"`
+	ErbCpu6        *ebpf.MapSpec `ebpf:"erb_cpu6"`
+	ErbCpu7        *ebpf.MapSpec `ebpf:"erb_cpu7"`
+	ErbCpu8        *ebpf.MapSpec `ebpf:"erb_cpu8"`
+	ErbCpu9        *ebpf.MapSpec `ebpf:"erb_cpu9"`
 	Events         *ebpf.MapSpec `ebpf:"events"`
+	EventsRingbuf  *ebpf.MapSpec `ebpf:"events_ringbuf"`
+	PausedEvents   *ebpf.MapSpec `ebpf:"paused_events"`
 	PeaPerCpuArray *ebpf.MapSpec `ebpf:"pea_per_cpu_array"`
 	ScratchSpace   *ebpf.MapSpec `ebpf:"scratch_space"`
+	SysEnterCalls  *ebpf.MapSpec `ebpf:"sys_enter_calls"`
+	SysExitCalls   *ebpf.MapSpec `ebpf:"sys_exit_calls"`
 	TarianStats    *ebpf.MapSpec `ebpf:"tarian_stats"`
 }
 
@@ -133,17 +210,57 @@ func (o *tarianObjects) Close() error {
 //
 // It can be passed to loadTarianObjects or ebpf.CollectionSpec.LoadAndAssign.
 type tarianMaps struct {
+	ErbCpu0        *ebpf.Map `ebpf:"erb_cpu0"`
+	ErbCpu1        *ebpf.Map `ebpf:"erb_cpu1"`
+	ErbCpu10       *ebpf.Map `ebpf:"erb_cpu10"`
+	ErbCpu11       *ebpf.Map `ebpf:"erb_cpu11"`
+	ErbCpu12       *ebpf.Map `ebpf:"erb_cpu12"`
+	ErbCpu13       *ebpf.Map `ebpf:"erb_cpu13"`
+	ErbCpu14       *ebpf.Map `ebpf:"erb_cpu14"`
+	ErbCpu15       *ebpf.Map `ebpf:"erb_cpu15"`
+	ErbCpu2        *ebpf.Map `ebpf:"erb_cpu2"`
+	ErbCpu3        *ebpf.Map `ebpf:"erb_cpu3"`
+	ErbCpu4        *ebpf.Map `ebpf:"erb_cpu4"`
+	ErbCpu5        *ebpf.Map `ebpf:"erb_cpu5"`
+	ErbCpu6        *ebpf.Map `ebpf:"erb_cpu6"`
+	ErbCpu7        *ebpf.Map `ebpf:"erb_cpu7"`
+	ErbCpu8        *ebpf.Map `ebpf:"erb_cpu8"`
+	ErbCpu9        *ebpf.Map `ebpf:"erb_cpu9"`
 	Events         *ebpf.Map `ebpf:"events"`
+	EventsRingbuf  *ebpf.Map `ebpf:"events_ringbuf"`
+	PausedEvents   *ebpf.Map `ebpf:"paused_events"`
 	PeaPerCpuArray *ebpf.Map `ebpf:"pea_per_cpu_array"`
 	ScratchSpace   *ebpf.Map `ebpf:"scratch_space"`
+	SysEnterCalls  *ebpf.Map `ebpf:"sys_enter_calls"`
+	SysExitCalls   *ebpf.Map `ebpf:"sys_exit_calls"`
 	TarianStats    *ebpf.Map `ebpf:"tarian_stats"`
 }
 
 func (m *tarianMaps) Close() error {
 	return _TarianClose(
+		m.ErbCpu0,
+		m.ErbCpu1,
+		m.ErbCpu10,
+		m.ErbCpu11,
+		m.ErbCpu12,
+		m.ErbCpu13,
+		m.ErbCpu14,
+		m.ErbCpu15,
+		m.ErbCpu2,
+		m.ErbCpu3,
+		m.ErbCpu4,
+		m.ErbCpu5,
+		m.ErbCpu6,
+		m.ErbCpu7,
+		m.ErbCpu8,
+		m.ErbCpu9,
 		m.Events,
+		m.EventsRingbuf,
+		m.PausedEvents,
 		m.PeaPerCpuArray,
 		m.ScratchSpace,
+		m.SysEnterCalls,
+		m.SysExitCalls,
 		m.TarianStats,
 	)
 }
@@ -152,42 +269,101 @@ func (m *tarianMaps) Close() error {
 //
 // It can be passed to loadTarianObjects or ebpf.CollectionSpec.LoadAndAssign.
 type tarianPrograms struct {
-	TdfAcceptE   *ebpf.Program `ebpf:"tdf_accept_e"`
-	TdfAcceptR   *ebpf.Program `ebpf:"tdf_accept_r"`
-	TdfBindE     *ebpf.Program `ebpf:"tdf_bind_e"`
-	TdfBindR     *ebpf.Program `ebpf:"tdf_bind_r"`
-	TdfCloneE    *ebpf.Program `ebpf:"tdf_clone_e"`
-	TdfCloneR    *ebpf.Program `ebpf:"tdf_clone_r"`
-	TdfCloseE    *ebpf.Program `ebpf:"tdf_close_e"`
-	TdfCloseR    *ebpf.Program `ebpf:"tdf_close_r"`
-	TdfConnectE  *ebpf.Program `ebpf:"tdf_connect_e"`
-	TdfConnectR  *ebpf.Program `ebpf:"tdf_connect_r"`
-	TdfExecveE   *ebpf.Program `ebpf:"tdf_execve_e"`
-	TdfExecveR   *ebpf.Program `ebpf:"tdf_execve_r"`
-	TdfExecveatE *ebpf.Program `ebpf:"tdf_execveat_e"`
-	TdfExecveatR *ebpf.Program `ebpf:"tdf_execveat_r"`
-	TdfListenE   *ebpf.Program `ebpf:"tdf_listen_e"`
-	TdfListenR   *ebpf.Program `ebpf:"tdf_listen_r"`
-	TdfOpenE     *ebpf.Program `ebpf:"tdf_open_e"`
-	TdfOpenR     *ebpf.Program `ebpf:"tdf_open_r"`
-	TdfOpenat2E  *ebpf.Program `ebpf:"tdf_openat2_e"`
-	TdfOpenat2R  *ebpf.Program `ebpf:"tdf_openat2_r"`
-	TdfOpenatE   *ebpf.Program `ebpf:"tdf_openat_e"`
-	TdfOpenatR   *ebpf.Program `ebpf:"tdf_openat_r"`
-	TdfReadE     *ebpf.Program `ebpf:"tdf_read_e"`
-	TdfReadR     *ebpf.Program `ebpf:"tdf_read_r"`
-	TdfReadvE    *ebpf.Program `ebpf:"tdf_readv_e"`
-	TdfReadvR    *ebpf.Program `ebpf:"tdf_readv_r"`
-	TdfSocketE   *ebpf.Program `ebpf:"tdf_socket_e"`
-	TdfSocketR   *ebpf.Program `ebpf:"tdf_socket_r"`
-	TdfWriteE    *ebpf.Program `ebpf:"tdf_write_e"`
-	TdfWriteR    *ebpf.Program `ebpf:"tdf_write_r"`
-	TdfWritevE   *ebpf.Program `ebpf:"tdf_writev_e"`
-	TdfWritevR   *ebpf.Program `ebpf:"tdf_writev_r"`
+	TdfAccept4E         *ebpf.Program `ebpf:"tdf_accept4_e"`
+	TdfAccept4R         *ebpf.Program `ebpf:"tdf_accept4_r"`
+	TdfAcceptE          *ebpf.Program `ebpf:"tdf_accept_e"`
+	TdfAcceptR          *ebpf.Program `ebpf:"tdf_accept_r"`
+	TdfBindE            *ebpf.Program `ebpf:"tdf_bind_e"`
+	TdfBindR            *ebpf.Program `ebpf:"tdf_bind_r"`
+	TdfCloneE           *ebpf.Program `ebpf:"tdf_clone_e"`
+	TdfCloneR           *ebpf.Program `ebpf:"tdf_clone_r"`
+	TdfCloseE           *ebpf.Program `ebpf:"tdf_close_e"`
+	TdfCloseR           *ebpf.Program `ebpf:"tdf_close_r"`
+	TdfConnectE         *ebpf.Program `ebpf:"tdf_connect_e"`
+	TdfConnectR         *ebpf.Program `ebpf:"tdf_connect_r"`
+	TdfDup2E            *ebpf.Program `ebpf:"tdf_dup2_e"`
+	TdfDup2R            *ebpf.Program `ebpf:"tdf_dup2_r"`
+	TdfDup3E            *ebpf.Program `ebpf:"tdf_dup3_e"`
+	TdfDup3R            *ebpf.Program `ebpf:"tdf_dup3_r"`
+	TdfDupE             *ebpf.Program `ebpf:"tdf_dup_e"`
+	TdfDupR             *ebpf.Program `ebpf:"tdf_dup_r"`
+	TdfExecveE          *ebpf.Program `ebpf:"tdf_execve_e"`
+	TdfExecveR          *ebpf.Program `ebpf:"tdf_execve_r"`
+	TdfExecveatE        *ebpf.Program `ebpf:"tdf_execveat_e"`
+	TdfExecveatR        *ebpf.Program `ebpf:"tdf_execveat_r"`
+	TdfFcntlE           *ebpf.Program `ebpf:"tdf_fcntl_e"`
+	TdfFcntlR           *ebpf.Program `ebpf:"tdf_fcntl_r"`
+	TdfListenE          *ebpf.Program `ebpf:"tdf_listen_e"`
+	TdfListenR          *ebpf.Program `ebpf:"tdf_listen_r"`
+	TdfOpenE            *ebpf.Program `ebpf:"tdf_open_e"`
+	TdfOpenR            *ebpf.Program `ebpf:"tdf_open_r"`
+	TdfOpenat2E         *ebpf.Program `ebpf:"tdf_openat2_e"`
+	TdfOpenat2R         *ebpf.Program `ebpf:"tdf_openat2_r"`
+	TdfOpenatE          *ebpf.Program `ebpf:"tdf_openat_e"`
+	TdfOpenatR          *ebpf.Program `ebpf:"tdf_openat_r"`
+	TdfReadE            *ebpf.Program `ebpf:"tdf_read_e"`
+	TdfReadR            *ebpf.Program `ebpf:"tdf_read_r"`
+	TdfReadvE           *ebpf.Program `ebpf:"tdf_readv_e"`
+	TdfReadvR           *ebpf.Program `ebpf:"tdf_readv_r"`
+	TdfRtpAccept4E      *ebpf.Program `ebpf:"tdf_rtp_accept4_e"`
+	TdfRtpAccept4R      *ebpf.Program `ebpf:"tdf_rtp_accept4_r"`
+	TdfRtpAcceptE       *ebpf.Program `ebpf:"tdf_rtp_accept_e"`
+	TdfRtpAcceptR       *ebpf.Program `ebpf:"tdf_rtp_accept_r"`
+	TdfRtpBindE         *ebpf.Program `ebpf:"tdf_rtp_bind_e"`
+	TdfRtpBindR         *ebpf.Program `ebpf:"tdf_rtp_bind_r"`
+	TdfRtpCloneE        *ebpf.Program `ebpf:"tdf_rtp_clone_e"`
+	TdfRtpCloneR        *ebpf.Program `ebpf:"tdf_rtp_clone_r"`
+	TdfRtpCloseE        *ebpf.Program `ebpf:"tdf_rtp_close_e"`
+	TdfRtpCloseR        *ebpf.Program `ebpf:"tdf_rtp_close_r"`
+	TdfRtpConnectE      *ebpf.Program `ebpf:"tdf_rtp_connect_e"`
+	TdfRtpConnectR      *ebpf.Program `ebpf:"tdf_rtp_connect_r"`
+	TdfRtpDup2E         *ebpf.Program `ebpf:"tdf_rtp_dup2_e"`
+	TdfRtpDup2R         *ebpf.Program `ebpf:"tdf_rtp_dup2_r"`
+	TdfRtpDup3E         *ebpf.Program `ebpf:"tdf_rtp_dup3_e"`
+	TdfRtpDup3R         *ebpf.Program `ebpf:"tdf_rtp_dup3_r"`
+	TdfRtpDupE          *ebpf.Program `ebpf:"tdf_rtp_dup_e"`
+	TdfRtpDupR          *ebpf.Program `ebpf:"tdf_rtp_dup_r"`
+	TdfRtpExecveE       *ebpf.Program `ebpf:"tdf_rtp_execve_e"`
+	TdfRtpExecveR       *ebpf.Program `ebpf:"tdf_rtp_execve_r"`
+	TdfRtpExecveatE     *ebpf.Program `ebpf:"tdf_rtp_execveat_e"`
+	TdfRtpExecveatR     *ebpf.Program `ebpf:"tdf_rtp_execveat_r"`
+	TdfRtpFcntlE        *ebpf.Program `ebpf:"tdf_rtp_fcntl_e"`
+	TdfRtpFcntlR        *ebpf.Program `ebpf:"tdf_rtp_fcntl_r"`
+	TdfRtpListenE       *ebpf.Program `ebpf:"tdf_rtp_listen_e"`
+	TdfRtpListenR       *ebpf.Program `ebpf:"tdf_rtp_listen_r"`
+	TdfRtpOpenE         *ebpf.Program `ebpf:"tdf_rtp_open_e"`
+	TdfRtpOpenR         *ebpf.Program `ebpf:"tdf_rtp_open_r"`
+	TdfRtpOpenat2E      *ebpf.Program `ebpf:"tdf_rtp_openat2_e"`
+	TdfRtpOpenat2R      *ebpf.Program `ebpf:"tdf_rtp_openat2_r"`
+	TdfRtpOpenatE       *ebpf.Program `ebpf:"tdf_rtp_openat_e"`
+	TdfRtpOpenatR       *ebpf.Program `ebpf:"tdf_rtp_openat_r"`
+	TdfRtpReadE         *ebpf.Program `ebpf:"tdf_rtp_read_e"`
+	TdfRtpReadR         *ebpf.Program `ebpf:"tdf_rtp_read_r"`
+	TdfRtpReadvE        *ebpf.Program `ebpf:"tdf_rtp_readv_e"`
+	TdfRtpReadvR        *ebpf.Program `ebpf:"tdf_rtp_readv_r"`
+	TdfRtpSocketE       *ebpf.Program `ebpf:"tdf_rtp_socket_e"`
+	TdfRtpSocketR       *ebpf.Program `ebpf:"tdf_rtp_socket_r"`
+	TdfRtpWriteE        *ebpf.Program `ebpf:"tdf_rtp_write_e"`
+	TdfRtpWriteR        *ebpf.Program `ebpf:"tdf_rtp_write_r"`
+	TdfRtpWritevE       *ebpf.Program `ebpf:"tdf_rtp_writev_e"`
+	TdfRtpWritevR       *ebpf.Program `ebpf:"tdf_rtp_writev_r"`
+	TdfSchedProcessExec *ebpf.Program `ebpf:"tdf_sched_process_exec"`
+	TdfSchedProcessExit *ebpf.Program `ebpf:"tdf_sched_process_exit"`
+	TdfSchedProcessFork *ebpf.Program `ebpf:"tdf_sched_process_fork"`
+	TdfSocketE          *ebpf.Program `ebpf:"tdf_socket_e"`
+	TdfSocketR          *ebpf.Program `ebpf:"tdf_socket_r"`
+	TdfSysEnter         *ebpf.Program `ebpf:"tdf_sys_enter"`
+	TdfSysExit          *ebpf.Program `ebpf:"tdf_sys_exit"`
+	TdfWriteE           *ebpf.Program `ebpf:"tdf_write_e"`
+	TdfWriteR           *ebpf.Program `ebpf:"tdf_write_r"`
+	TdfWritevE          *ebpf.Program `ebpf:"tdf_writev_e"`
+	TdfWritevR          *ebpf.Program `ebpf:"tdf_writev_r"`
 }
 
 func (p *tarianPrograms) Close() error {
 	return _TarianClose(
+		p.TdfAccept4E,
+		p.TdfAccept4R,
 		p.TdfAcceptE,
 		p.TdfAcceptR,
 		p.TdfBindE,
@@ -198,10 +374,18 @@ func (p *tarianPrograms) Close() error {
 		p.TdfCloseR,
 		p.TdfConnectE,
 		p.TdfConnectR,
+		p.TdfDup2E,
+		p.TdfDup2R,
+		p.TdfDup3E,
+		p.TdfDup3R,
+		p.TdfDupE,
+		p.TdfDupR,
 		p.TdfExecveE,
 		p.TdfExecveR,
 		p.TdfExecveatE,
 		p.TdfExecveatR,
+		p.TdfFcntlE,
+		p.TdfFcntlR,
 		p.TdfListenE,
 		p.TdfListenR,
 		p.TdfOpenE,
@@ -214,8 +398,55 @@ func (p *tarianPrograms) Close() error {
 		p.TdfReadR,
 		p.TdfReadvE,
 		p.TdfReadvR,
+		p.TdfRtpAccept4E,
+		p.TdfRtpAccept4R,
+		p.TdfRtpAcceptE,
+		p.TdfRtpAcceptR,
+		p.TdfRtpBindE,
+		p.TdfRtpBindR,
+		p.TdfRtpCloneE,
+		p.TdfRtpCloneR,
+		p.TdfRtpCloseE,
+		p.TdfRtpCloseR,
+		p.TdfRtpConnectE,
+		p.TdfRtpConnectR,
+		p.TdfRtpDup2E,
+		p.TdfRtpDup2R,
+		p.TdfRtpDup3E,
+		p.TdfRtpDup3R,
+		p.TdfRtpDupE,
+		p.TdfRtpDupR,
+		p.TdfRtpExecveE,
+		p.TdfRtpExecveR,
+		p.TdfRtpExecveatE,
+		p.TdfRtpExecveatR,
+		p.TdfRtpFcntlE,
+		p.TdfRtpFcntlR,
+		p.TdfRtpListenE,
+		p.TdfRtpListenR,
+		p.TdfRtpOpenE,
+		p.TdfRtpOpenR,
+		p.TdfRtpOpenat2E,
+		p.TdfRtpOpenat2R,
+		p.TdfRtpOpenatE,
+		p.TdfRtpOpenatR,
+		p.TdfRtpReadE,
+		p.TdfRtpReadR,
+		p.TdfRtpReadvE,
+		p.TdfRtpReadvR,
+		p.TdfRtpSocketE,
+		p.TdfRtpSocketR,
+		p.TdfRtpWriteE,
+		p.TdfRtpWriteR,
+		p.TdfRtpWritevE,
+		p.TdfRtpWritevR,
+		p.TdfSchedProcessExec,
+		p.TdfSchedProcessExit,
+		p.TdfSchedProcessFork,
 		p.TdfSocketE,
 		p.TdfSocketR,
+		p.TdfSysEnter,
+		p.TdfSysExit,
 		p.TdfWriteE,
 		p.TdfWriteR,
 		p.TdfWritevE,
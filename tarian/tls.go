@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"debug/buildinfo"
+	"debug/elf"
+	"fmt"
+	"go/version"
+	"runtime"
+
+	"github.com/cilium/ebpf/link"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"golang.org/x/arch/arm64/arm64asm"
+	"golang.org/x/arch/x86/x86asm"
+)
+
+const (
+	goTlsWrite = "crypto/tls.(*Conn).Write"
+	goTlsRead  = "crypto/tls.(*Conn).Read"
+)
+
+// tlsPrograms holds the programs capturing the plaintext of the tls libraries.
+var tlsPrograms = []string{
+	"tdf_uprobe_ssl_write",
+	"tdf_uprobe_ssl_read",
+	"tdf_uretprobe_ssl_read",
+	"tdf_uprobe_go_tls_write",
+	"tdf_uprobe_go_tls_read",
+	"tdf_uprobe_go_tls_read_ret",
+}
+
+// goRegisterABI maps the architecture to the first Go release passing the arguments in
+// registers, which the Go crypto/tls programs read.
+var goRegisterABI = map[string]string{
+	"amd64": "go1.17",
+	"arm64": "go1.18",
+}
+
+// addTls adds the providers of the OpenSSL libraries and the Go executables linking crypto/tls.
+func (d *uprobeDiscovery) addTls() error {
+	if _, ok := goRegisterABI[runtime.GOARCH]; !ok {
+		return fmt.Errorf("unsupported architecture %s", runtime.GOARCH)
+	}
+
+	d.libraries["libssl.so"] = d.libsslPrograms
+	d.executables = append(d.executables, d.goTlsPrograms)
+
+	return nil
+}
+
+// libsslPrograms returns the programs of the libssl library at path.
+func (d *uprobeDiscovery) libsslPrograms(path string) ([]*ebpf.ProgramInfo, error) {
+	return []*ebpf.ProgramInfo{
+		d.program("ssl_write", "tdf_uprobe_ssl_write", ebpf.NewHookInfo().Uprobe(path, "SSL_write")),
+		d.program("ssl_read", "tdf_uprobe_ssl_read", ebpf.NewHookInfo().Uprobe(path, "SSL_read")),
+		d.program("ssl_read", "tdf_uretprobe_ssl_read", ebpf.NewHookInfo().Uretprobe(path, "SSL_read")),
+	}, nil
+}
+
+// goTlsPrograms returns the programs of the Go executable at path, none if it does
+// not link crypto/tls. The returns of Read are probed on its RET instructions.
+func (d *uprobeDiscovery) goTlsPrograms(path string) ([]*ebpf.ProgramInfo, error) {
+	bi, err := buildinfo.ReadFile(path)
+	if err != nil {
+		// not a Go executable
+		return nil, nil
+	}
+
+	if version.Compare(bi.GoVersion, goRegisterABI[runtime.GOARCH]) < 0 {
+		return nil, nil
+	}
+
+	f, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// crypto/tls is not linked, or not used and removed by the linker
+	_, write, err := elfFunction(f, goTlsWrite)
+	if err != nil {
+		return nil, nil
+	}
+
+	code, read, err := elfFunction(f, goTlsRead)
+	if err != nil {
+		return nil, nil
+	}
+
+	rets, err := retOffsets(code, runtime.GOARCH)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// the functions are probed by address, the stripped executables have no symbol table
+	progs := []*ebpf.ProgramInfo{
+		d.program("go_tls_write", "tdf_uprobe_go_tls_write", ebpf.NewHookInfo().Uprobe(path, goTlsWrite, &link.UprobeOptions{Address: write})),
+		d.program("go_tls_read", "tdf_uprobe_go_tls_read", ebpf.NewHookInfo().Uprobe(path, goTlsRead, &link.UprobeOptions{Address: read})),
+	}
+
+	for _, off := range rets {
+		progs = append(progs, d.program("go_tls_read", "tdf_uprobe_go_tls_read_ret",
+			ebpf.NewHookInfo().Uprobe(path, goTlsRead, &link.UprobeOptions{Address: read, Offset: off})))
+	}
+
+	return progs, nil
+}
+
+// retOffsets returns the offsets of the RET instructions in the machine code of a function.
+func retOffsets(code []byte, arch string) ([]uint64, error) {
+	var offsets []uint64
+
+	switch arch {
+	case "amd64":
+		for i := 0; i < len(code); {
+			// a misdecoded instruction would shift the following ones, so decoding
+			// stops on the first invalid or truncated instruction
+			inst, err := x86asm.Decode(code[i:], 64)
+			if err == nil && inst.Op == 0 {
+				err = fmt.Errorf("invalid instruction % x", code[i:min(i+inst.Len, len(code))])
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode instruction at %#x: %w", i, err)
+			}
+
+			if inst.Op == x86asm.RET {
+				offsets = append(offsets, uint64(i))
+			}
+
+			i += inst.Len
+		}
+	case "arm64":
+		// fixed size instructions, undecodable words are padding or data
+		for i := 0; i+4 <= len(code); i += 4 {
+			inst, err := arm64asm.Decode(code[i : i+4])
+			if err == nil && inst.Op == arm64asm.RET {
+				offsets = append(offsets, uint64(i))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported architecture %s", arch)
+	}
+
+	return offsets, nil
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test_retOffsets tests the retOffsets function
+func Test_retOffsets(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    []byte
+		arch    string
+		want    []uint64
+		wantErr bool
+	}{
+		{
+			name: "amd64",
+			// push rbp; ret; mov rax, rbx; ret; int3
+			code: []byte{0x55, 0xc3, 0x48, 0x89, 0xd8, 0xc3, 0xcc},
+			arch: "amd64",
+			want: []uint64{1, 5},
+		},
+		{
+			name:    "amd64 truncated instruction",
+			code:    []byte{0xc3, 0x48, 0x89},
+			arch:    "amd64",
+			wantErr: true,
+		},
+		{
+			name: "arm64",
+			// nop; ret; nop; ret
+			code: []byte{0x1f, 0x20, 0x03, 0xd5, 0xc0, 0x03, 0x5f, 0xd6, 0x1f, 0x20, 0x03, 0xd5, 0xc0, 0x03, 0x5f, 0xd6},
+			arch: "arm64",
+			want: []uint64{4, 12},
+		},
+		{
+			name: "arm64 padding",
+			// ret; udf
+			code: []byte{0xc0, 0x03, 0x5f, 0xd6, 0x00, 0x00, 0x00, 0x00},
+			arch: "arm64",
+			want: []uint64{0},
+		},
+		{
+			name:    "unsupported architecture",
+			code:    []byte{0xc3},
+			arch:    "riscv64",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := retOffsets(tt.code, tt.arch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("retOffsets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("retOffsets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"debug/gosym"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cilium_ebpf "github.com/cilium/ebpf"
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+	"github.com/intelops/tarian-detector/pkg/k8s"
+	"golang.org/x/sys/unix"
+)
+
+// uprobeProvider returns the programs to attach to the executable or shared library at
+// path, none if it does not apply to the file.
+type uprobeProvider func(path string) ([]*ebpf.ProgramInfo, error)
+
+// fileKey identifies a file independently of the mount namespace it is found through.
+type fileKey struct {
+	dev uint64
+	ino uint64
+}
+
+// uprobeDiscovery finds the executables and the shared libraries of the running
+// processes, through their root so that those of the containers are found too,
+// and returns the programs of the providers applying to them. The programs of the
+// files no longer mapped by any process are pruned.
+type uprobeDiscovery struct {
+	programs    map[string]*cilium_ebpf.Program
+	sel         ProbeSelection
+	libraries   map[string]uprobeProvider // keyed by the prefix of the library file name
+	executables []uprobeProvider
+	seen        map[fileKey][]*ebpf.ProgramInfo // files already inspected, with their programs if any
+	stale       []*ebpf.ProgramInfo             // programs of the files no longer mapped, to be pruned
+}
+
+// addUprobes loads the uprobe programs of the enabled features and adds their discovery
+// to the module. The files are inspected when the module is prepared and on every
+// refresh of its handler, which detaches the programs of the files no longer mapped.
+func addUprobes(m *ebpf.Module, objs *tarianObjects, useRingBuf bool, opts Options) error {
+	spec, err := loadTarianUprobe()
+	if err != nil {
+		return err
+	}
+
+	if !opts.TlsCapture {
+		for _, name := range tlsPrograms {
+			delete(spec.Programs, name)
+		}
+	}
+
+	if !opts.ShellCapture {
+		delete(spec.Programs, readlineProgram)
+	}
+
+	coll, err := loadSharedCollection(spec, objs, useRingBuf)
+	if err != nil {
+		return err
+	}
+
+	d := &uprobeDiscovery{
+		programs:  coll.Programs,
+		sel:       opts.Probes,
+		libraries: make(map[string]uprobeProvider),
+		seen:      make(map[fileKey][]*ebpf.ProgramInfo),
+	}
+
+	if opts.TlsCapture {
+		if err := d.addTls(); err != nil {
+			return err
+		}
+	}
+
+	if opts.ShellCapture {
+		d.addReadline()
+	}
+
+	m.AddProgramSource(d.discover)
+	m.AddProgramPruner(d.prune)
+
+	return nil
+}
+
+// discover returns the programs for the files of the processes started since its
+// previous call. Processes exiting while inspected are skipped. The programs of the
+// files no longer mapped by any process are set aside for prune.
+func (d *uprobeDiscovery) discover() ([]*ebpf.ProgramInfo, error) {
+	entries, err := os.ReadDir(k8s.HostProcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var progs []*ebpf.ProgramInfo
+	mapped := make(map[fileKey]bool)
+	for _, entry := range entries {
+		pid, err := strconv.ParseUint(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		maps, err := os.ReadFile(filepath.Join(k8s.HostProcDir, entry.Name(), "maps"))
+		if err != nil {
+			continue
+		}
+
+		for _, lib := range mappedFiles(maps) {
+			provider := d.library(filepath.Base(lib))
+			if provider == nil {
+				continue
+			}
+
+			path := k8s.ContainerPath(uint32(pid), lib)
+			key, ok := d.visit(path, mapped)
+			if !ok {
+				continue
+			}
+
+			lp, err := provider(path)
+			if err != nil {
+				continue
+			}
+
+			d.seen[key] = append(d.seen[key], lp...)
+			progs = append(progs, lp...)
+		}
+
+		exe, err := k8s.ProcsExecutable(uint32(pid))
+		if err != nil {
+			continue
+		}
+
+		key, ok := d.visit(exe, mapped)
+		if !ok {
+			continue
+		}
+
+		for _, provider := range d.executables {
+			ep, err := provider(exe)
+			if err != nil {
+				continue
+			}
+
+			d.seen[key] = append(d.seen[key], ep...)
+			progs = append(progs, ep...)
+		}
+	}
+
+	for key, fileProgs := range d.seen {
+		if !mapped[key] {
+			delete(d.seen, key)
+			d.stale = append(d.stale, fileProgs...)
+		}
+	}
+
+	return progs, nil
+}
+
+// prune returns the programs of the files no longer mapped by any process found by
+// discover since its previous call.
+func (d *uprobeDiscovery) prune() []*ebpf.ProgramInfo {
+	stale := d.stale
+	d.stale = nil
+
+	return stale
+}
+
+// library returns the provider of the library named name, nil if there is none.
+func (d *uprobeDiscovery) library(name string) uprobeProvider {
+	for prefix, provider := range d.libraries {
+		if strings.HasPrefix(name, prefix) {
+			return provider
+		}
+	}
+
+	return nil
+}
+
+// visit returns the key of the file at path and whether it was not inspected yet, and
+// marks it as inspected and as mapped.
+func (d *uprobeDiscovery) visit(path string, mapped map[fileKey]bool) (fileKey, bool) {
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return fileKey{}, false
+	}
+
+	key := fileKey{dev: uint64(st.Dev), ino: st.Ino}
+	mapped[key] = true
+	if _, ok := d.seen[key]; ok {
+		return key, false
+	}
+
+	d.seen[key] = nil
+
+	return key, true
+}
+
+// program returns the program called name, attached to hook, of the probe named probe.
+func (d *uprobeDiscovery) program(probe string, name string, hook *ebpf.HookInfo) *ebpf.ProgramInfo {
+	return newProgram(d.sel, probe, d.programs[name], hook)
+}
+
+// mappedFiles returns the paths of the files mapped executable in the /proc/<pid>/maps content.
+func mappedFiles(maps []byte) []string {
+	var paths []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(maps))
+	for scanner.Scan() {
+		// address perms offset dev inode pathname
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || !strings.Contains(fields[1], "x") {
+			continue
+		}
+
+		// anonymous and special mappings, e.g. [vdso], are not files
+		path := strings.Join(fields[5:], " ")
+		if !strings.HasPrefix(path, "/") || seen[path] {
+			continue
+		}
+
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+// elfFunction returns the machine code of the function symbol name and its offset in the
+// file, the address of its uprobes. The function is looked up in the symbol table, or in
+// the function table of the Go runtime, .gopclntab, kept by the stripped Go executables.
+func elfFunction(f *elf.File, name string) ([]byte, uint64, error) {
+	start, end, err := symbolRange(f, name)
+	if err != nil {
+		var gerr error
+		if start, end, gerr = goFunctionRange(f, name); gerr != nil {
+			return nil, 0, fmt.Errorf("%w, %w", err, gerr)
+		}
+	}
+
+	var sect *elf.Section
+	for _, s := range f.Sections {
+		if s.Type == elf.SHT_PROGBITS && s.Flags&elf.SHF_EXECINSTR != 0 && s.Addr <= start && end <= s.Addr+s.Size {
+			sect = s
+			break
+		}
+	}
+
+	if sect == nil {
+		return nil, 0, fmt.Errorf("symbol %s: no section holds %#x-%#x", name, start, end)
+	}
+
+	code := make([]byte, end-start)
+	if _, err := sect.ReadAt(code, int64(start-sect.Addr)); err != nil {
+		return nil, 0, fmt.Errorf("symbol %s: %w", name, err)
+	}
+
+	// the offset of the function in its executable segment
+	offset := start - sect.Addr + sect.Offset
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_LOAD && prog.Flags&elf.PF_X != 0 && prog.Vaddr <= start && start < prog.Vaddr+prog.Memsz {
+			offset = start - prog.Vaddr + prog.Off
+			break
+		}
+	}
+
+	return code, offset, nil
+}
+
+// symbolRange returns the addresses of the start and the end of the function symbol name,
+// from the symbol table.
+func symbolRange(f *elf.File, name string) (uint64, uint64, error) {
+	syms, err := f.Symbols()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, sym := range syms {
+		if sym.Name == name && elf.ST_TYPE(sym.Info) == elf.STT_FUNC {
+			return sym.Value, sym.Value + sym.Size, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("symbol %s not found", name)
+}
+
+// goFunctionRange returns the addresses of the start and the end of the Go function name,
+// from the function table of the Go runtime. It is in .data.rel.ro.gopclntab for the
+// position independent executables.
+func goFunctionRange(f *elf.File, name string) (uint64, uint64, error) {
+	pclntab := f.Section(".gopclntab")
+	if pclntab == nil {
+		pclntab = f.Section(".data.rel.ro.gopclntab")
+	}
+
+	text := f.Section(".text")
+	if pclntab == nil || text == nil {
+		return 0, 0, fmt.Errorf("no Go function table")
+	}
+
+	data, err := pclntab.Data()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.Addr))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fn := table.LookupFunc(name)
+	if fn == nil {
+		return 0, 0, fmt.Errorf("function %s not found in the Go function table", name)
+	}
+
+	return fn.Entry, fn.End, nil
+}
+
+// hasFunction reports whether the executable at path defines the function symbol name,
+// in its symbol table or, for the stripped executables, in its dynamic symbol table.
+func hasFunction(path string, name string) (bool, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	for _, symbols := range []func() ([]elf.Symbol, error){f.Symbols, f.DynamicSymbols} {
+		syms, err := symbols()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			// undefined symbols are imported from a shared library
+			if sym.Name == name && elf.ST_TYPE(sym.Info) == elf.STT_FUNC && sym.Section != elf.SHN_UNDEF {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
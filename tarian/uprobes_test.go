@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of Tarian & the Organization created Tarian
+
+package tarian
+
+import (
+	"debug/elf"
+	"os"
+	"reflect"
+	"testing"
+
+	ebpf "github.com/intelops/tarian-detector/pkg/eBPF"
+)
+
+// Test_mappedFiles tests the mappedFiles function
+func Test_mappedFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		maps string
+		want []string
+	}{
+		{
+			name: "executable mapping",
+			maps: `55d4c2a00000-55d4c2a28000 r--p 00000000 08:01 1048602                    /usr/bin/curl
+7f2b1c400000-7f2b1c45b000 r--p 00000000 08:01 1057270                    /usr/lib/x86_64-linux-gnu/libssl.so.3
+7f2b1c45b000-7f2b1c4b7000 r-xp 0005b000 08:01 1057270                    /usr/lib/x86_64-linux-gnu/libssl.so.3
+7f2b1c4b7000-7f2b1c4d4000 r--p 000b7000 08:01 1057270                    /usr/lib/x86_64-linux-gnu/libssl.so.3
+7f2b1c600000-7f2b1c628000 r-xp 00028000 08:01 1057120                    /usr/lib/x86_64-linux-gnu/libc.so.6
+7ffd5a1e8000-7ffd5a209000 rw-p 00000000 00:00 0                          [stack]
+`,
+			want: []string{"/usr/lib/x86_64-linux-gnu/libssl.so.3", "/usr/lib/x86_64-linux-gnu/libc.so.6"},
+		},
+		{
+			name: "multiple libraries",
+			maps: `7f2b1c45b000-7f2b1c4b7000 r-xp 0005b000 08:01 1057270                    /usr/lib/libssl.so.1.1
+7f2b1d45b000-7f2b1d4b7000 r-xp 0005b000 08:01 1057271                    /opt/app lib/libssl.so.3
+`,
+			want: []string{"/usr/lib/libssl.so.1.1", "/opt/app lib/libssl.so.3"},
+		},
+		{
+			name: "no files",
+			maps: `7f2b1c800000-7f2b1c828000 r-xp 00000000 00:00 0
+7ffd5a3f2000-7ffd5a3f4000 r-xp 00000000 00:00 0                          [vdso]
+7f2b1c900000-7f2b1c928000 rw-p 00000000 08:01 1057121                    /usr/lib/locale/C.utf8/LC_CTYPE
+`,
+			want: nil,
+		},
+		{
+			name: "empty",
+			maps: "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mappedFiles([]byte(tt.maps)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mappedFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_uprobeDiscovery_library tests the library function
+func Test_uprobeDiscovery_library(t *testing.T) {
+	d := &uprobeDiscovery{libraries: make(map[string]uprobeProvider)}
+	d.libraries["libssl.so"] = func(path string) ([]*ebpf.ProgramInfo, error) { return nil, nil }
+
+	tests := []struct {
+		name string
+		lib  string
+		want bool
+	}{
+		{name: "versioned library", lib: "libssl.so.3", want: true},
+		{name: "unversioned library", lib: "libssl.so", want: true},
+		{name: "other library", lib: "libcrypto.so.3", want: false},
+		{name: "similar name", lib: "libssl3.so", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.library(tt.lib) != nil; got != tt.want {
+				t.Errorf("uprobeDiscovery.library() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_elfFunction tests the elfFunction function on the test executable
+func Test_elfFunction(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable() error = %v", err)
+	}
+
+	f, err := elf.Open(exe)
+	if err != nil {
+		t.Fatalf("elf.Open() error = %v", err)
+	}
+	defer f.Close()
+
+	tests := []struct {
+		name    string
+		fn      string
+		wantErr bool
+	}{
+		{name: "function", fn: "testing.(*T).Run"},
+		{name: "unknown function", fn: "main.unknown", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, offset, err := elfFunction(f, tt.fn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("elfFunction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && (len(code) == 0 || offset == 0) {
+				t.Errorf("elfFunction() = %d bytes at %#x, want the code of %s", len(code), offset, tt.fn)
+			}
+		})
+	}
+}
+
+// Test_goFunctionRange tests the goFunctionRange function on the test executable, stripped
+// of its symbol table by go test
+func Test_goFunctionRange(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable() error = %v", err)
+	}
+
+	f, err := elf.Open(exe)
+	if err != nil {
+		t.Fatalf("elf.Open() error = %v", err)
+	}
+	defer f.Close()
+
+	text := f.Section(".text")
+
+	start, end, err := goFunctionRange(f, "testing.(*T).Run")
+	if err != nil {
+		t.Fatalf("goFunctionRange() error = %v", err)
+	}
+
+	if start < text.Addr || end <= start || end > text.Addr+text.Size {
+		t.Errorf("goFunctionRange() = %#x-%#x, want a function of .text %#x-%#x", start, end, text.Addr, text.Addr+text.Size)
+	}
+
+	if _, _, err := goFunctionRange(f, "main.unknown"); err == nil {
+		t.Errorf("goFunctionRange() error = %v, wantErr %v", err, true)
+	}
+}